@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"time"
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type AchievementCommentMock struct {
+	mock.Mock
+}
+
+func (m *AchievementCommentMock) Create(comment *models.AchievementComment) error {
+	return m.Called(comment).Error(0)
+}
+
+func (m *AchievementCommentMock) GetByID(id string) (*models.AchievementComment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AchievementComment), args.Error(1)
+}
+
+func (m *AchievementCommentMock) GetByMongoID(mongoID string) ([]*models.AchievementComment, error) {
+	args := m.Called(mongoID)
+	return args.Get(0).([]*models.AchievementComment), args.Error(1)
+}
+
+func (m *AchievementCommentMock) UpdateBody(id string, body string) error {
+	return m.Called(id, body).Error(0)
+}
+
+func (m *AchievementCommentMock) GetLastRead(mongoID string, userID string) (*time.Time, error) {
+	args := m.Called(mongoID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *AchievementCommentMock) MarkRead(mongoID string, userID string, at time.Time) error {
+	return m.Called(mongoID, userID, at).Error(0)
+}
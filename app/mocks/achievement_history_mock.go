@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type AchievementHistoryMock struct {
+	mock.Mock
+}
+
+func (m *AchievementHistoryMock) Create(h *models.AchievementStatusHistory) error {
+	return m.Called(h).Error(0)
+}
+
+func (m *AchievementHistoryMock) GetByMongoID(mongoID string) ([]*models.AchievementStatusHistory, error) {
+	args := m.Called(mongoID)
+	return args.Get(0).([]*models.AchievementStatusHistory), args.Error(1)
+}
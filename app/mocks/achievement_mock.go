@@ -10,8 +10,8 @@ type AchievementRefMock struct {
 	mock.Mock
 }
 
-func (m *AchievementRefMock) UpdateStatusByMongoID(mongoID string, status string, submittedAt *time.Time) error {
-	args := m.Called(mongoID, status, submittedAt)
+func (m *AchievementRefMock) UpdateStatusByMongoID(mongoID, studentID string, status string, submittedAt *time.Time) error {
+	args := m.Called(mongoID, studentID, status, submittedAt)
 	return args.Error(0)
 }
 
@@ -32,6 +32,9 @@ func (m *AchievementRefMock) SoftDeleteByMongoID(mongoID string) error {
 
 func (m *AchievementRefMock) GetByMongoID(mongoID string) (*models.AchievementReference, error) {
 	args := m.Called(mongoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*models.AchievementReference), args.Error(1)
 }
 
@@ -50,15 +53,135 @@ func (m *AchievementRefMock) GetAll() ([]*models.AchievementReference, error) {
 	return args.Get(0).([]*models.AchievementReference), args.Error(1)
 }
 
-func (m *AchievementRefMock) VerifyByMongoID(mongoID string, vBy string, vAt time.Time) error {
-	return m.Called(mongoID, vBy, vAt).Error(0)
+func (m *AchievementRefMock) VerifyByMongoID(mongoID string, studentID string, vBy string, vAt time.Time, points int) error {
+	return m.Called(mongoID, studentID, vBy, vAt, points).Error(0)
 }
 
-func (m *AchievementRefMock) RejectByMongoID(mongoID string, note string) error {
-	return m.Called(mongoID, note).Error(0)
+func (m *AchievementRefMock) RejectByMongoID(mongoID string, studentID string, note string) error {
+	return m.Called(mongoID, studentID, note).Error(0)
 }
 
-func (m *AchievementRefMock) GetHistoryByMongoID(mongoID string) ([]*models.AchievementReference, error) {
+func (m *AchievementRefMock) RequestRevisionByMongoID(mongoID string, studentID string, feedback models.RevisionFeedback) error {
+	return m.Called(mongoID, studentID, feedback).Error(0)
+}
+
+func (m *AchievementRefMock) UpdateScoringByMongoID(mongoID, studentID string, suggestedPoints int, justification *string) error {
+	return m.Called(mongoID, studentID, suggestedPoints, justification).Error(0)
+}
+
+func (m *AchievementRefMock) UpdateAwardedPoints(mongoID, studentID string, points int) error {
+	return m.Called(mongoID, studentID, points).Error(0)
+}
+
+func (m *AchievementRefMock) GetVerifiedByFilter(from, to *time.Time, programStudy string) ([]*models.AchievementReference, error) {
+	args := m.Called(from, to, programStudy)
+	return args.Get(0).([]*models.AchievementReference), args.Error(1)
+}
+
+func (m *AchievementRefMock) GetPendingVerifications(programStudy string, overdueOnly, escalatedOnly bool) ([]*models.PendingVerification, error) {
+	args := m.Called(programStudy, overdueOnly, escalatedOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PendingVerification), args.Error(1)
+}
+
+func (m *AchievementRefMock) MarkOverdueByMongoIDs(mongoIDs []string, at time.Time) error {
+	return m.Called(mongoIDs, at).Error(0)
+}
+
+func (m *AchievementRefMock) MarkEscalatedByMongoIDs(mongoIDs []string, at time.Time) error {
+	return m.Called(mongoIDs, at).Error(0)
+}
+
+func (m *AchievementRefMock) GetVerifiedCertifications(mongoIDs []string, programStudy, advisorID string) ([]*models.CertificationValidity, error) {
+	args := m.Called(mongoIDs, programStudy, advisorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CertificationValidity), args.Error(1)
+}
+
+func (m *AchievementRefMock) MarkExpiredByMongoIDs(mongoIDs []string, at time.Time) error {
+	return m.Called(mongoIDs, at).Error(0)
+}
+
+func (m *AchievementRefMock) AdvanceApprovalStage(mongoID, studentID string, fromStage int) error {
+	return m.Called(mongoID, studentID, fromStage).Error(0)
+}
+
+func (m *AchievementRefMock) GetDeleted(studentID string, deletedBefore *time.Time) ([]*models.AchievementReference, error) {
+	args := m.Called(studentID, deletedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AchievementReference), args.Error(1)
+}
+
+func (m *AchievementRefMock) RestoreByMongoID(mongoID string) (string, error) {
+	args := m.Called(mongoID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *AchievementRefMock) HardDeleteWithOutbox(mongoID string) (string, error) {
 	args := m.Called(mongoID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *AchievementRefMock) GetByMongoIDAndStudent(mongoID, studentID string) (*models.AchievementReference, error) {
+	args := m.Called(mongoID, studentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AchievementReference), args.Error(1)
+}
+
+func (m *AchievementRefMock) GetTeamByMongoID(mongoID string) ([]*models.AchievementReference, error) {
+	args := m.Called(mongoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AchievementReference), args.Error(1)
+}
+
+func (m *AchievementRefMock) CreateWithOutbox(refs []*models.AchievementReference, outboxID string) error {
+	return m.Called(refs, outboxID).Error(0)
+}
+
+func (m *AchievementRefMock) SoftDeleteWithOutbox(mongoID string) (string, error) {
+	args := m.Called(mongoID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *AchievementRefMock) RestoreWithOutbox(mongoID string) (string, string, error) {
+	args := m.Called(mongoID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *AchievementRefMock) GetAllWithDeleted() ([]*models.AchievementReference, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*models.AchievementReference), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *AchievementRefMock) List(filter models.AchievementListFilter) ([]*models.AchievementReference, int, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.AchievementReference), args.Int(1), args.Error(2)
+}
+
+func (m *AchievementRefMock) SetApprovalKeyByMongoID(mongoID, achievementType, competitionLevel string) error {
+	return m.Called(mongoID, achievementType, competitionLevel).Error(0)
+}
+
+func (m *AchievementRefMock) GetSubmittedWithoutApprovalKey() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
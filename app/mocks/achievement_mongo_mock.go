@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 	"uas/app/models"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson"
@@ -43,13 +44,95 @@ func (m *AchievementMongoMock) AddAttachment(ctx context.Context, id string, att
 	return m.Called(ctx, id, att).Error(0)
 }
 
-func (m *AchievementMongoMock) FindByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error) {
-    args := m.Called(ctx, ids)
-    
-    var results []map[string]interface{}
-    if args.Get(0) != nil {
-        results = args.Get(0).([]map[string]interface{})
-    }
-    
-    return results, args.Error(1)
-}
\ No newline at end of file
+func (m *AchievementMongoMock) FindByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) FindByIDsAndType(ctx context.Context, ids []string, achievementType string) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx, ids, achievementType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) FindCertificationsValidUntil(ctx context.Context, after *time.Time, until time.Time) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx, after, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) FindDuplicateCandidates(ctx context.Context, achievementType, studentID string, eventDate *time.Time, excludeID string) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx, achievementType, studentID, eventDate, excludeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) FindUnmarkedExpiredCertifications(ctx context.Context, now time.Time) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) MarkCertificationsExpired(ctx context.Context, ids []string, at time.Time) error {
+	return m.Called(ctx, ids, at).Error(0)
+}
+
+func (m *AchievementMongoMock) UpdatePointsBatch(ctx context.Context, points map[string]int) error {
+	return m.Called(ctx, points).Error(0)
+}
+
+func (m *AchievementMongoMock) FindDeletedByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) Restore(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *AchievementMongoMock) Discard(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *AchievementMongoMock) FindAllWithDeleted(ctx context.Context) ([]*models.MongoAchievement, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MongoAchievement), args.Error(1)
+}
+
+func (m *AchievementMongoMock) FilterIDs(ctx context.Context, achievementType string, tags []string) ([]string, error) {
+	args := m.Called(ctx, achievementType, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *AchievementMongoMock) CountByType(ctx context.Context, achievementType string) (int64, error) {
+	args := m.Called(ctx, achievementType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *AchievementMongoMock) Search(ctx context.Context, query string, ids []string, limit, offset int) ([]*models.AchievementSearchHit, int, error) {
+	args := m.Called(ctx, query, ids, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.AchievementSearchHit), args.Int(1), args.Error(2)
+}
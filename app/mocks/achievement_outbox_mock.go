@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"time"
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type AchievementOutboxMock struct {
+	mock.Mock
+}
+
+func (m *AchievementOutboxMock) Enqueue(entry *models.OutboxEntry) error {
+	return m.Called(entry).Error(0)
+}
+
+func (m *AchievementOutboxMock) Pending(now time.Time, limit int) ([]*models.OutboxEntry, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OutboxEntry), args.Error(1)
+}
+
+func (m *AchievementOutboxMock) MarkProcessed(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *AchievementOutboxMock) MarkFailed(id, lastError string, retryAt time.Time) error {
+	return m.Called(id, lastError, retryAt).Error(0)
+}
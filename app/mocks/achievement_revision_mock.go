@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type AchievementRevisionRepoMock struct {
+	mock.Mock
+}
+
+func (m *AchievementRevisionRepoMock) Append(ctx context.Context, rev *models.AchievementRevision) error {
+	return m.Called(ctx, rev).Error(0)
+}
+
+func (m *AchievementRevisionRepoMock) Latest(ctx context.Context, achievementID string) (*models.AchievementRevision, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AchievementRevision), args.Error(1)
+}
+
+func (m *AchievementRevisionRepoMock) FindByAchievementID(ctx context.Context, achievementID string) ([]*models.AchievementRevision, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AchievementRevision), args.Error(1)
+}
+
+func (m *AchievementRevisionRepoMock) DeleteByAchievementID(ctx context.Context, achievementID string) error {
+	return m.Called(ctx, achievementID).Error(0)
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type AchievementTypeRepoMock struct {
+	mock.Mock
+}
+
+func (m *AchievementTypeRepoMock) FindAll() ([]*models.AchievementTypeDefinition, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AchievementTypeDefinition), args.Error(1)
+}
+
+func (m *AchievementTypeRepoMock) FindByCode(code string) (*models.AchievementTypeDefinition, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AchievementTypeDefinition), args.Error(1)
+}
+
+func (m *AchievementTypeRepoMock) Create(def *models.AchievementTypeDefinition) error {
+	return m.Called(def).Error(0)
+}
+
+func (m *AchievementTypeRepoMock) Update(def *models.AchievementTypeDefinition) error {
+	return m.Called(def).Error(0)
+}
+
+func (m *AchievementTypeRepoMock) Delete(code string) error {
+	return m.Called(code).Error(0)
+}
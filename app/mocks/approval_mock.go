@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"time"
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type ApprovalChainRepoMock struct {
+	mock.Mock
+}
+
+func (m *ApprovalChainRepoMock) FindAll() ([]*models.ApprovalChain, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ApprovalChain), args.Error(1)
+}
+
+func (m *ApprovalChainRepoMock) Upsert(chain *models.ApprovalChain) error {
+	return m.Called(chain).Error(0)
+}
+
+func (m *ApprovalChainRepoMock) Delete(id string) error {
+	return m.Called(id).Error(0)
+}
+
+type AchievementApprovalRepoMock struct {
+	mock.Mock
+}
+
+func (m *AchievementApprovalRepoMock) Create(a *models.AchievementApproval) error {
+	return m.Called(a).Error(0)
+}
+
+func (m *AchievementApprovalRepoMock) GetByMongoID(mongoID string, since *time.Time) ([]*models.AchievementApproval, error) {
+	args := m.Called(mongoID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AchievementApproval), args.Error(1)
+}
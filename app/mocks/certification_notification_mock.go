@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type CertificationNotificationMock struct {
+	mock.Mock
+}
+
+func (m *CertificationNotificationMock) Create(n *models.CertificationNotification) (bool, error) {
+	args := m.Called(n)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *CertificationNotificationMock) GetByRecipient(role, recipientID string) ([]*models.CertificationNotification, error) {
+	args := m.Called(role, recipientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CertificationNotification), args.Error(1)
+}
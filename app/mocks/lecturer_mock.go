@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type LecturerRepoMock struct {
+	mock.Mock
+}
+
+func (m *LecturerRepoMock) FindAll() ([]models.Lecturer, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Lecturer), args.Error(1)
+}
+
+func (m *LecturerRepoMock) FindByID(id string) (*models.Lecturer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Lecturer), args.Error(1)
+}
+
+func (m *LecturerRepoMock) FindByUserID(userID string) (*models.Lecturer, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Lecturer), args.Error(1)
+}
+
+func (m *LecturerRepoMock) Create(lecturer *models.Lecturer) error {
+	return m.Called(lecturer).Error(0)
+}
+
+func (m *LecturerRepoMock) Update(lecturer *models.Lecturer) error {
+	return m.Called(lecturer).Error(0)
+}
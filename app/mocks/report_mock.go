@@ -14,4 +14,12 @@ func (m *ReportRepoMock) GetVerifiedAchievementMongoIDs() ([]string, error) {
 func (m *ReportRepoMock) GetVerifiedAchievementMongoIDsByStudent(studentID string) ([]string, error) {
 	args := m.Called(studentID)
 	return args.Get(0).([]string), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *ReportRepoMock) GetAwardedPointsByStudent(studentID string) (map[string]int, error) {
+	args := m.Called(studentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
@@ -23,4 +23,12 @@ func (m *MongoReportMock) CountByType(ctx context.Context, ids []string) (map[st
 func (m *MongoReportMock) FindByIDs(ctx context.Context, ids []string) ([]bson.M, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]bson.M), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *MongoReportMock) FilterIDsByTag(ctx context.Context, ids []string, tags []string) ([]string, error) {
+	args := m.Called(ctx, ids, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
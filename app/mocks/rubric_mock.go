@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type RubricRepoMock struct {
+	mock.Mock
+}
+
+func (m *RubricRepoMock) FindAll() ([]*models.RubricRule, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.RubricRule), args.Error(1)
+}
+
+func (m *RubricRepoMock) FindByType(achievementType string) ([]*models.RubricRule, error) {
+	args := m.Called(achievementType)
+	return args.Get(0).([]*models.RubricRule), args.Error(1)
+}
+
+func (m *RubricRepoMock) FindByID(id string) (*models.RubricRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RubricRule), args.Error(1)
+}
+
+func (m *RubricRepoMock) Create(rule *models.RubricRule) error {
+	return m.Called(rule).Error(0)
+}
+
+func (m *RubricRepoMock) Update(rule *models.RubricRule) error {
+	return m.Called(rule).Error(0)
+}
+
+func (m *RubricRepoMock) Delete(id string) error {
+	return m.Called(id).Error(0)
+}
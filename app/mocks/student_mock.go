@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type StudentRepoMock struct {
+	mock.Mock
+}
+
+func (m *StudentRepoMock) FindByUserID(userID string) (*models.Student, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Student), args.Error(1)
+}
+
+func (m *StudentRepoMock) FindByAdvisorID(advisorID string) ([]*models.Student, error) {
+	args := m.Called(advisorID)
+	return args.Get(0).([]*models.Student), args.Error(1)
+}
+
+func (m *StudentRepoMock) Create(student *models.Student) error {
+	return m.Called(student).Error(0)
+}
+
+func (m *StudentRepoMock) UpdateAdvisor(studentID string, advisorID string) error {
+	return m.Called(studentID, advisorID).Error(0)
+}
+
+func (m *StudentRepoMock) FindAll() ([]*models.Student, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Student), args.Error(1)
+}
+
+func (m *StudentRepoMock) FindByID(studentID string) (*models.Student, error) {
+	args := m.Called(studentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Student), args.Error(1)
+}
+
+func (m *StudentRepoMock) FindAchievementsByStudentID(studentID string) ([]map[string]any, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]map[string]any), args.Error(1)
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type TagRepoMock struct {
+	mock.Mock
+}
+
+func (m *TagRepoMock) FindAll() ([]*models.Tag, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *TagRepoMock) FindByID(id string) (*models.Tag, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *TagRepoMock) Search(query string, limit int) ([]*models.Tag, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *TagRepoMock) Create(tag *models.Tag) error {
+	return m.Called(tag).Error(0)
+}
+
+func (m *TagRepoMock) Update(tag *models.Tag) error {
+	return m.Called(tag).Error(0)
+}
+
+func (m *TagRepoMock) Delete(id string) error {
+	return m.Called(id).Error(0)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type TeamPointPolicyRepoMock struct {
+	mock.Mock
+}
+
+func (m *TeamPointPolicyRepoMock) FindAll() ([]*models.TeamPointPolicy, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TeamPointPolicy), args.Error(1)
+}
+
+func (m *TeamPointPolicyRepoMock) FindByType(achievementType string) (*models.TeamPointPolicy, error) {
+	args := m.Called(achievementType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamPointPolicy), args.Error(1)
+}
+
+func (m *TeamPointPolicyRepoMock) Upsert(policy *models.TeamPointPolicy) error {
+	return m.Called(policy).Error(0)
+}
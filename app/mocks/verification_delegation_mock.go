@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"time"
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type DelegationRepoMock struct {
+	mock.Mock
+}
+
+func (m *DelegationRepoMock) Create(d *models.VerificationDelegation) error {
+	return m.Called(d).Error(0)
+}
+
+func (m *DelegationRepoMock) FindByID(id string) (*models.VerificationDelegation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VerificationDelegation), args.Error(1)
+}
+
+func (m *DelegationRepoMock) FindByLecturer(lecturerID string) ([]*models.VerificationDelegation, error) {
+	args := m.Called(lecturerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.VerificationDelegation), args.Error(1)
+}
+
+func (m *DelegationRepoMock) FindActive(advisorID, delegateID string, at time.Time) (*models.VerificationDelegation, error) {
+	args := m.Called(advisorID, delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VerificationDelegation), args.Error(1)
+}
+
+func (m *DelegationRepoMock) FindActiveByDelegate(delegateID string, at time.Time) ([]*models.VerificationDelegation, error) {
+	args := m.Called(delegateID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.VerificationDelegation), args.Error(1)
+}
+
+func (m *DelegationRepoMock) HasOverlap(advisorID string, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(advisorID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *DelegationRepoMock) Revoke(id string, at time.Time) error {
+	return m.Called(id, at).Error(0)
+}
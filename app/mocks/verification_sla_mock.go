@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"uas/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type VerificationSLARepoMock struct {
+	mock.Mock
+}
+
+func (m *VerificationSLARepoMock) FindAll() ([]*models.VerificationSLA, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.VerificationSLA), args.Error(1)
+}
+
+func (m *VerificationSLARepoMock) Upsert(sla *models.VerificationSLA) error {
+	return m.Called(sla).Error(0)
+}
+
+func (m *VerificationSLARepoMock) Delete(programStudy string) error {
+	return m.Called(programStudy).Error(0)
+}
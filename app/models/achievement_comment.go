@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type AchievementComment struct {
+	ID                 string     `json:"id"`
+	MongoAchievementID string     `json:"mongoAchievementId"`
+	ParentID           *string    `json:"parentId,omitempty"`
+	AuthorID           string     `json:"authorId"`
+	AuthorRole         string     `json:"authorRole"`
+	Body               string     `json:"body"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+	EditedAt           *time.Time `json:"editedAt,omitempty"`
+}
+
+type CommentThread struct {
+	AchievementComment
+	Unread  bool             `json:"unread"`
+	Replies []*CommentThread `json:"replies"`
+}
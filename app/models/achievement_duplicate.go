@@ -0,0 +1,13 @@
+package models
+
+// DuplicateMatch adalah prestasi lain yang kemungkinan besar sama dengan
+// prestasi yang sedang dibuat atau diajukan.
+type DuplicateMatch struct {
+	AchievementID string   `json:"achievementId"`
+	StudentID     string   `json:"studentId"`
+	Title         string   `json:"title"`
+	Status        string   `json:"status"`
+	SameStudent   bool     `json:"sameStudent"`
+	Score         float64  `json:"score"`
+	Reasons       []string `json:"reasons"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type AchievementStatusHistory struct {
+	ID                 string    `json:"id"`
+	AchievementRefID   string    `json:"achievementRefId"`
+	MongoAchievementID string    `json:"mongoAchievementId"`
+	FromStatus         *string   `json:"fromStatus"`
+	ToStatus           string    `json:"toStatus"`
+	ActorID            string    `json:"actorId"`
+	ActorRole          string    `json:"actorRole"`
+	Note               *string   `json:"note,omitempty"`
+	OnBehalfOf         *string   `json:"onBehalfOf,omitempty"`
+	DelegationID       *string   `json:"delegationId,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Kolom urutan yang didukung ListAchievements.
+const (
+	AchievementSortCreatedAt   = "created_at"
+	AchievementSortUpdatedAt   = "updated_at"
+	AchievementSortSubmittedAt = "submitted_at"
+	AchievementSortStatus      = "status"
+	AchievementSortPoints      = "points"
+)
+
+// AchievementListFilter adalah filter, urutan, dan halaman daftar
+// referensi prestasi. Slice nil berarti tidak difilter; slice kosong berarti
+// tidak ada yang cocok. Limit 0 berarti tanpa batas. StageRoles (huruf
+// kecil) membatasi ke prestasi yang tahap persetujuannya saat ini ditangani
+// role tersebut. IncludeEscalated menyertakan prestasi submitted yang sudah
+// dieskalasi SLA walaupun tidak lolos StageRoles, sehingga masuk antrean
+// admin.
+type AchievementListFilter struct {
+	StudentIDs   []string
+	AdvisorIDs   []string
+	MongoIDs     []string
+	RefIDs       []string
+	Statuses     []string
+	ProgramStudy string
+	From         *time.Time
+	To           *time.Time
+	Sort         string
+	Desc         bool
+	Limit        int
+	Offset       int
+
+	StageRoles       []string
+	IncludeEscalated bool
+}
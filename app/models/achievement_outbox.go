@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Operasi outbox terhadap dokumen Mongo prestasi.
+const (
+	OutboxOpDiscardDocument    = "discard_document"
+	OutboxOpSoftDeleteDocument = "soft_delete_document"
+	OutboxOpRestoreDocument    = "restore_document"
+)
+
+// OutboxEntry adalah satu perubahan dokumen Mongo yang menunggu diterapkan.
+type OutboxEntry struct {
+	ID                 string     `json:"id"`
+	MongoAchievementID string     `json:"mongoAchievementId"`
+	Operation          string     `json:"operation"`
+	Attempts           int        `json:"attempts"`
+	LastError          *string    `json:"lastError"`
+	AvailableAt        time.Time  `json:"availableAt"`
+	ProcessedAt        *time.Time `json:"processedAt"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
+
+type OutboxRelayResult struct {
+	Scanned   int `json:"scanned"`
+	Processed int `json:"processed"`
+	Failed    int `json:"failed"`
+}
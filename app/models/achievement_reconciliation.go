@@ -0,0 +1,47 @@
+package models
+
+// Jenis ketidakcocokan antara achievement_references dan dokumen Mongo.
+const (
+	ReconcileMissingDocument = "missing_document"
+	ReconcileOrphanDocument  = "orphan_document"
+	ReconcileDeletedMismatch = "deleted_state_mismatch"
+)
+
+// Perbaikan yang dilakukan mode repair.
+const (
+	ReconcileActionCreateReference     = "create_reference"
+	ReconcileActionSoftDeleteDocument  = "soft_delete_document"
+	ReconcileActionRestoreDocument     = "restore_document"
+	ReconcileActionSoftDeleteReference = "soft_delete_reference"
+)
+
+type ReconciliationRequest struct {
+	Repair bool `json:"repair"`
+}
+
+// ReconciliationIssue adalah satu prestasi yang datanya tidak cocok antara
+// Postgres dan Mongo. Action kosong berarti tidak bisa diperbaiki otomatis.
+type ReconciliationIssue struct {
+	Kind               string `json:"kind"`
+	MongoAchievementID string `json:"mongoAchievementId"`
+	StudentID          string `json:"studentId,omitempty"`
+	Title              string `json:"title,omitempty"`
+	ReferenceStatus    string `json:"referenceStatus,omitempty"`
+	DocumentDeleted    bool   `json:"documentDeleted"`
+	Action             string `json:"action,omitempty"`
+	Repaired           bool   `json:"repaired"`
+	Error              string `json:"error,omitempty"`
+}
+
+type ReconciliationReport struct {
+	Repair            bool                   `json:"repair"`
+	ReferencesScanned int                    `json:"referencesScanned"`
+	DocumentsScanned  int                    `json:"documentsScanned"`
+	MissingDocuments  int                    `json:"missingDocuments"`
+	OrphanDocuments   int                    `json:"orphanDocuments"`
+	DeletedMismatches int                    `json:"deletedMismatches"`
+	RecentSkipped     int                    `json:"recentSkipped"`
+	Repaired          int                    `json:"repaired"`
+	Failed            int                    `json:"failed"`
+	Issues            []*ReconciliationIssue `json:"issues"`
+}
@@ -1,16 +1,71 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	AchievementStatusDraft     = "draft"
+	AchievementStatusSubmitted = "submitted"
+	AchievementStatusVerified  = "verified"
+	AchievementStatusRejected  = "rejected"
+	AchievementStatusDeleted   = "deleted"
+
+	AchievementStatusNeedsRevision = "needs_revision"
+)
 
 type AchievementReference struct {
-	ID                 string
-	StudentID          string
-	MongoAchievementID string
-	Status             string      
-	SubmittedAt        *time.Time  
-	VerifiedAt         *time.Time 
-	VerifiedBy         *string    
-	RejectionNote      *string     
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	ID                  string
+	StudentID           string
+	MongoAchievementID  string
+	Status              string
+	SubmittedAt         *time.Time
+	VerifiedAt          *time.Time
+	VerifiedBy          *string
+	RejectionNote       *string
+	RevisionCount       int
+	RevisionFeedback    RevisionFeedback
+	SuggestedPoints     *int
+	PointsJustification *string
+	ApprovalStage       int
+	DeletedAt           *time.Time
+	StatusBeforeDelete  *string
+	TeamRole            string
+	TeamSize            int
+	Points              *int
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// RevisionComment adalah catatan reviewer untuk satu field prestasi,
+// misalnya {"field": "attachments", "comment": "lampirkan scan sertifikat"}.
+type RevisionComment struct {
+	Field   string `json:"field"`
+	Comment string `json:"comment"`
+}
+
+// RevisionFeedback disimpan sebagai JSONB di achievement_references.
+type RevisionFeedback []RevisionComment
+
+func (f RevisionFeedback) Value() (driver.Value, error) {
+	if f == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(f)
+}
+
+func (f *RevisionFeedback) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	}
+	return fmt.Errorf("cannot scan %T into RevisionFeedback", src)
 }
@@ -0,0 +1,49 @@
+package models
+
+// VerificationResult adalah hasil satu sign-off. Status tetap submitted
+// jika masih ada tahap persetujuan berikutnya (NextStage).
+type VerificationResult struct {
+	Status         string `json:"status"`
+	ApprovedStage  string `json:"approvedStage"`
+	NextStage      string `json:"nextStage,omitempty"`
+	Points         int    `json:"points"`
+	ComputedPoints int    `json:"computedPoints"`
+	Overridden     bool   `json:"overridden"`
+	StudentID      string `json:"studentId"`
+	AwardedPoints  int    `json:"awardedPoints"`
+}
+
+// Hasil per item pada verifikasi/penolakan massal.
+const (
+	BulkOutcomeSucceeded = "succeeded"
+	BulkOutcomeConflict  = "conflict"
+	BulkOutcomeForbidden = "forbidden"
+	BulkOutcomeNotFound  = "not_found"
+	BulkOutcomeInvalid   = "invalid"
+	BulkOutcomeFailed    = "failed"
+)
+
+type BulkReviewItemResult struct {
+	ID             string `json:"id"`
+	StudentID      string `json:"studentId,omitempty"`
+	Outcome        string `json:"outcome"`
+	Status         string `json:"status,omitempty"`
+	NextStage      string `json:"nextStage,omitempty"`
+	CurrentStatus  string `json:"currentStatus,omitempty"`
+	Error          string `json:"error,omitempty"`
+	Points         *int   `json:"points,omitempty"`
+	ComputedPoints *int   `json:"computedPoints,omitempty"`
+	AwardedPoints  *int   `json:"awardedPoints,omitempty"`
+	Overridden     bool   `json:"overridden,omitempty"`
+}
+
+type BulkReviewReport struct {
+	Total     int                     `json:"total"`
+	Succeeded int                     `json:"succeeded"`
+	Conflicts int                     `json:"conflicts"`
+	Forbidden int                     `json:"forbidden"`
+	NotFound  int                     `json:"notFound"`
+	Invalid   int                     `json:"invalid"`
+	Failed    int                     `json:"failed"`
+	Results   []*BulkReviewItemResult `json:"results"`
+}
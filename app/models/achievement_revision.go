@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementRevision adalah snapshot isi prestasi setiap kali mahasiswa
+// membuat, mengubah, atau menambah lampiran. Version dimulai dari 1.
+type AchievementRevision struct {
+	ID            primitive.ObjectID      `bson:"_id,omitempty" json:"id"`
+	AchievementID string                  `bson:"achievementId" json:"achievementId"`
+	Version       int                     `bson:"version" json:"version"`
+	AuthorID      string                  `bson:"authorId" json:"authorId"`
+	AuthorRole    string                  `bson:"authorRole" json:"authorRole"`
+	Status        string                  `bson:"status" json:"status"`
+	Title         string                  `bson:"title" json:"title"`
+	Description   string                  `bson:"description" json:"description"`
+	Details       AchievementDetails      `bson:"details" json:"details"`
+	Attachments   []AchievementAttachment `bson:"attachments" json:"attachments"`
+	Tags          []string                `bson:"tags" json:"tags"`
+	CreatedAt     time.Time               `bson:"createdAt" json:"createdAt"`
+}
+
+const (
+	RevisionChangeAdded    = "added"
+	RevisionChangeRemoved  = "removed"
+	RevisionChangeModified = "modified"
+)
+
+// RevisionChange adalah perbedaan satu field antara dua revisi. Field
+// memakai nama bson bertitik, mis. "details.competitionName".
+type RevisionChange struct {
+	Field  string      `json:"field"`
+	Kind   string      `json:"kind"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// AchievementSearchHit adalah dokumen hasil pencarian teks beserta skor
+// relevansinya dari MongoDB.
+type AchievementSearchHit struct {
+	MongoAchievement `bson:",inline"`
+	Score            float64 `bson:"score" json:"score"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TrashedAchievement adalah satu prestasi di tempat sampah.
+type TrashedAchievement struct {
+	ID                 string     `json:"id"`
+	StudentID          string     `json:"studentId"`
+	Title              string     `json:"title"`
+	AchievementType    string     `json:"achievementType"`
+	StatusBeforeDelete string     `json:"statusBeforeDelete"`
+	DeletedAt          *time.Time `json:"deletedAt"`
+}
+
+type TrashPurgeResult struct {
+	Scanned int `json:"scanned"`
+	Purged  int `json:"purged"`
+	Failed  int `json:"failed"`
+}
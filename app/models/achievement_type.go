@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AchievementTypeDefinition adalah tipe prestasi tambahan yang didaftarkan
+// admin di luar tipe bawaan. CustomFieldsSchema berisi JSON Schema untuk
+// details.customFields, sedangkan DefaultPoints menjadi poin dasar tipe ini
+// selama rubrik belum punya aturan "base" untuknya.
+type AchievementTypeDefinition struct {
+	ID                 string          `json:"id"`
+	Code               string          `json:"code"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	CustomFieldsSchema json.RawMessage `json:"customFieldsSchema" swaggertype:"object"`
+	DefaultPoints      int             `json:"defaultPoints"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
+}
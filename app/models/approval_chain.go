@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ApprovalRoleAdvisor menandakan tahap yang ditangani dosen wali mahasiswa
+// (atau penerima delegasinya). Role lain dicocokkan dengan nama role user.
+const ApprovalRoleAdvisor = "advisor"
+
+type ApprovalStage struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type ApprovalStages []ApprovalStage
+
+func (s ApprovalStages) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *ApprovalStages) Scan(value interface{}) error {
+	if value == nil {
+		*s = ApprovalStages{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("ApprovalStages: unexpected type %T", value)
+	}
+	return json.Unmarshal(b, s)
+}
+
+// DefaultApprovalStages dipakai jika tidak ada rantai persetujuan yang
+// cocok: cukup satu tahap oleh dosen wali.
+var DefaultApprovalStages = ApprovalStages{{Name: "advisor", Role: ApprovalRoleAdvisor}}
+
+// ApprovalChain mendefinisikan tahap persetujuan untuk satu AchievementType.
+// CompetitionLevel kosong berlaku untuk semua tingkat tipe tersebut.
+type ApprovalChain struct {
+	ID               string         `json:"id"`
+	AchievementType  string         `json:"achievementType"`
+	CompetitionLevel string         `json:"competitionLevel"`
+	Stages           ApprovalStages `json:"stages"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	UpdatedAt        time.Time      `json:"updatedAt"`
+}
+
+// AchievementApproval adalah tanda tangan satu tahap persetujuan.
+type AchievementApproval struct {
+	ID                 string    `json:"id"`
+	AchievementRefID   string    `json:"achievementRefId"`
+	MongoAchievementID string    `json:"mongoAchievementId"`
+	StageIndex         int       `json:"stageIndex"`
+	StageName          string    `json:"stageName"`
+	ApproverID         string    `json:"approverId"`
+	ApproverRole       string    `json:"approverRole"`
+	OnBehalfOf         *string   `json:"onBehalfOf,omitempty"`
+	ApprovedAt         time.Time `json:"approvedAt"`
+}
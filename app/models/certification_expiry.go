@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// CertificationValidity adalah sertifikasi terverifikasi beserta masa
+// berlakunya. Valid bernilai false setelah ValidUntil terlewati.
+type CertificationValidity struct {
+	MongoAchievementID string     `json:"id"`
+	StudentID          string     `json:"studentId"`
+	ProgramStudy       string     `json:"programStudy"`
+	AdvisorID          *string    `json:"advisorId"`
+	Title              string     `json:"title"`
+	CertificationName  string     `json:"certificationName"`
+	IssuedBy           string     `json:"issuedBy"`
+	ValidUntil         *time.Time `json:"validUntil"`
+	ExpiredAt          *time.Time `json:"expiredAt"`
+	Valid              bool       `json:"valid"`
+	DaysLeft           int        `json:"daysLeft"`
+}
+
+type CertificationExpiryResult struct {
+	Scanned  int `json:"scanned"`
+	Expired  int `json:"expired"`
+	Notified int `json:"notified"`
+}
+
+// Jenis dan penerima CertificationNotification.
+const (
+	CertificationExpiring = "expiring"
+	CertificationExpired  = "expired"
+
+	RecipientStudent = "student"
+	RecipientAdvisor = "advisor"
+)
+
+// CertificationNotification memberi tahu mahasiswa atau dosen walinya bahwa
+// sertifikasi akan atau sudah kedaluwarsa. RecipientID adalah ID mahasiswa
+// atau ID dosen sesuai RecipientRole.
+type CertificationNotification struct {
+	ID                 string     `json:"id"`
+	MongoAchievementID string     `json:"mongoAchievementId"`
+	StudentID          string     `json:"studentId"`
+	RecipientRole      string     `json:"recipientRole"`
+	RecipientID        string     `json:"recipientId"`
+	Kind               string     `json:"kind"`
+	ValidUntil         *time.Time `json:"validUntil"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
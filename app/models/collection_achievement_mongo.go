@@ -53,9 +53,11 @@ type MongoAchievement struct {
     Description     string                  `bson:"description" json:"description"`
     Details         AchievementDetails      `bson:"details" json:"details"`
     Attachments     []AchievementAttachment `bson:"attachments" json:"attachments"`
+    Members         []TeamMember            `bson:"members,omitempty" json:"members,omitempty"`
     Tags            []string                `bson:"tags" json:"tags"`
     Points          int                     `bson:"points" json:"points"`
     CreatedAt       time.Time               `bson:"createdAt" json:"createdAt"`
     UpdatedAt       time.Time               `bson:"updatedAt" json:"updatedAt"`
     DeletedAt       *time.Time              `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+    ExpiredAt       *time.Time              `bson:"expiredAt,omitempty" json:"expiredAt,omitempty"`
 }
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type PointsRecalculationRequest struct {
+	From              *time.Time `json:"from"`
+	To                *time.Time `json:"to"`
+	AchievementType   string     `json:"achievementType"`
+	ProgramStudy      string     `json:"programStudy"`
+	DryRun            *bool      `json:"dryRun"`
+	BatchSize         int        `json:"batchSize"`
+	IncludeOverridden bool       `json:"includeOverridden"`
+}
+
+type PointsChange struct {
+	AchievementID string `json:"achievementId"`
+	StudentID     string `json:"studentId"`
+	OldPoints     int    `json:"oldPoints"`
+	NewPoints     int    `json:"newPoints"`
+}
+
+type StudentPointsChange struct {
+	StudentID string `json:"studentId"`
+	OldTotal  int    `json:"oldTotal"`
+	NewTotal  int    `json:"newTotal"`
+	Delta     int    `json:"delta"`
+	Changed   int    `json:"changedAchievements"`
+}
+
+type PointsRecalculationReport struct {
+	DryRun            bool                   `json:"dryRun"`
+	Scanned           int                    `json:"scanned"`
+	Changed           int                    `json:"changed"`
+	Unchanged         int                    `json:"unchanged"`
+	SkippedOverridden int                    `json:"skippedOverridden"`
+	Missing           int                    `json:"missing"`
+	Batches           int                    `json:"batches"`
+	StudentsChanged   int                    `json:"studentsChanged"`
+	Changes           []PointsChange         `json:"changes"`
+	Students          []*StudentPointsChange `json:"students"`
+}
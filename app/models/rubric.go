@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const RubricFieldBase = "base"
+
+// RubricFields adalah field AchievementDetails yang boleh dipakai aturan rubrik.
+var RubricFields = []string{
+	RubricFieldBase,
+	"competitionLevel",
+	"rank",
+	"medalType",
+	"publicationType",
+	"position",
+}
+
+type RubricRule struct {
+	ID              string    `json:"id"`
+	AchievementType string    `json:"achievementType"`
+	Field           string    `json:"field"`
+	Value           string    `json:"value"`
+	Points          int       `json:"points"`
+	Description     string    `json:"description"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type RubricScore struct {
+	Points  int           `json:"points"`
+	Matches []*RubricRule `json:"matches"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Tag adalah satu entri kosakata tag prestasi. Aliases berisi ejaan lain
+// yang dinormalisasi ke Name.
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
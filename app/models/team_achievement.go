@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const (
+	TeamRoleOwner  = "owner"
+	TeamRoleMember = "member"
+
+	TeamPointPolicySplit     = "split"
+	TeamPointPolicyDuplicate = "duplicate"
+)
+
+// TeamMember adalah anggota prestasi tim. Role bebas diisi, mis. "ketua"
+// atau "penulis kedua".
+type TeamMember struct {
+	StudentID string `bson:"studentId" json:"studentId"`
+	Role      string `bson:"role,omitempty" json:"role,omitempty"`
+}
+
+// TeamPointPolicy menentukan pembagian poin prestasi tim untuk satu tipe.
+type TeamPointPolicy struct {
+	AchievementType string    `json:"achievementType"`
+	Policy          string    `json:"policy"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// VerificationDelegation memberi DelegateID (dosen) hak verifikasi atas
+// mahasiswa bimbingan AdvisorID selama StartsAt <= waktu < EndsAt, kecuali
+// sudah dicabut.
+type VerificationDelegation struct {
+	ID         string     `json:"id"`
+	AdvisorID  string     `json:"advisorId"`
+	DelegateID string     `json:"delegateId"`
+	StartsAt   time.Time  `json:"startsAt"`
+	EndsAt     time.Time  `json:"endsAt"`
+	Reason     string     `json:"reason"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+func (d *VerificationDelegation) IsActive(at time.Time) bool {
+	return d.RevokedAt == nil && !at.Before(d.StartsAt) && at.Before(d.EndsAt)
+}
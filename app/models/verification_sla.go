@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// VerificationSLA adalah batas waktu verifikasi untuk satu program studi.
+// ProgramStudy kosong berlaku sebagai default untuk program yang belum diatur.
+// Prestasi submitted yang melewati DueDays ditandai overdue, dan setelah
+// EscalateDays dieskalasi ke antrean admin.
+type VerificationSLA struct {
+	ProgramStudy string    `json:"programStudy"`
+	DueDays      int       `json:"dueDays"`
+	EscalateDays int       `json:"escalateDays"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type PendingVerification struct {
+	MongoAchievementID string     `json:"id"`
+	StudentID          string     `json:"studentId"`
+	ProgramStudy       string     `json:"programStudy"`
+	AdvisorID          *string    `json:"advisorId"`
+	SubmittedAt        *time.Time `json:"submittedAt"`
+	OverdueAt          *time.Time `json:"overdueAt"`
+	EscalatedAt        *time.Time `json:"escalatedAt"`
+	DaysPending        int        `json:"daysPending"`
+}
+
+type SLACheckResult struct {
+	Scanned   int `json:"scanned"`
+	Overdue   int `json:"overdue"`
+	Escalated int `json:"escalated"`
+}
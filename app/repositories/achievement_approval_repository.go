@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+	"uas/app/models"
+)
+
+type IAchievementApprovalRepository interface {
+	Create(a *models.AchievementApproval) error
+	GetByMongoID(mongoID string, since *time.Time) ([]*models.AchievementApproval, error)
+}
+
+type AchievementApprovalRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementApprovalRepository(db *sql.DB) IAchievementApprovalRepository {
+	return &AchievementApprovalRepository{DB: db}
+}
+
+func (r *AchievementApprovalRepository) Create(a *models.AchievementApproval) error {
+	return r.DB.QueryRow(`
+		INSERT INTO achievement_approvals
+		(achievement_ref_id, mongo_achievement_id, stage_index, stage_name,
+		 approver_id, approver_role, on_behalf_of, approved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING id, approved_at
+	`,
+		a.AchievementRefID,
+		a.MongoAchievementID,
+		a.StageIndex,
+		a.StageName,
+		a.ApproverID,
+		a.ApproverRole,
+		a.OnBehalfOf,
+	).Scan(&a.ID, &a.ApprovedAt)
+}
+
+// GetByMongoID mengembalikan persetujuan prestasi, opsional hanya yang
+// diberikan sejak waktu tertentu (misalnya sejak pengajuan terakhir).
+func (r *AchievementApprovalRepository) GetByMongoID(mongoID string, since *time.Time) ([]*models.AchievementApproval, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, achievement_ref_id, mongo_achievement_id, stage_index, stage_name,
+		       approver_id, approver_role, on_behalf_of, approved_at
+		FROM achievement_approvals
+		WHERE mongo_achievement_id = $1
+		  AND ($2::timestamp IS NULL OR approved_at >= $2)
+		ORDER BY approved_at ASC
+	`, mongoID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []*models.AchievementApproval
+	for rows.Next() {
+		a := &models.AchievementApproval{}
+		if err := rows.Scan(
+			&a.ID,
+			&a.AchievementRefID,
+			&a.MongoAchievementID,
+			&a.StageIndex,
+			&a.StageName,
+			&a.ApproverID,
+			&a.ApproverRole,
+			&a.OnBehalfOf,
+			&a.ApprovedAt,
+		); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, nil
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+	"uas/app/models"
+)
+
+type IAchievementCommentRepository interface {
+	Create(comment *models.AchievementComment) error
+	GetByID(id string) (*models.AchievementComment, error)
+	GetByMongoID(mongoID string) ([]*models.AchievementComment, error)
+	UpdateBody(id string, body string) error
+	GetLastRead(mongoID string, userID string) (*time.Time, error)
+	MarkRead(mongoID string, userID string, at time.Time) error
+}
+
+type AchievementCommentRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementCommentRepository(db *sql.DB) IAchievementCommentRepository {
+	return &AchievementCommentRepository{DB: db}
+}
+
+func (r *AchievementCommentRepository) Create(comment *models.AchievementComment) error {
+	return r.DB.QueryRow(`
+		INSERT INTO achievement_comments
+		(mongo_achievement_id, parent_id, author_id, author_role, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`,
+		comment.MongoAchievementID,
+		comment.ParentID,
+		comment.AuthorID,
+		comment.AuthorRole,
+		comment.Body,
+	).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+}
+
+func (r *AchievementCommentRepository) GetByID(id string) (*models.AchievementComment, error) {
+	c := &models.AchievementComment{}
+	err := r.DB.QueryRow(`
+		SELECT id, mongo_achievement_id, parent_id, author_id, author_role,
+		       body, created_at, updated_at, edited_at
+		FROM achievement_comments
+		WHERE id=$1
+	`, id).Scan(
+		&c.ID,
+		&c.MongoAchievementID,
+		&c.ParentID,
+		&c.AuthorID,
+		&c.AuthorRole,
+		&c.Body,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.EditedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *AchievementCommentRepository) GetByMongoID(mongoID string) ([]*models.AchievementComment, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, mongo_achievement_id, parent_id, author_id, author_role,
+		       body, created_at, updated_at, edited_at
+		FROM achievement_comments
+		WHERE mongo_achievement_id=$1
+		ORDER BY created_at ASC
+	`, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.AchievementComment
+	for rows.Next() {
+		c := &models.AchievementComment{}
+		if err := rows.Scan(
+			&c.ID,
+			&c.MongoAchievementID,
+			&c.ParentID,
+			&c.AuthorID,
+			&c.AuthorRole,
+			&c.Body,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+			&c.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, nil
+}
+
+func (r *AchievementCommentRepository) UpdateBody(id string, body string) error {
+	_, err := r.DB.Exec(`
+		UPDATE achievement_comments
+		SET body=$2, edited_at=NOW(), updated_at=NOW()
+		WHERE id=$1
+	`, id, body)
+	return err
+}
+
+func (r *AchievementCommentRepository) GetLastRead(mongoID string, userID string) (*time.Time, error) {
+	var lastRead time.Time
+	err := r.DB.QueryRow(`
+		SELECT last_read_at
+		FROM achievement_comment_reads
+		WHERE mongo_achievement_id=$1 AND user_id=$2
+	`, mongoID, userID).Scan(&lastRead)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lastRead, nil
+}
+
+func (r *AchievementCommentRepository) MarkRead(mongoID string, userID string, at time.Time) error {
+	_, err := r.DB.Exec(`
+		INSERT INTO achievement_comment_reads (mongo_achievement_id, user_id, last_read_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (mongo_achievement_id, user_id)
+		DO UPDATE SET last_read_at = GREATEST(achievement_comment_reads.last_read_at, EXCLUDED.last_read_at)
+	`, mongoID, userID, at)
+	return err
+}
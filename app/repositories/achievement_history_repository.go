@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type IAchievementHistoryRepository interface {
+	Create(h *models.AchievementStatusHistory) error
+	GetByMongoID(mongoID string) ([]*models.AchievementStatusHistory, error)
+}
+
+type AchievementHistoryRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementHistoryRepository(db *sql.DB) IAchievementHistoryRepository {
+	return &AchievementHistoryRepository{DB: db}
+}
+
+func (r *AchievementHistoryRepository) Create(h *models.AchievementStatusHistory) error {
+	return r.DB.QueryRow(`
+		INSERT INTO achievement_status_history
+		(achievement_ref_id, mongo_achievement_id, from_status, to_status,
+		 actor_id, actor_role, note, on_behalf_of, delegation_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING id, created_at
+	`,
+		h.AchievementRefID,
+		h.MongoAchievementID,
+		h.FromStatus,
+		h.ToStatus,
+		h.ActorID,
+		h.ActorRole,
+		h.Note,
+		h.OnBehalfOf,
+		h.DelegationID,
+	).Scan(&h.ID, &h.CreatedAt)
+}
+
+func (r *AchievementHistoryRepository) GetByMongoID(mongoID string) ([]*models.AchievementStatusHistory, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, achievement_ref_id, mongo_achievement_id, from_status, to_status,
+		       actor_id, actor_role, note, on_behalf_of, delegation_id, created_at
+		FROM achievement_status_history
+		WHERE mongo_achievement_id=$1
+		ORDER BY created_at ASC
+	`, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*models.AchievementStatusHistory
+	for rows.Next() {
+		h := &models.AchievementStatusHistory{}
+		if err := rows.Scan(
+			&h.ID,
+			&h.AchievementRefID,
+			&h.MongoAchievementID,
+			&h.FromStatus,
+			&h.ToStatus,
+			&h.ActorID,
+			&h.ActorRole,
+			&h.Note,
+			&h.OnBehalfOf,
+			&h.DelegationID,
+			&h.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+
+	return history, nil
+}
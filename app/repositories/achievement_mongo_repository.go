@@ -4,11 +4,13 @@ import (
 	"context"
 	"time"
 	"fmt"
+	"regexp"
 	"uas/app/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IAchievementMongoRepository interface {
@@ -19,7 +21,20 @@ type IAchievementMongoRepository interface {
 	SoftDelete(ctx context.Context, id string) error
 	AddAttachment(ctx context.Context, id string, attachment models.AchievementAttachment) error
 	UpdatePoints(ctx context.Context, id string, points int) error
-	FindByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error)
+	UpdatePointsBatch(ctx context.Context, points map[string]int) error
+	FindByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error)
+	FindByIDsAndType(ctx context.Context, ids []string, achievementType string) ([]*models.MongoAchievement, error)
+	FindCertificationsValidUntil(ctx context.Context, after *time.Time, until time.Time) ([]*models.MongoAchievement, error)
+	FindDuplicateCandidates(ctx context.Context, achievementType, studentID string, eventDate *time.Time, excludeID string) ([]*models.MongoAchievement, error)
+	FindUnmarkedExpiredCertifications(ctx context.Context, now time.Time) ([]*models.MongoAchievement, error)
+	MarkCertificationsExpired(ctx context.Context, ids []string, at time.Time) error
+	FindDeletedByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error)
+	Restore(ctx context.Context, id string) error
+	Discard(ctx context.Context, id string) error
+	FindAllWithDeleted(ctx context.Context) ([]*models.MongoAchievement, error)
+	FilterIDs(ctx context.Context, achievementType string, tags []string) ([]string, error)
+	CountByType(ctx context.Context, achievementType string) (int64, error)
+	Search(ctx context.Context, query string, ids []string, limit, offset int) ([]*models.AchievementSearchHit, int, error)
 }
 
 type AchievementMongoRepository struct {
@@ -38,7 +53,11 @@ func (r *AchievementMongoRepository) Insert(ctx context.Context, data *models.Mo
     data.CreatedAt = time.Now()
     data.UpdatedAt = time.Now()
     
-    data.ID = primitive.NilObjectID 
+    // ID boleh ditentukan pemanggil agar bisa dicatat di outbox sebelum
+    // dokumen disisipkan.
+    if data.ID.IsZero() {
+        data.ID = primitive.NewObjectID()
+    }
 
     res, err := r.collection.InsertOne(ctx, data)
     if err != nil {
@@ -121,14 +140,19 @@ func (r *AchievementMongoRepository) Update(
 		return err
 	}
 
+	set := bson.M{
+		"title":       data.Title,
+		"description": data.Description,
+		"details":     data.Details,
+		"updatedAt":   time.Now(),
+	}
+	if data.Tags != nil {
+		set["tags"] = data.Tags
+	}
+
 	_, err = r.collection.UpdateOne(ctx,
 		bson.M{"_id": oid},
-		bson.M{"$set": bson.M{
-			"title":       data.Title,
-			"description": data.Description,
-			"details":     data.Details,
-			"updatedAt":   time.Now(),
-		}},
+		bson.M{"$set": set},
 	)
 
 	return err
@@ -196,22 +220,35 @@ func (r *AchievementMongoRepository) UpdatePoints(ctx context.Context, id string
     return err
 }
 
-func (r *AchievementMongoRepository) FindByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error) {
-    var filterIDs []interface{}
+// FindByIDs membaca banyak dokumen aktif sekaligus. ID yang tidak valid
+// atau tidak ditemukan dilewati; urutan hasil tidak dijamin.
+func (r *AchievementMongoRepository) FindByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error) {
+    return r.FindByIDsAndType(ctx, ids, "")
+}
+
+// FindByIDsAndType seperti FindByIDs, tetapi hanya dokumen dengan tipe
+// achievementType. Tipe kosong tidak difilter.
+func (r *AchievementMongoRepository) FindByIDsAndType(ctx context.Context, ids []string, achievementType string) ([]*models.MongoAchievement, error) {
+    oids := make([]primitive.ObjectID, 0, len(ids))
     for _, id := range ids {
         if oid, err := primitive.ObjectIDFromHex(id); err == nil {
-            filterIDs = append(filterIDs, oid)
+            oids = append(oids, oid)
         }
-        filterIDs = append(filterIDs, id)
+    }
+    if len(oids) == 0 {
+        return nil, nil
     }
 
     filter := bson.M{
-        "_id": bson.M{"$in": filterIDs},
+        "_id": bson.M{"$in": oids},
         "$or": []bson.M{
             {"deletedAt": nil},
             {"deletedAt": bson.M{"$exists": false}},
         },
     }
+    if achievementType != "" {
+        filter["achievementType"] = achievementType
+    }
 
     cursor, err := r.collection.Find(ctx, filter)
     if err != nil {
@@ -219,9 +256,372 @@ func (r *AchievementMongoRepository) FindByIDs(ctx context.Context, ids []string
     }
     defer cursor.Close(ctx)
 
-    var results []map[string]interface{}
+    var results []*models.MongoAchievement
+    if err := cursor.All(ctx, &results); err != nil {
+        return nil, err
+    }
+    return results, nil
+}
+
+// FindCertificationsValidUntil mengembalikan sertifikasi aktif yang
+// validUntil-nya setelah after (nil berarti tanpa batas bawah) dan paling
+// lambat until.
+func (r *AchievementMongoRepository) FindCertificationsValidUntil(
+    ctx context.Context,
+    after *time.Time,
+    until time.Time,
+) ([]*models.MongoAchievement, error) {
+    window := bson.M{"$lte": until}
+    if after != nil {
+        window["$gt"] = *after
+    }
+
+    cursor, err := r.collection.Find(ctx, bson.M{
+        "achievementType":    "certification",
+        "details.validUntil": window,
+        "$or": []bson.M{
+            {"deletedAt": nil},
+            {"deletedAt": bson.M{"$exists": false}},
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var results []*models.MongoAchievement
     if err := cursor.All(ctx, &results); err != nil {
         return nil, err
     }
     return results, nil
-}
\ No newline at end of file
+}
+
+// FindDuplicateCandidates mengembalikan prestasi aktif bertipe
+// achievementType milik studentID, atau milik siapa pun dengan tanggal
+// kegiatan paling jauh sehari dari eventDate. excludeID tidak ikut.
+func (r *AchievementMongoRepository) FindDuplicateCandidates(
+    ctx context.Context,
+    achievementType, studentID string,
+    eventDate *time.Time,
+    excludeID string,
+) ([]*models.MongoAchievement, error) {
+    scope := []bson.M{{"studentId": studentID}}
+    if eventDate != nil {
+        scope = append(scope, bson.M{"details.eventDate": bson.M{
+            "$gte": eventDate.AddDate(0, 0, -1),
+            "$lte": eventDate.AddDate(0, 0, 1),
+        }})
+    }
+
+    filter := bson.M{
+        "achievementType": achievementType,
+        "$and": []bson.M{
+            {"$or": scope},
+            {"$or": []bson.M{
+                {"deletedAt": nil},
+                {"deletedAt": bson.M{"$exists": false}},
+            }},
+        },
+    }
+    if oid, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+        filter["_id"] = bson.M{"$ne": oid}
+    }
+
+    cursor, err := r.collection.Find(ctx, filter)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var results []*models.MongoAchievement
+    if err := cursor.All(ctx, &results); err != nil {
+        return nil, err
+    }
+    return results, nil
+}
+
+// FindUnmarkedExpiredCertifications mengembalikan sertifikasi aktif yang
+// validUntil-nya sudah lewat pada now tetapi belum ditandai expiredAt oleh
+// MarkCertificationsExpired.
+func (r *AchievementMongoRepository) FindUnmarkedExpiredCertifications(
+    ctx context.Context,
+    now time.Time,
+) ([]*models.MongoAchievement, error) {
+    cursor, err := r.collection.Find(ctx, bson.M{
+        "achievementType":    "certification",
+        "details.validUntil": bson.M{"$lte": now},
+        "expiredAt":          nil,
+        "$or": []bson.M{
+            {"deletedAt": nil},
+            {"deletedAt": bson.M{"$exists": false}},
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var results []*models.MongoAchievement
+    if err := cursor.All(ctx, &results); err != nil {
+        return nil, err
+    }
+    return results, nil
+}
+
+// MarkCertificationsExpired menandai dokumen sertifikasi yang sudah
+// diproses pemeriksa kedaluwarsa agar tidak dipindai ulang.
+func (r *AchievementMongoRepository) MarkCertificationsExpired(ctx context.Context, ids []string, at time.Time) error {
+    oids := make([]primitive.ObjectID, 0, len(ids))
+    for _, id := range ids {
+        if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+            oids = append(oids, oid)
+        }
+    }
+    if len(oids) == 0 {
+        return nil
+    }
+
+    _, err := r.collection.UpdateMany(ctx,
+        bson.M{"_id": bson.M{"$in": oids}, "expiredAt": nil},
+        bson.M{"$set": bson.M{"expiredAt": at}},
+    )
+    return err
+}
+
+// UpdatePointsBatch memperbarui poin banyak dokumen dalam satu BulkWrite.
+func (r *AchievementMongoRepository) UpdatePointsBatch(ctx context.Context, points map[string]int) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(points))
+	for id, p := range points {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf("invalid id format: %s", id)
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": oid}).
+			SetUpdate(bson.M{"$set": bson.M{"points": p, "updatedAt": now}}))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindDeletedByIDs mengembalikan dokumen yang sudah di-soft delete.
+func (r *AchievementMongoRepository) FindDeletedByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"_id":       bson.M{"$in": oids},
+		"deletedAt": bson.M{"$ne": nil},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []*models.MongoAchievement
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *AchievementMongoRepository) Restore(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": oid},
+		bson.M{
+			"$unset": bson.M{"deletedAt": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		},
+	)
+	return err
+}
+
+// Discard menghapus permanen dokumen tanpa syarat soft delete. Dipakai
+// saat referensi Postgres gagal dibuat atau sudah dihapus permanen;
+// dokumen yang tidak ada dianggap sudah terhapus.
+func (r *AchievementMongoRepository) Discard(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+// CountByType menghitung dokumen bertipe achievementType, termasuk yang di
+// tempat sampah karena masih bisa dipulihkan.
+func (r *AchievementMongoRepository) CountByType(ctx context.Context, achievementType string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"achievementType": achievementType})
+}
+
+// FindAllWithDeleted mengembalikan ID, pemilik, judul, waktu dibuat, dan
+// status hapus semua dokumen, termasuk yang di-soft delete.
+func (r *AchievementMongoRepository) FindAllWithDeleted(ctx context.Context) ([]*models.MongoAchievement, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{
+		"_id":       1,
+		"studentId": 1,
+		"title":     1,
+		"createdAt": 1,
+		"deletedAt": 1,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []*models.MongoAchievement
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FilterIDs mengembalikan ID dokumen aktif dengan tipe tertentu yang
+// memiliki salah satu tags. Tag dicocokkan tanpa membedakan huruf besar
+// kecil; argumen kosong tidak difilter.
+func (r *AchievementMongoRepository) FilterIDs(ctx context.Context, achievementType string, tags []string) ([]string, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"deletedAt": bson.M{"$exists": false}},
+			{"deletedAt": nil},
+		},
+	}
+	if achievementType != "" {
+		filter["achievementType"] = achievementType
+	}
+	if len(tags) > 0 {
+		filter["tags"] = tagsFilter(tags)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID.Hex())
+	}
+	return ids, cursor.Err()
+}
+
+// tagsFilter mencocokkan array tags yang memuat salah satu tags, tanpa
+// membedakan huruf besar kecil.
+func tagsFilter(tags []string) bson.M {
+	patterns := make([]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		patterns = append(patterns, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(tag) + "$", Options: "i"})
+	}
+	return bson.M{"$in": patterns}
+}
+
+// achievementSearchIndex adalah nama text index pencarian prestasi.
+const achievementSearchIndex = "achievement_search"
+
+// EnsureAchievementSearchIndex membuat text index untuk Search jika belum
+// ada. Bobot membuat kecocokan di judul lebih relevan daripada di deskripsi.
+// Bahasa "none" mematikan stemming dan stop word bahasa Inggris karena
+// sebagian besar isi berbahasa Indonesia.
+func EnsureAchievementSearchIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("achievements").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "details.competitionName", Value: "text"},
+			{Key: "details.publicationTitle", Value: "text"},
+			{Key: "details.organizationName", Value: "text"},
+			{Key: "tags", Value: "text"},
+		},
+		Options: options.Index().
+			SetName(achievementSearchIndex).
+			SetDefaultLanguage("none").
+			SetWeights(bson.D{
+				{Key: "title", Value: 10},
+				{Key: "tags", Value: 5},
+				{Key: "details.competitionName", Value: 3},
+				{Key: "details.publicationTitle", Value: 3},
+				{Key: "details.organizationName", Value: 3},
+				{Key: "description", Value: 1},
+			}),
+	})
+	return err
+}
+
+// Search mencari dokumen aktif dengan text index, diurutkan dari skor
+// relevansi tertinggi. ids nil berarti semua dokumen; selain itu pencarian
+// dibatasi ke ids. Mengembalikan satu halaman hasil dan jumlah seluruh
+// dokumen yang cocok.
+func (r *AchievementMongoRepository) Search(
+	ctx context.Context,
+	query string,
+	ids []string,
+	limit, offset int,
+) ([]*models.AchievementSearchHit, int, error) {
+
+	filter := bson.M{
+		"$text": bson.M{"$search": query},
+		"$or": []bson.M{
+			{"deletedAt": bson.M{"$exists": false}},
+			{"deletedAt": nil},
+		},
+	}
+	if ids != nil {
+		oids := make([]primitive.ObjectID, 0, len(ids))
+		for _, id := range ids {
+			if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+				oids = append(oids, oid)
+			}
+		}
+		filter["_id"] = bson.M{"$in": oids}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var hits []*models.AchievementSearchHit
+	if err := cursor.All(ctx, &hits); err != nil {
+		return nil, 0, err
+	}
+	return hits, int(total), nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+	"uas/app/models"
+)
+
+type IAchievementOutboxRepository interface {
+	Enqueue(entry *models.OutboxEntry) error
+	Pending(now time.Time, limit int) ([]*models.OutboxEntry, error)
+	MarkProcessed(id string) error
+	MarkFailed(id, lastError string, retryAt time.Time) error
+}
+
+type AchievementOutboxRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementOutboxRepository(db *sql.DB) IAchievementOutboxRepository {
+	return &AchievementOutboxRepository{DB: db}
+}
+
+// Enqueue mencatat pesan outbox baru. AvailableAt kosong berarti segera.
+func (r *AchievementOutboxRepository) Enqueue(entry *models.OutboxEntry) error {
+	availableAt := entry.AvailableAt
+	if availableAt.IsZero() {
+		availableAt = time.Now()
+	}
+
+	return r.DB.QueryRow(`
+		INSERT INTO achievement_outbox (mongo_achievement_id, operation, available_at, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, available_at, created_at
+	`, entry.MongoAchievementID, entry.Operation, availableAt).Scan(&entry.ID, &entry.AvailableAt, &entry.CreatedAt)
+}
+
+// Pending mengembalikan pesan yang belum diproses dan sudah jatuh tempo,
+// yang terlama lebih dulu.
+func (r *AchievementOutboxRepository) Pending(now time.Time, limit int) ([]*models.OutboxEntry, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, mongo_achievement_id, operation, attempts, last_error,
+		       available_at, processed_at, created_at
+		FROM achievement_outbox
+		WHERE processed_at IS NULL
+		  AND available_at <= $1
+		ORDER BY available_at
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.OutboxEntry
+	for rows.Next() {
+		e := &models.OutboxEntry{}
+		if err := rows.Scan(
+			&e.ID,
+			&e.MongoAchievementID,
+			&e.Operation,
+			&e.Attempts,
+			&e.LastError,
+			&e.AvailableAt,
+			&e.ProcessedAt,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (r *AchievementOutboxRepository) MarkProcessed(id string) error {
+	_, err := r.DB.Exec(`
+		UPDATE achievement_outbox
+		SET processed_at=NOW()
+		WHERE id=$1
+	`, id)
+	return err
+}
+
+func (r *AchievementOutboxRepository) MarkFailed(id, lastError string, retryAt time.Time) error {
+	_, err := r.DB.Exec(`
+		UPDATE achievement_outbox
+		SET attempts=attempts+1,
+		    last_error=$2,
+		    available_at=$3
+		WHERE id=$1
+	`, id, lastError, retryAt)
+	return err
+}
+
+// enqueueOutboxTx mencatat pesan outbox di dalam transaksi tx.
+func enqueueOutboxTx(tx *sql.Tx, mongoID, operation string) (string, error) {
+	var id string
+	err := tx.QueryRow(`
+		INSERT INTO achievement_outbox (mongo_achievement_id, operation, available_at, created_at)
+		VALUES ($1, $2, NOW(), NOW())
+		RETURNING id
+	`, mongoID, operation).Scan(&id)
+	return id, err
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
     "database/sql"
+    "strings"
     "time"
     "uas/app/models"
     "github.com/lib/pq"
@@ -11,15 +12,36 @@ import (
 type IAchievementReferenceRepo interface {
     Create(ref *models.AchievementReference) (string, error)
     GetByID(id string) (*models.AchievementReference, error)
-    UpdateStatusByMongoID(mongoID string, status string, submittedAt *time.Time) error
+    UpdateStatusByMongoID(mongoID, studentID string, status string, submittedAt *time.Time) error
 	SoftDeleteByMongoID(mongoID string) error
     GetByMongoID(mongoID string) (*models.AchievementReference, error)
+    GetByMongoIDAndStudent(mongoID, studentID string) (*models.AchievementReference, error)
+    GetTeamByMongoID(mongoID string) ([]*models.AchievementReference, error)
     GetByStudentID(studentID string) ([]*models.AchievementReference, error)
     GetByStudentIDs(studentIDs []string) ([]*models.AchievementReference, error)
     GetAll() ([]*models.AchievementReference, error)
-	VerifyByMongoID( mongoID string, verifiedBy string, verifiedAt time.Time,) error
-    RejectByMongoID( mongoID string, rejectionNote string,) error
-    GetHistoryByMongoID(mongoID string) ([]*models.AchievementReference, error)
+	VerifyByMongoID( mongoID string, studentID string, verifiedBy string, verifiedAt time.Time, points int,) error
+    RejectByMongoID( mongoID string, studentID string, rejectionNote string,) error
+    RequestRevisionByMongoID(mongoID string, studentID string, feedback models.RevisionFeedback) error
+    UpdateScoringByMongoID(mongoID, studentID string, suggestedPoints int, justification *string) error
+    UpdateAwardedPoints(mongoID, studentID string, points int) error
+    GetVerifiedByFilter(from, to *time.Time, programStudy string) ([]*models.AchievementReference, error)
+    GetPendingVerifications(programStudy string, overdueOnly, escalatedOnly bool) ([]*models.PendingVerification, error)
+    MarkOverdueByMongoIDs(mongoIDs []string, at time.Time) error
+    MarkEscalatedByMongoIDs(mongoIDs []string, at time.Time) error
+    GetVerifiedCertifications(mongoIDs []string, programStudy, advisorID string) ([]*models.CertificationValidity, error)
+    MarkExpiredByMongoIDs(mongoIDs []string, at time.Time) error
+    AdvanceApprovalStage(mongoID, studentID string, fromStage int) error
+    GetDeleted(studentID string, deletedBefore *time.Time) ([]*models.AchievementReference, error)
+    RestoreByMongoID(mongoID string) (string, error)
+    HardDeleteWithOutbox(mongoID string) (string, error)
+    CreateWithOutbox(refs []*models.AchievementReference, outboxID string) error
+    SoftDeleteWithOutbox(mongoID string) (string, error)
+    RestoreWithOutbox(mongoID string) (string, string, error)
+    GetAllWithDeleted() ([]*models.AchievementReference, error)
+    List(filter models.AchievementListFilter) ([]*models.AchievementReference, int, error)
+    SetApprovalKeyByMongoID(mongoID, achievementType, competitionLevel string) error
+    GetSubmittedWithoutApprovalKey() ([]string, error)
 }
 
 type AchievementReferenceRepo struct {
@@ -31,13 +53,18 @@ func NewAchievementReferenceRepo(db *sql.DB) IAchievementReferenceRepo {
 }
 
 func (r *AchievementReferenceRepo) Create(ref *models.AchievementReference) (string, error) {
+    teamRole := ref.TeamRole
+    if teamRole == "" {
+        teamRole = models.TeamRoleOwner
+    }
+
     var id string
     err := r.DB.QueryRow(`
         INSERT INTO achievement_references 
-        (student_id, mongo_achievement_id, status, created_at, updated_at)
-        VALUES ($1, $2, $3, NOW(), NOW())
+        (student_id, mongo_achievement_id, status, team_role, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, NOW(), NOW())
         RETURNING id
-    `, ref.StudentID, ref.MongoAchievementID, ref.Status).Scan(&id)
+    `, ref.StudentID, ref.MongoAchievementID, ref.Status, teamRole).Scan(&id)
 
     return id, err
 }
@@ -48,7 +75,9 @@ func (r *AchievementReferenceRepo) GetByID(id string) (*models.AchievementRefere
     err := r.DB.QueryRow(`
         SELECT id, student_id, mongo_achievement_id, status,
                submitted_at, verified_at, verified_by, rejection_note,
-               created_at, updated_at
+               revision_count, revision_feedback,
+               suggested_points, points_justification,
+               approval_stage, created_at, updated_at
         FROM achievement_references
         WHERE id=$1
     `, id).Scan(
@@ -60,6 +89,11 @@ func (r *AchievementReferenceRepo) GetByID(id string) (*models.AchievementRefere
         &ref.VerifiedAt,
         &ref.VerifiedBy,
         &ref.RejectionNote,
+        &ref.RevisionCount,
+        &ref.RevisionFeedback,
+        &ref.SuggestedPoints,
+        &ref.PointsJustification,
+        &ref.ApprovalStage,
         &ref.CreatedAt,
         &ref.UpdatedAt,
     )
@@ -67,12 +101,21 @@ func (r *AchievementReferenceRepo) GetByID(id string) (*models.AchievementRefere
     return ref, err
 }
 
-func (r *AchievementReferenceRepo) UpdateStatusByMongoID(mongoID string, status string, submittedAt *time.Time) error {
+// UpdateStatusByMongoID mengubah status satu anggota prestasi. Referensi
+// yang sudah diverifikasi atau dihapus tidak ikut berubah. Catatan revisi
+// dihapus saat prestasi dikirim ulang.
+func (r *AchievementReferenceRepo) UpdateStatusByMongoID(mongoID, studentID string, status string, submittedAt *time.Time) error {
     _, err := r.DB.Exec(`
         UPDATE achievement_references
-        SET status=$2, submitted_at=$3, updated_at=NOW()
+        SET status=$3, submitted_at=$4,
+            overdue_at=NULL, escalated_at=NULL,
+            approval_stage=0,
+            revision_feedback=CASE WHEN $3='submitted' THEN '[]'::jsonb ELSE revision_feedback END,
+            updated_at=NOW()
         WHERE mongo_achievement_id=$1
-    `, mongoID, status, submittedAt)
+          AND student_id::text=$2
+          AND status NOT IN ('verified', 'deleted')
+    `, mongoID, studentID, status, submittedAt)
     return err
 }
 
@@ -80,8 +123,12 @@ func (r *AchievementReferenceRepo) SoftDeleteByMongoID(mongoID string) error {
     now := time.Now()
     _, err := r.DB.Exec(`
         UPDATE achievement_references
-        SET status='deleted', updated_at=$2
+        SET status_before_delete=status,
+            status='deleted',
+            deleted_at=$2,
+            updated_at=$2
         WHERE mongo_achievement_id=$1
+          AND status <> 'deleted'
     `, mongoID, now)
     return err
 }
@@ -91,7 +138,7 @@ func (r *AchievementReferenceRepo) SoftDeleteByMongoID(mongoID string) error {
 
 func (r *AchievementReferenceRepo) GetByStudentID(studentID string) ([]*models.AchievementReference, error) {
 	rows, err := r.DB.Query(`
-		SELECT id, student_id, mongo_achievement_id, status, created_at, updated_at
+		SELECT id, student_id, mongo_achievement_id, status, approval_stage, created_at, updated_at
 		FROM achievement_references
 		WHERE student_id=$1 AND status<>'deleted'
 	`, studentID)
@@ -103,7 +150,7 @@ func (r *AchievementReferenceRepo) GetByStudentID(studentID string) ([]*models.A
 	var refs []*models.AchievementReference
 	for rows.Next() {
 		ref := &models.AchievementReference{}
-		err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.CreatedAt, &ref.UpdatedAt)
+		err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.ApprovalStage, &ref.CreatedAt, &ref.UpdatedAt)
 		if err != nil {
 			continue
 		}
@@ -112,15 +159,72 @@ func (r *AchievementReferenceRepo) GetByStudentID(studentID string) ([]*models.A
 	return refs, nil
 }
 
+// GetByMongoID mengembalikan referensi pemilik entri (owner). TeamSize
+// berisi jumlah anggota prestasi.
 func (r *AchievementReferenceRepo) GetByMongoID(mongoID string) (*models.AchievementReference, error) {
-    ref := &models.AchievementReference{}
-    err := r.DB.QueryRow(`
+    return scanReference(r.DB.QueryRow(`
+        SELECT id, student_id, mongo_achievement_id, status,
+               submitted_at, verified_at, verified_by, rejection_note,
+               revision_count, revision_feedback,
+               suggested_points, points_justification,
+               approval_stage, team_role, COUNT(*) OVER (), points,
+               created_at, updated_at
+        FROM achievement_references
+        WHERE mongo_achievement_id=$1
+        ORDER BY (team_role = 'owner') DESC
+        LIMIT 1
+    `, mongoID))
+}
+
+// GetByMongoIDAndStudent mengembalikan referensi satu anggota prestasi.
+func (r *AchievementReferenceRepo) GetByMongoIDAndStudent(mongoID, studentID string) (*models.AchievementReference, error) {
+    return scanReference(r.DB.QueryRow(`
+        SELECT id, student_id, mongo_achievement_id, status,
+               submitted_at, verified_at, verified_by, rejection_note,
+               revision_count, revision_feedback,
+               suggested_points, points_justification,
+               approval_stage, team_role,
+               (SELECT COUNT(*) FROM achievement_references t WHERE t.mongo_achievement_id = ar.mongo_achievement_id),
+               points, created_at, updated_at
+        FROM achievement_references ar
+        WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
+    `, mongoID, studentID))
+}
+
+// GetTeamByMongoID mengembalikan referensi semua anggota prestasi, pemilik
+// entri lebih dulu.
+func (r *AchievementReferenceRepo) GetTeamByMongoID(mongoID string) ([]*models.AchievementReference, error) {
+    rows, err := r.DB.Query(`
         SELECT id, student_id, mongo_achievement_id, status,
                submitted_at, verified_at, verified_by, rejection_note,
+               revision_count, revision_feedback,
+               suggested_points, points_justification,
+               approval_stage, team_role, COUNT(*) OVER (), points,
                created_at, updated_at
         FROM achievement_references
         WHERE mongo_achievement_id=$1
-    `, mongoID).Scan(
+        ORDER BY (team_role = 'owner') DESC, created_at
+    `, mongoID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var refs []*models.AchievementReference
+    for rows.Next() {
+        ref, err := scanReference(rows)
+        if err != nil {
+            return nil, err
+        }
+        refs = append(refs, ref)
+    }
+    return refs, nil
+}
+
+func scanReference(row rowScanner) (*models.AchievementReference, error) {
+    ref := &models.AchievementReference{}
+    err := row.Scan(
         &ref.ID,
         &ref.StudentID,
         &ref.MongoAchievementID,
@@ -129,17 +233,33 @@ func (r *AchievementReferenceRepo) GetByMongoID(mongoID string) (*models.Achieve
         &ref.VerifiedAt,
         &ref.VerifiedBy,
         &ref.RejectionNote,
+        &ref.RevisionCount,
+        &ref.RevisionFeedback,
+        &ref.SuggestedPoints,
+        &ref.PointsJustification,
+        &ref.ApprovalStage,
+        &ref.TeamRole,
+        &ref.TeamSize,
+        &ref.Points,
         &ref.CreatedAt,
         &ref.UpdatedAt,
     )
-    return ref, err
+    if err != nil {
+        return nil, err
+    }
+    return ref, nil
 }
 
+// GetByStudentIDs mengembalikan referensi aktif milik banyak mahasiswa
+// dalam satu query.
 func (r *AchievementReferenceRepo) GetByStudentIDs(studentIDs []string) ([]*models.AchievementReference, error) {
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, created_at, updated_at
+		SELECT id, student_id, mongo_achievement_id, status,
+		       submitted_at, verified_at, verified_by, rejection_note,
+		       approval_stage, team_role, points, created_at, updated_at
 		FROM achievement_references
 		WHERE student_id = ANY($1) AND status <> 'deleted'
+		ORDER BY created_at DESC
 	`
 
 	rows, err := r.DB.Query(query, pq.Array(studentIDs))
@@ -156,21 +276,28 @@ func (r *AchievementReferenceRepo) GetByStudentIDs(studentIDs []string) ([]*mode
 			&ref.StudentID,
 			&ref.MongoAchievementID,
 			&ref.Status,
+			&ref.SubmittedAt,
+			&ref.VerifiedAt,
+			&ref.VerifiedBy,
+			&ref.RejectionNote,
+			&ref.ApprovalStage,
+			&ref.TeamRole,
+			&ref.Points,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		); err != nil {
-			continue
+			return nil, err
 		}
 		refs = append(refs, ref)
 	}
 
-	return refs, nil
+	return refs, rows.Err()
 }
 
 func (r *AchievementReferenceRepo) GetAll() ([]*models.AchievementReference, error) {
 	rows, err := r.DB.Query(`
 		SELECT id, student_id, mongo_achievement_id, status,
-		       submitted_at, created_at, updated_at
+		       submitted_at, approval_stage, created_at, updated_at
 		FROM achievement_references
 		WHERE status <> 'deleted'
 	`)
@@ -188,6 +315,7 @@ func (r *AchievementReferenceRepo) GetAll() ([]*models.AchievementReference, err
 			&ref.MongoAchievementID,
 			&ref.Status,
 			&ref.SubmittedAt,
+			&ref.ApprovalStage,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		)
@@ -201,8 +329,10 @@ func (r *AchievementReferenceRepo) GetAll() ([]*models.AchievementReference, err
 
 func (r *AchievementReferenceRepo) VerifyByMongoID(
     mongoID string,
+    studentID string,
     verifiedBy string,
     verifiedAt time.Time,
+    points int,
 ) error {
 
     fmt.Println("DEBUG: VerifyByMongoID called")
@@ -213,12 +343,15 @@ func (r *AchievementReferenceRepo) VerifyByMongoID(
     res, err := r.DB.Exec(`
         UPDATE achievement_references
         SET status='verified',
-            verified_by=$2,
-            verified_at=$3,
+            verified_by=$3,
+            verified_at=$4,
+            points=$5,
+            revision_feedback='[]',
             updated_at=NOW()
         WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
           AND status='submitted'
-    `, mongoID, verifiedBy, verifiedAt)
+    `, mongoID, studentID, verifiedBy, verifiedAt, points)
 
     if err != nil {
         fmt.Println("DEBUG: update error =", err)
@@ -238,54 +371,663 @@ func (r *AchievementReferenceRepo) VerifyByMongoID(
 
 func (r *AchievementReferenceRepo) RejectByMongoID(
     mongoID string,
+    studentID string,
     note string,
 ) error {
     _, err := r.DB.Exec(`
         UPDATE achievement_references
         SET status='rejected',
-            rejection_note=$2,
+            rejection_note=$3,
+            approval_stage=0,
             updated_at=NOW()
         WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
           AND status='submitted'
-    `, mongoID, note)
+    `, mongoID, studentID, note)
 
     return err
 }
 
-func (r *AchievementReferenceRepo) GetHistoryByMongoID(mongoID string) ([]*models.AchievementReference, error) {
+func (r *AchievementReferenceRepo) RequestRevisionByMongoID(
+    mongoID string,
+    studentID string,
+    feedback models.RevisionFeedback,
+) error {
+    res, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET status='needs_revision',
+            revision_feedback=$3,
+            revision_count=revision_count+1,
+            approval_stage=0,
+            updated_at=NOW()
+        WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
+          AND status='submitted'
+    `, mongoID, studentID, feedback)
+    if err != nil {
+        return err
+    }
+
+    rowsAffected, _ := res.RowsAffected()
+    if rowsAffected == 0 {
+        return fmt.Errorf("no rows updated: cek mongoID atau status")
+    }
+    return nil
+}
+
+// UpdateScoringByMongoID menyimpan poin usulan rubrik dan justifikasi
+// override untuk satu anggota prestasi.
+func (r *AchievementReferenceRepo) UpdateScoringByMongoID(
+    mongoID string,
+    studentID string,
+    suggestedPoints int,
+    justification *string,
+) error {
+    _, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET suggested_points=$3,
+            points_justification=$4,
+            updated_at=NOW()
+        WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
+    `, mongoID, studentID, suggestedPoints, justification)
+    return err
+}
+
+// UpdateAwardedPoints menyimpan poin yang diterima satu anggota atas
+// prestasi terverifikasi.
+func (r *AchievementReferenceRepo) UpdateAwardedPoints(mongoID, studentID string, points int) error {
+    _, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET points=$3,
+            updated_at=NOW()
+        WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
+          AND status='verified'
+    `, mongoID, studentID, points)
+    return err
+}
+
+func (r *AchievementReferenceRepo) GetVerifiedByFilter(
+    from, to *time.Time,
+    programStudy string,
+) ([]*models.AchievementReference, error) {
+    rows, err := r.DB.Query(`
+        SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+               ar.verified_at, ar.suggested_points, ar.points_justification,
+               ar.points, ar.team_role,
+               (SELECT COUNT(*) FROM achievement_references t
+                WHERE t.mongo_achievement_id = ar.mongo_achievement_id),
+               ar.created_at, ar.updated_at
+        FROM achievement_references ar
+        JOIN students s ON s.id = ar.student_id
+        WHERE ar.status = 'verified'
+          AND ($1::timestamp IS NULL OR ar.verified_at >= $1)
+          AND ($2::timestamp IS NULL OR ar.verified_at <= $2)
+          AND ($3 = '' OR s.program_study = $3)
+        ORDER BY ar.student_id, ar.verified_at
+    `, from, to, programStudy)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var refs []*models.AchievementReference
+    for rows.Next() {
+        ref := &models.AchievementReference{}
+        if err := rows.Scan(
+            &ref.ID,
+            &ref.StudentID,
+            &ref.MongoAchievementID,
+            &ref.Status,
+            &ref.VerifiedAt,
+            &ref.SuggestedPoints,
+            &ref.PointsJustification,
+            &ref.Points,
+            &ref.TeamRole,
+            &ref.TeamSize,
+            &ref.CreatedAt,
+            &ref.UpdatedAt,
+        ); err != nil {
+            return nil, err
+        }
+        refs = append(refs, ref)
+    }
+    return refs, nil
+}
+
+// GetPendingVerifications mengembalikan prestasi berstatus submitted beserta
+// program studi dan dosen wali mahasiswanya, urut dari yang paling lama.
+func (r *AchievementReferenceRepo) GetPendingVerifications(
+    programStudy string,
+    overdueOnly, escalatedOnly bool,
+) ([]*models.PendingVerification, error) {
+    rows, err := r.DB.Query(`
+        SELECT ar.mongo_achievement_id, ar.student_id, s.program_study, s.advisor_id,
+               ar.submitted_at, ar.overdue_at, ar.escalated_at
+        FROM achievement_references ar
+        JOIN students s ON s.id = ar.student_id
+        WHERE ar.status = 'submitted'
+          AND ($1 = '' OR s.program_study = $1)
+          AND (NOT $2 OR ar.overdue_at IS NOT NULL)
+          AND (NOT $3 OR ar.escalated_at IS NOT NULL)
+        ORDER BY ar.submitted_at
+    `, programStudy, overdueOnly, escalatedOnly)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var pending []*models.PendingVerification
+    for rows.Next() {
+        p := &models.PendingVerification{}
+        if err := rows.Scan(
+            &p.MongoAchievementID,
+            &p.StudentID,
+            &p.ProgramStudy,
+            &p.AdvisorID,
+            &p.SubmittedAt,
+            &p.OverdueAt,
+            &p.EscalatedAt,
+        ); err != nil {
+            return nil, err
+        }
+        pending = append(pending, p)
+    }
+    return pending, nil
+}
+
+func (r *AchievementReferenceRepo) MarkOverdueByMongoIDs(mongoIDs []string, at time.Time) error {
+    _, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET overdue_at=$2
+        WHERE mongo_achievement_id = ANY($1)
+          AND status='submitted'
+          AND overdue_at IS NULL
+    `, pq.Array(mongoIDs), at)
+    return err
+}
+
+func (r *AchievementReferenceRepo) MarkEscalatedByMongoIDs(mongoIDs []string, at time.Time) error {
+    _, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET escalated_at=$2,
+            overdue_at=COALESCE(overdue_at, $2)
+        WHERE mongo_achievement_id = ANY($1)
+          AND status='submitted'
+          AND escalated_at IS NULL
+    `, pq.Array(mongoIDs), at)
+    return err
+}
+
+// GetVerifiedCertifications mengembalikan referensi terverifikasi untuk
+// mongoIDs beserta program studi dan dosen wali mahasiswanya. Filter
+// kosong diabaikan.
+func (r *AchievementReferenceRepo) GetVerifiedCertifications(
+    mongoIDs []string,
+    programStudy, advisorID string,
+) ([]*models.CertificationValidity, error) {
+    rows, err := r.DB.Query(`
+        SELECT ar.mongo_achievement_id, ar.student_id, s.program_study, s.advisor_id,
+               ar.expired_at
+        FROM achievement_references ar
+        JOIN students s ON s.id = ar.student_id
+        WHERE ar.mongo_achievement_id = ANY($1)
+          AND ar.status = 'verified'
+          AND ($2 = '' OR s.program_study = $2)
+          AND ($3 = '' OR s.advisor_id::text = $3)
+    `, pq.Array(mongoIDs), programStudy, advisorID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var certs []*models.CertificationValidity
+    for rows.Next() {
+        c := &models.CertificationValidity{}
+        if err := rows.Scan(
+            &c.MongoAchievementID,
+            &c.StudentID,
+            &c.ProgramStudy,
+            &c.AdvisorID,
+            &c.ExpiredAt,
+        ); err != nil {
+            return nil, err
+        }
+        certs = append(certs, c)
+    }
+    return certs, nil
+}
+
+func (r *AchievementReferenceRepo) MarkExpiredByMongoIDs(mongoIDs []string, at time.Time) error {
+    _, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET expired_at=$2
+        WHERE mongo_achievement_id = ANY($1)
+          AND status='verified'
+          AND expired_at IS NULL
+    `, pq.Array(mongoIDs), at)
+    return err
+}
+
+// AdvanceApprovalStage memindahkan prestasi submitted ke tahap persetujuan
+// berikutnya. Gagal jika tahap sudah berubah (sign-off ganda bersamaan).
+func (r *AchievementReferenceRepo) AdvanceApprovalStage(mongoID, studentID string, fromStage int) error {
+    res, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET approval_stage=approval_stage+1,
+            updated_at=NOW()
+        WHERE mongo_achievement_id=$1
+          AND student_id::text=$2
+          AND status='submitted'
+          AND approval_stage=$3
+    `, mongoID, studentID, fromStage)
+    if err != nil {
+        return err
+    }
+
+    rowsAffected, _ := res.RowsAffected()
+    if rowsAffected == 0 {
+        return fmt.Errorf("no rows updated: cek mongoID atau tahap persetujuan")
+    }
+    return nil
+}
+
+// GetDeleted mengembalikan prestasi di tempat sampah, terbaru lebih dulu.
+// studentID kosong berarti semua mahasiswa; deletedBefore membatasi ke
+// yang dihapus sebelum waktu tersebut.
+func (r *AchievementReferenceRepo) GetDeleted(studentID string, deletedBefore *time.Time) ([]*models.AchievementReference, error) {
     rows, err := r.DB.Query(`
         SELECT id, student_id, mongo_achievement_id, status,
-               submitted_at, verified_at, verified_by, rejection_note,
-               created_at, updated_at
+               deleted_at, status_before_delete, created_at, updated_at
         FROM achievement_references
+        WHERE status='deleted'
+          AND ($1 = '' OR student_id::text = $1)
+          AND ($2::timestamp IS NULL OR COALESCE(deleted_at, updated_at) < $2)
+        ORDER BY COALESCE(deleted_at, updated_at) DESC
+    `, studentID, deletedBefore)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var refs []*models.AchievementReference
+    for rows.Next() {
+        ref := &models.AchievementReference{}
+        if err := rows.Scan(
+            &ref.ID,
+            &ref.StudentID,
+            &ref.MongoAchievementID,
+            &ref.Status,
+            &ref.DeletedAt,
+            &ref.StatusBeforeDelete,
+            &ref.CreatedAt,
+            &ref.UpdatedAt,
+        ); err != nil {
+            return nil, err
+        }
+        if ref.DeletedAt == nil {
+            ref.DeletedAt = &ref.UpdatedAt
+        }
+        refs = append(refs, ref)
+    }
+    return refs, nil
+}
+
+// RestoreByMongoID mengembalikan status sebelum dihapus (draft jika tidak
+// tercatat) dan mengembalikan status tersebut.
+func (r *AchievementReferenceRepo) RestoreByMongoID(mongoID string) (string, error) {
+    var status string
+    err := r.DB.QueryRow(`
+        UPDATE achievement_references
+        SET status=COALESCE(status_before_delete, 'draft'),
+            status_before_delete=NULL,
+            deleted_at=NULL,
+            updated_at=NOW()
+        WHERE mongo_achievement_id=$1
+          AND status='deleted'
+        RETURNING status
+    `, mongoID).Scan(&status)
+    return status, err
+}
+
+// HardDeleteWithOutbox menghapus permanen baris referensi beserta
+// persetujuan dan komentarnya, lalu mencatat pesan discard_document dalam
+// transaksi yang sama agar dokumen Mongo-nya tetap terhapus walaupun
+// pembersihan setelahnya gagal. Riwayat status append-only tidak dihapus.
+// Mengembalikan ID pesan outbox.
+func (r *AchievementReferenceRepo) HardDeleteWithOutbox(mongoID string) (string, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return "", err
+    }
+    defer tx.Rollback()
+
+    for _, q := range []string{
+        `DELETE FROM achievement_approvals WHERE mongo_achievement_id=$1`,
+        `DELETE FROM achievement_comment_reads WHERE mongo_achievement_id=$1`,
+        `DELETE FROM achievement_comments WHERE mongo_achievement_id=$1`,
+    } {
+        if _, err := tx.Exec(q, mongoID); err != nil {
+            return "", err
+        }
+    }
+
+    res, err := tx.Exec(`
+        DELETE FROM achievement_references
         WHERE mongo_achievement_id=$1
-        ORDER BY created_at ASC
+          AND status='deleted'
     `, mongoID)
+    if err != nil {
+        return "", err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return "", sql.ErrNoRows
+    }
+
+    outboxID, err := enqueueOutboxTx(tx, mongoID, models.OutboxOpDiscardDocument)
+    if err != nil {
+        return "", err
+    }
+
+    return outboxID, tx.Commit()
+}
+
+// CreateWithOutbox menyimpan referensi pemilik dan anggota tim dalam satu
+// transaksi, sekaligus menandai pesan kompensasi outboxID selesai sehingga
+// dokumen Mongo-nya tidak dibuang relay. outboxID kosong dilewati.
+func (r *AchievementReferenceRepo) CreateWithOutbox(refs []*models.AchievementReference, outboxID string) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, ref := range refs {
+        teamRole := ref.TeamRole
+        if teamRole == "" {
+            teamRole = models.TeamRoleOwner
+        }
+        if err := tx.QueryRow(`
+            INSERT INTO achievement_references
+            (student_id, mongo_achievement_id, status, team_role, created_at, updated_at)
+            VALUES ($1, $2, $3, $4, NOW(), NOW())
+            RETURNING id
+        `, ref.StudentID, ref.MongoAchievementID, ref.Status, teamRole).Scan(&ref.ID); err != nil {
+            return err
+        }
+    }
+
+    if outboxID != "" {
+        if _, err := tx.Exec(`
+            UPDATE achievement_outbox
+            SET processed_at=NOW()
+            WHERE id=$1
+        `, outboxID); err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// SoftDeleteWithOutbox menandai referensi terhapus seperti
+// SoftDeleteByMongoID dan mencatat pesan outbox untuk soft delete dokumen
+// Mongo dalam satu transaksi. Tidak ada yang dihapus selama salah satu
+// anggota masih submitted atau sudah verified (sql.ErrNoRows). Mengembalikan
+// ID pesan outbox.
+func (r *AchievementReferenceRepo) SoftDeleteWithOutbox(mongoID string) (string, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return "", err
+    }
+    defer tx.Rollback()
+
+    res, err := tx.Exec(`
+        UPDATE achievement_references
+        SET status_before_delete=status,
+            status='deleted',
+            deleted_at=NOW(),
+            updated_at=NOW()
+        WHERE mongo_achievement_id=$1
+          AND status <> 'deleted'
+          AND NOT EXISTS (
+              SELECT 1 FROM achievement_references locked
+              WHERE locked.mongo_achievement_id=$1
+                AND locked.status IN ('submitted', 'verified')
+          )
+    `, mongoID)
+    if err != nil {
+        return "", err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return "", sql.ErrNoRows
+    }
+
+    outboxID, err := enqueueOutboxTx(tx, mongoID, models.OutboxOpSoftDeleteDocument)
+    if err != nil {
+        return "", err
+    }
+
+    return outboxID, tx.Commit()
+}
+
+// RestoreWithOutbox memulihkan referensi seperti RestoreByMongoID dan
+// mencatat pesan outbox untuk memulihkan dokumen Mongo dalam satu
+// transaksi. Mengembalikan status hasil pemulihan dan ID pesan outbox.
+func (r *AchievementReferenceRepo) RestoreWithOutbox(mongoID string) (string, string, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return "", "", err
+    }
+    defer tx.Rollback()
+
+    var status string
+    err = tx.QueryRow(`
+        WITH restored AS (
+            UPDATE achievement_references
+            SET status=COALESCE(status_before_delete, 'draft'),
+                status_before_delete=NULL,
+                deleted_at=NULL,
+                updated_at=NOW()
+            WHERE mongo_achievement_id=$1
+              AND status='deleted'
+            RETURNING status, team_role
+        )
+        SELECT status FROM restored
+        ORDER BY (team_role = 'owner') DESC
+        LIMIT 1
+    `, mongoID).Scan(&status)
+    if err != nil {
+        return "", "", err
+    }
+
+    outboxID, err := enqueueOutboxTx(tx, mongoID, models.OutboxOpRestoreDocument)
+    if err != nil {
+        return "", "", err
+    }
+
+    return status, outboxID, tx.Commit()
+}
+
+// GetAllWithDeleted mengembalikan semua referensi termasuk yang ada di
+// tempat sampah, untuk dicocokkan dengan dokumen Mongo.
+func (r *AchievementReferenceRepo) GetAllWithDeleted() ([]*models.AchievementReference, error) {
+    rows, err := r.DB.Query(`
+        SELECT id, student_id, mongo_achievement_id, status, team_role,
+               created_at, updated_at
+        FROM achievement_references
+        ORDER BY created_at
+    `)
     if err != nil {
         return nil, err
     }
     defer rows.Close()
 
-    var history []*models.AchievementReference
+    var refs []*models.AchievementReference
+    for rows.Next() {
+        ref := &models.AchievementReference{}
+        if err := rows.Scan(
+            &ref.ID,
+            &ref.StudentID,
+            &ref.MongoAchievementID,
+            &ref.Status,
+            &ref.TeamRole,
+            &ref.CreatedAt,
+            &ref.UpdatedAt,
+        ); err != nil {
+            return nil, err
+        }
+        refs = append(refs, ref)
+    }
+    return refs, rows.Err()
+}
+
+// achievementSortColumns memetakan kunci sort ke kolom; hanya kolom di sini
+// yang boleh masuk ORDER BY.
+var achievementSortColumns = map[string]string{
+    models.AchievementSortCreatedAt:   "ar.created_at",
+    models.AchievementSortUpdatedAt:   "ar.updated_at",
+    models.AchievementSortSubmittedAt: "ar.submitted_at",
+    models.AchievementSortStatus:      "ar.status",
+    models.AchievementSortPoints:      "ar.points",
+}
+
+// approvalStageRole menghitung role tahap persetujuan referensi ar saat ini
+// dari approval_chains, sama seperti ResolveApprovalChain dan currentStage:
+// rantai untuk tingkat lomba yang cocok didahulukan, lalu rantai untuk semua
+// tingkat, lalu tahap bawaan ($11). Hasilnya huruf kecil.
+const approvalStageRole = `
+    LOWER(COALESCE((
+        SELECT c.stages -> GREATEST(0, LEAST(ar.approval_stage, jsonb_array_length(c.stages) - 1)) ->> 'role'
+        FROM approval_chains c
+        WHERE jsonb_array_length(c.stages) > 0
+          AND LOWER(c.achievement_type) = LOWER(ar.achievement_type)
+          AND (c.competition_level = ''
+               OR (ar.competition_level <> '' AND LOWER(c.competition_level) = LOWER(ar.competition_level)))
+        ORDER BY c.competition_level = ''
+        LIMIT 1
+    ), $11))`
+
+// List mengembalikan satu halaman referensi yang cocok dengan filter beserta
+// jumlah seluruh referensi yang cocok. Referensi di tempat sampah tidak
+// ikut.
+func (r *AchievementReferenceRepo) List(filter models.AchievementListFilter) ([]*models.AchievementReference, int, error) {
+    where := `
+        FROM achievement_references ar
+        JOIN students s ON s.id = ar.student_id
+        WHERE ar.status <> 'deleted'
+          AND ($1::text[] IS NULL OR ar.student_id::text = ANY($1))
+          AND ($2::text[] IS NULL OR ar.mongo_achievement_id = ANY($2))
+          AND ($3::text[] IS NULL OR ar.id::text = ANY($3))
+          AND ($4::text[] IS NULL OR ar.status = ANY($4))
+          AND ($5 = '' OR s.program_study = $5)
+          AND ($6::timestamp IS NULL OR ar.created_at >= $6)
+          AND ($7::timestamp IS NULL OR ar.created_at <= $7)
+          AND ($8::text[] IS NULL OR s.advisor_id::text = ANY($8))
+          AND ($10::text[] IS NULL OR ` + approvalStageRole + ` = ANY($10)
+               OR ($9 AND ar.status = 'submitted' AND ar.escalated_at IS NOT NULL))
+    `
+    args := []interface{}{
+        pq.Array(filter.StudentIDs),
+        pq.Array(filter.MongoIDs),
+        pq.Array(filter.RefIDs),
+        pq.Array(filter.Statuses),
+        filter.ProgramStudy,
+        filter.From,
+        filter.To,
+        pq.Array(filter.AdvisorIDs),
+        filter.IncludeEscalated,
+        pq.Array(filter.StageRoles),
+        strings.ToLower(models.DefaultApprovalStages[0].Role),
+    }
+
+    var total int
+    if err := r.DB.QueryRow(`SELECT COUNT(*) `+where, args...).Scan(&total); err != nil {
+        return nil, 0, err
+    }
+
+    column, ok := achievementSortColumns[filter.Sort]
+    if !ok {
+        column = "ar.created_at"
+    }
+    order := "ASC NULLS LAST"
+    if filter.Desc {
+        order = "DESC NULLS LAST"
+    }
+
+    query := `
+        SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+               ar.submitted_at, ar.approval_stage, ar.team_role, ar.points,
+               ar.created_at, ar.updated_at
+    ` + where + fmt.Sprintf(" ORDER BY %s %s, ar.id", column, order)
+
+    if filter.Limit > 0 {
+        args = append(args, filter.Limit, filter.Offset)
+        query += " LIMIT $12 OFFSET $13"
+    }
+
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, 0, err
+    }
+    defer rows.Close()
+
+    var refs []*models.AchievementReference
     for rows.Next() {
         ref := &models.AchievementReference{}
-        err := rows.Scan(
+        if err := rows.Scan(
             &ref.ID,
             &ref.StudentID,
             &ref.MongoAchievementID,
             &ref.Status,
             &ref.SubmittedAt,
-            &ref.VerifiedAt,
-            &ref.VerifiedBy,
-            &ref.RejectionNote,
+            &ref.ApprovalStage,
+            &ref.TeamRole,
+            &ref.Points,
             &ref.CreatedAt,
             &ref.UpdatedAt,
-        )
-        if err != nil {
-            continue
+        ); err != nil {
+            return nil, 0, err
         }
-        history = append(history, ref)
+        refs = append(refs, ref)
     }
+    return refs, total, rows.Err()
+}
 
-    return history, nil
+// SetApprovalKeyByMongoID menyalin tipe prestasi dan tingkat lomba ke semua
+// referensi dokumen untuk perhitungan tahap persetujuan di List.
+func (r *AchievementReferenceRepo) SetApprovalKeyByMongoID(mongoID, achievementType, competitionLevel string) error {
+    _, err := r.DB.Exec(`
+        UPDATE achievement_references
+        SET achievement_type=$2,
+            competition_level=$3
+        WHERE mongo_achievement_id=$1
+    `, mongoID, achievementType, competitionLevel)
+    return err
+}
+
+// GetSubmittedWithoutApprovalKey mengembalikan ID dokumen prestasi submitted
+// yang dikirim sebelum tipe dan tingkatnya disalin ke referensi.
+func (r *AchievementReferenceRepo) GetSubmittedWithoutApprovalKey() ([]string, error) {
+    rows, err := r.DB.Query(`
+        SELECT DISTINCT mongo_achievement_id
+        FROM achievement_references
+        WHERE status='submitted'
+          AND achievement_type IS NULL
+    `)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var ids []string
+    for rows.Next() {
+        var id string
+        if err := rows.Scan(&id); err != nil {
+            return nil, err
+        }
+        ids = append(ids, id)
+    }
+    return ids, rows.Err()
 }
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"uas/app/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IAchievementRevisionRepository interface {
+	Append(ctx context.Context, rev *models.AchievementRevision) error
+	Latest(ctx context.Context, achievementID string) (*models.AchievementRevision, error)
+	FindByAchievementID(ctx context.Context, achievementID string) ([]*models.AchievementRevision, error)
+	DeleteByAchievementID(ctx context.Context, achievementID string) error
+}
+
+type AchievementRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAchievementRevisionRepository(db *mongo.Database) IAchievementRevisionRepository {
+	return &AchievementRevisionRepository{
+		collection: db.Collection("achievement_revisions"),
+	}
+}
+
+// maxRevisionAppendAttempts membatasi percobaan ulang Append saat nomor
+// versi sudah dipakai edit lain yang berjalan bersamaan.
+const maxRevisionAppendAttempts = 5
+
+// EnsureAchievementRevisionIndex membuat indeks unik (achievementId,
+// version) jika belum ada, sehingga dua revisi tidak bisa mendapat nomor
+// versi yang sama.
+func EnsureAchievementRevisionIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("achievement_revisions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "achievementId", Value: 1},
+			{Key: "version", Value: 1},
+		},
+		Options: options.Index().SetName("achievement_revision_version").SetUnique(true),
+	})
+	return err
+}
+
+// Append menyimpan revisi dengan nomor versi berikutnya. Jika nomor itu
+// sudah diambil revisi lain, versi dihitung ulang dan penyimpanan dicoba
+// lagi.
+func (r *AchievementRevisionRepository) Append(ctx context.Context, rev *models.AchievementRevision) error {
+	if rev.CreatedAt.IsZero() {
+		rev.CreatedAt = time.Now()
+	}
+
+	var err error
+	for attempt := 0; attempt < maxRevisionAppendAttempts; attempt++ {
+		var latest *models.AchievementRevision
+		latest, err = r.Latest(ctx, rev.AchievementID)
+		if err != nil {
+			return err
+		}
+
+		rev.Version = 1
+		if latest != nil {
+			rev.Version = latest.Version + 1
+		}
+
+		_, err = r.collection.InsertOne(ctx, rev)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Latest mengembalikan nil tanpa error jika prestasi belum punya revisi.
+func (r *AchievementRevisionRepository) Latest(ctx context.Context, achievementID string) (*models.AchievementRevision, error) {
+	var rev models.AchievementRevision
+
+	err := r.collection.FindOne(ctx,
+		bson.M{"achievementId": achievementID},
+		options.FindOne().SetSort(bson.M{"version": -1}),
+	).Decode(&rev)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+func (r *AchievementRevisionRepository) FindByAchievementID(
+	ctx context.Context,
+	achievementID string,
+) ([]*models.AchievementRevision, error) {
+
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"achievementId": achievementID},
+		options.Find().SetSort(bson.M{"version": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []*models.AchievementRevision
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *AchievementRevisionRepository) DeleteByAchievementID(ctx context.Context, achievementID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"achievementId": achievementID})
+	return err
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type IAchievementTypeRepository interface {
+	FindAll() ([]*models.AchievementTypeDefinition, error)
+	FindByCode(code string) (*models.AchievementTypeDefinition, error)
+	Create(def *models.AchievementTypeDefinition) error
+	Update(def *models.AchievementTypeDefinition) error
+	Delete(code string) error
+}
+
+type AchievementTypeRepository struct {
+	DB *sql.DB
+}
+
+func NewAchievementTypeRepository(db *sql.DB) IAchievementTypeRepository {
+	return &AchievementTypeRepository{DB: db}
+}
+
+const achievementTypeColumns = `
+	id, code, name, description, custom_fields_schema, default_points, created_at, updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAchievementType(row rowScanner) (*models.AchievementTypeDefinition, error) {
+	def := &models.AchievementTypeDefinition{}
+	var schema []byte
+	if err := row.Scan(
+		&def.ID,
+		&def.Code,
+		&def.Name,
+		&def.Description,
+		&schema,
+		&def.DefaultPoints,
+		&def.CreatedAt,
+		&def.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	def.CustomFieldsSchema = schema
+	return def, nil
+}
+
+func (r *AchievementTypeRepository) FindAll() ([]*models.AchievementTypeDefinition, error) {
+	rows, err := r.DB.Query(`
+		SELECT` + achievementTypeColumns + `
+		FROM achievement_types
+		ORDER BY code
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var defs []*models.AchievementTypeDefinition
+	for rows.Next() {
+		def, err := scanAchievementType(rows)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func (r *AchievementTypeRepository) FindByCode(code string) (*models.AchievementTypeDefinition, error) {
+	return scanAchievementType(r.DB.QueryRow(`
+		SELECT`+achievementTypeColumns+`
+		FROM achievement_types
+		WHERE code = LOWER($1)
+	`, code))
+}
+
+func (r *AchievementTypeRepository) Create(def *models.AchievementTypeDefinition) error {
+	return r.DB.QueryRow(`
+		INSERT INTO achievement_types
+		(code, name, description, custom_fields_schema, default_points, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`,
+		def.Code,
+		def.Name,
+		def.Description,
+		[]byte(def.CustomFieldsSchema),
+		def.DefaultPoints,
+	).Scan(&def.ID, &def.CreatedAt, &def.UpdatedAt)
+}
+
+func (r *AchievementTypeRepository) Update(def *models.AchievementTypeDefinition) error {
+	return r.DB.QueryRow(`
+		UPDATE achievement_types
+		SET name=$2, description=$3, custom_fields_schema=$4, default_points=$5, updated_at=NOW()
+		WHERE code=$1
+		RETURNING id, created_at, updated_at
+	`,
+		def.Code,
+		def.Name,
+		def.Description,
+		[]byte(def.CustomFieldsSchema),
+		def.DefaultPoints,
+	).Scan(&def.ID, &def.CreatedAt, &def.UpdatedAt)
+}
+
+func (r *AchievementTypeRepository) Delete(code string) error {
+	res, err := r.DB.Exec(`DELETE FROM achievement_types WHERE code=$1`, code)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type IApprovalChainRepository interface {
+	FindAll() ([]*models.ApprovalChain, error)
+	Upsert(chain *models.ApprovalChain) error
+	Delete(id string) error
+}
+
+type ApprovalChainRepository struct {
+	DB *sql.DB
+}
+
+func NewApprovalChainRepository(db *sql.DB) IApprovalChainRepository {
+	return &ApprovalChainRepository{DB: db}
+}
+
+func (r *ApprovalChainRepository) FindAll() ([]*models.ApprovalChain, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, achievement_type, competition_level, stages, created_at, updated_at
+		FROM approval_chains
+		ORDER BY achievement_type, competition_level
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chains []*models.ApprovalChain
+	for rows.Next() {
+		chain := &models.ApprovalChain{}
+		if err := rows.Scan(
+			&chain.ID,
+			&chain.AchievementType,
+			&chain.CompetitionLevel,
+			&chain.Stages,
+			&chain.CreatedAt,
+			&chain.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+func (r *ApprovalChainRepository) Upsert(chain *models.ApprovalChain) error {
+	return r.DB.QueryRow(`
+		INSERT INTO approval_chains (achievement_type, competition_level, stages, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (achievement_type, competition_level)
+		DO UPDATE SET stages = EXCLUDED.stages, updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`,
+		chain.AchievementType,
+		chain.CompetitionLevel,
+		chain.Stages,
+	).Scan(&chain.ID, &chain.CreatedAt, &chain.UpdatedAt)
+}
+
+func (r *ApprovalChainRepository) Delete(id string) error {
+	res, err := r.DB.Exec(`DELETE FROM approval_chains WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type ICertificationNotificationRepository interface {
+	Create(n *models.CertificationNotification) (bool, error)
+	GetByRecipient(role, recipientID string) ([]*models.CertificationNotification, error)
+}
+
+type CertificationNotificationRepository struct {
+	DB *sql.DB
+}
+
+func NewCertificationNotificationRepository(db *sql.DB) ICertificationNotificationRepository {
+	return &CertificationNotificationRepository{DB: db}
+}
+
+// Create menyimpan notifikasi dan mengembalikan false jika notifikasi yang
+// sama (prestasi, penerima, jenis) sudah pernah dibuat.
+func (r *CertificationNotificationRepository) Create(n *models.CertificationNotification) (bool, error) {
+	err := r.DB.QueryRow(`
+		INSERT INTO certification_notifications
+		(mongo_achievement_id, student_id, recipient_role, recipient_id, kind, valid_until, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (mongo_achievement_id, recipient_role, recipient_id, kind) DO NOTHING
+		RETURNING id, created_at
+	`,
+		n.MongoAchievementID,
+		n.StudentID,
+		n.RecipientRole,
+		n.RecipientID,
+		n.Kind,
+		n.ValidUntil,
+	).Scan(&n.ID, &n.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetByRecipient mengembalikan notifikasi penerima, terbaru lebih dulu.
+func (r *CertificationNotificationRepository) GetByRecipient(role, recipientID string) ([]*models.CertificationNotification, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, mongo_achievement_id, student_id, recipient_role, recipient_id,
+		       kind, valid_until, created_at
+		FROM certification_notifications
+		WHERE recipient_role=$1
+		  AND recipient_id::text=$2
+		ORDER BY created_at DESC
+	`, role, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*models.CertificationNotification
+	for rows.Next() {
+		n := &models.CertificationNotification{}
+		if err := rows.Scan(
+			&n.ID,
+			&n.MongoAchievementID,
+			&n.StudentID,
+			&n.RecipientRole,
+			&n.RecipientID,
+			&n.Kind,
+			&n.ValidUntil,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IAchievementMongoReportRepository interface {
 	SumPointsByIDs(ctx context.Context, ids []string) (int, error)
 	CountByType(ctx context.Context, ids []string) (map[string]int, error)
 	FindByIDs(ctx context.Context, ids []string) ([]bson.M, error)
+	FilterIDsByTag(ctx context.Context, ids []string, tags []string) ([]string, error)
 }
 
 type achievementMongoReportRepository struct {
@@ -19,13 +22,24 @@ type achievementMongoReportRepository struct {
 
 func NewAchievementMongoReportRepository(db *mongo.Database) IAchievementMongoReportRepository {
 	return &achievementMongoReportRepository{
-		collection: db.Collection("achievement_collections"),
+		collection: db.Collection("achievements"),
 	}
 }
 
+// objectIDs mengubah id heks menjadi ObjectID; id yang tidak valid dilewati.
+func objectIDs(ids []string) []primitive.ObjectID {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	return oids
+}
+
 func (r *achievementMongoReportRepository) SumPointsByIDs(ctx context.Context, ids []string) (int, error) {
 	pipeline := []bson.M{
-		{"$match": bson.M{"_id": bson.M{"$in": ids}}},
+		{"$match": bson.M{"_id": bson.M{"$in": objectIDs(ids)}}},
 		{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": "$points"}}},
 	}
 
@@ -35,7 +49,7 @@ func (r *achievementMongoReportRepository) SumPointsByIDs(ctx context.Context, i
 	}
 	defer cursor.Close(ctx)
 
-	var result []struct {
+	var result struct {
 		Total int `bson:"total"`
 	}
 
@@ -43,15 +57,15 @@ func (r *achievementMongoReportRepository) SumPointsByIDs(ctx context.Context, i
 		if err := cursor.Decode(&result); err != nil {
 			return 0, err
 		}
-		return result[0].Total, nil
+		return result.Total, nil
 	}
 
-	return 0, nil
+	return 0, cursor.Err()
 }
 
 func (r *achievementMongoReportRepository) CountByType(ctx context.Context, ids []string) (map[string]int, error) {
 	pipeline := []bson.M{
-		{"$match": bson.M{"_id": bson.M{"$in": ids}}},
+		{"$match": bson.M{"_id": bson.M{"$in": objectIDs(ids)}}},
 		{"$group": bson.M{"_id": "$achievementType", "count": bson.M{"$sum": 1}}},
 	}
 
@@ -77,7 +91,7 @@ func (r *achievementMongoReportRepository) CountByType(ctx context.Context, ids
 }
 
 func (r *achievementMongoReportRepository) FindByIDs(ctx context.Context, ids []string) ([]bson.M, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs(ids)}})
 	if err != nil {
 		return nil, err
 	}
@@ -90,3 +104,31 @@ func (r *achievementMongoReportRepository) FindByIDs(ctx context.Context, ids []
 
 	return results, nil
 }
+
+// FilterIDsByTag mengembalikan ids yang dokumennya memiliki salah satu
+// tags, tanpa membedakan huruf besar kecil.
+func (r *achievementMongoReportRepository) FilterIDsByTag(ctx context.Context, ids []string, tags []string) ([]string, error) {
+	filter := bson.M{
+		"_id":  bson.M{"$in": objectIDs(ids)},
+		"tags": tagsFilter(tags),
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var filtered []string
+	for cursor.Next(ctx) {
+		var row struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		filtered = append(filtered, row.ID.Hex())
+	}
+
+	return filtered, cursor.Err()
+}
@@ -0,0 +1,97 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"uas/app/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// matchIDs mengambil daftar _id pada filter {"_id": {"$in": [...]}}
+// dari perintah yang benar-benar dikirim driver.
+func matchIDs(t *testing.T, filter bson.Raw) []primitive.ObjectID {
+	in := filter.Lookup("_id", "$in").Array()
+	values, err := in.Values()
+	assert.NoError(t, err)
+
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		oid, ok := v.ObjectIDOK()
+		assert.True(t, ok, "_id must be sent as ObjectID, got %s", v.Type)
+		ids = append(ids, oid)
+	}
+	return ids
+}
+
+func TestAchievementMongoReportRepository(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	oid := primitive.NewObjectID()
+	ns := "uas.achievements"
+
+	mt.Run("FindByIDs queries achievements by ObjectID", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: oid}, {Key: "achievementType", Value: "competition"}},
+		))
+
+		repo := repositories.NewAchievementMongoReportRepository(mt.DB)
+		docs, err := repo.FindByIDs(context.Background(), []string{oid.Hex(), "not-an-id"})
+
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, "achievements", cmd.Lookup("find").StringValue())
+		assert.Equal(t, []primitive.ObjectID{oid}, matchIDs(t, cmd.Lookup("filter").Document()))
+	})
+
+	mt.Run("SumPointsByIDs matches ObjectIDs and decodes the total", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: nil}, {Key: "total", Value: 42}},
+		))
+
+		repo := repositories.NewAchievementMongoReportRepository(mt.DB)
+		total, err := repo.SumPointsByIDs(context.Background(), []string{oid.Hex()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, total)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, "achievements", cmd.Lookup("aggregate").StringValue())
+		match := cmd.Lookup("pipeline", "0", "$match").Document()
+		assert.Equal(t, []primitive.ObjectID{oid}, matchIDs(t, match))
+	})
+
+	mt.Run("FilterIDsByTag matches ObjectIDs and case-insensitive tags", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: oid}},
+		))
+
+		repo := repositories.NewAchievementMongoReportRepository(mt.DB)
+		ids, err := repo.FilterIDsByTag(context.Background(), []string{oid.Hex()}, []string{"AI", "machine.learning"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{oid.Hex()}, ids)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, "achievements", cmd.Lookup("find").StringValue())
+
+		filter := cmd.Lookup("filter").Document()
+		assert.Equal(t, []primitive.ObjectID{oid}, matchIDs(t, filter))
+
+		values, err := filter.Lookup("tags", "$in").Array().Values()
+		assert.NoError(t, err)
+		if assert.Len(t, values, 2) {
+			pattern, options := values[0].Regex()
+			assert.Equal(t, "^AI$", pattern)
+			assert.Equal(t, "i", options)
+			pattern, _ = values[1].Regex()
+			assert.Equal(t, `^machine\.learning$`, pattern)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 type IReportRepository interface {
 	GetVerifiedAchievementMongoIDs() ([]string, error)
 	GetVerifiedAchievementMongoIDsByStudent(studentID string) ([]string, error)
+	GetAwardedPointsByStudent(studentID string) (map[string]int, error)
 }
 
 type reportRepository struct {
@@ -17,8 +18,11 @@ func NewReportRepository(db *sql.DB) IReportRepository {
 	return &reportRepository{db: db}
 }
 
+// GetVerifiedAchievementMongoIDs mengembalikan setiap prestasi yang punya
+// referensi terverifikasi satu kali. Prestasi tim dengan beberapa anggota
+// terverifikasi tetap dihitung sebagai satu prestasi.
 func (r *reportRepository) GetVerifiedAchievementMongoIDs() ([]string, error) {
-	rows, err := r.db.Query("SELECT mongo_achievement_id FROM achievement_references WHERE status = 'verified'")
+	rows, err := r.db.Query("SELECT DISTINCT mongo_achievement_id FROM achievement_references WHERE status = 'verified'")
 	if err != nil {
 		return nil, err
 	}
@@ -52,3 +56,25 @@ func (r *reportRepository) GetVerifiedAchievementMongoIDsByStudent(studentID str
 	}
 	return ids, nil
 }
+
+// GetAwardedPointsByStudent mengembalikan poin yang diterima mahasiswa per
+// prestasi terverifikasi. Poin anggota prestasi tim bisa berbeda dari poin
+// dokumen jika kebijakan tipenya split.
+func (r *reportRepository) GetAwardedPointsByStudent(studentID string) (map[string]int, error) {
+	rows, err := r.db.Query("SELECT mongo_achievement_id, points FROM achievement_references WHERE student_id = $1 AND status = 'verified' AND points IS NOT NULL", studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := map[string]int{}
+	for rows.Next() {
+		var id string
+		var p int
+		if err := rows.Scan(&id, &p); err != nil {
+			return nil, err
+		}
+		points[id] = p
+	}
+	return points, nil
+}
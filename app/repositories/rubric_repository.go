@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type IRubricRepository interface {
+	FindAll() ([]*models.RubricRule, error)
+	FindByType(achievementType string) ([]*models.RubricRule, error)
+	FindByID(id string) (*models.RubricRule, error)
+	Create(rule *models.RubricRule) error
+	Update(rule *models.RubricRule) error
+	Delete(id string) error
+}
+
+type RubricRepository struct {
+	DB *sql.DB
+}
+
+func NewRubricRepository(db *sql.DB) IRubricRepository {
+	return &RubricRepository{DB: db}
+}
+
+const rubricColumns = `
+	id, achievement_type, field, value, points, description, created_at, updated_at
+`
+
+func scanRubricRules(rows *sql.Rows) ([]*models.RubricRule, error) {
+	defer rows.Close()
+
+	var rules []*models.RubricRule
+	for rows.Next() {
+		rule := &models.RubricRule{}
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.AchievementType,
+			&rule.Field,
+			&rule.Value,
+			&rule.Points,
+			&rule.Description,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *RubricRepository) FindAll() ([]*models.RubricRule, error) {
+	rows, err := r.DB.Query(`
+		SELECT` + rubricColumns + `
+		FROM point_rubric_rules
+		ORDER BY achievement_type, field, value
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return scanRubricRules(rows)
+}
+
+func (r *RubricRepository) FindByType(achievementType string) ([]*models.RubricRule, error) {
+	rows, err := r.DB.Query(`
+		SELECT`+rubricColumns+`
+		FROM point_rubric_rules
+		WHERE LOWER(achievement_type) = LOWER($1)
+		ORDER BY field, value
+	`, achievementType)
+	if err != nil {
+		return nil, err
+	}
+	return scanRubricRules(rows)
+}
+
+func (r *RubricRepository) FindByID(id string) (*models.RubricRule, error) {
+	rule := &models.RubricRule{}
+	err := r.DB.QueryRow(`
+		SELECT`+rubricColumns+`
+		FROM point_rubric_rules
+		WHERE id = $1
+	`, id).Scan(
+		&rule.ID,
+		&rule.AchievementType,
+		&rule.Field,
+		&rule.Value,
+		&rule.Points,
+		&rule.Description,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *RubricRepository) Create(rule *models.RubricRule) error {
+	return r.DB.QueryRow(`
+		INSERT INTO point_rubric_rules
+		(achievement_type, field, value, points, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`,
+		rule.AchievementType,
+		rule.Field,
+		rule.Value,
+		rule.Points,
+		rule.Description,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *RubricRepository) Update(rule *models.RubricRule) error {
+	res, err := r.DB.Exec(`
+		UPDATE point_rubric_rules
+		SET achievement_type=$2, field=$3, value=$4, points=$5, description=$6, updated_at=NOW()
+		WHERE id=$1
+	`,
+		rule.ID,
+		rule.AchievementType,
+		rule.Field,
+		rule.Value,
+		rule.Points,
+		rule.Description,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *RubricRepository) Delete(id string) error {
+	res, err := r.DB.Exec(`DELETE FROM point_rubric_rules WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
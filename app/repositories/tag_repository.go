@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"database/sql"
+	"strings"
+	"uas/app/models"
+
+	"github.com/lib/pq"
+)
+
+type ITagRepository interface {
+	FindAll() ([]*models.Tag, error)
+	FindByID(id string) (*models.Tag, error)
+	Search(query string, limit int) ([]*models.Tag, error)
+	Create(tag *models.Tag) error
+	Update(tag *models.Tag) error
+	Delete(id string) error
+}
+
+type TagRepository struct {
+	DB *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) ITagRepository {
+	return &TagRepository{DB: db}
+}
+
+func (r *TagRepository) FindAll() ([]*models.Tag, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, name, aliases, created_at, updated_at
+		FROM tags
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+func (r *TagRepository) FindByID(id string) (*models.Tag, error) {
+	return scanTag(r.DB.QueryRow(`
+		SELECT id, name, aliases, created_at, updated_at
+		FROM tags
+		WHERE id=$1
+	`, id))
+}
+
+// Search mencari tag yang nama atau salah satu aliasnya diawali query.
+// Kecocokan pada nama diurutkan lebih dulu.
+func (r *TagRepository) Search(query string, limit int) ([]*models.Tag, error) {
+	prefix := likeEscaper.Replace(query) + "%"
+
+	rows, err := r.DB.Query(`
+		SELECT id, name, aliases, created_at, updated_at
+		FROM tags
+		WHERE name ILIKE $1
+		   OR EXISTS (SELECT 1 FROM unnest(aliases) a WHERE a ILIKE $1)
+		ORDER BY (name ILIKE $1) DESC, name
+		LIMIT $2
+	`, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+func (r *TagRepository) Create(tag *models.Tag) error {
+	return r.DB.QueryRow(`
+		INSERT INTO tags (name, aliases, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, tag.Name, pq.Array(tag.Aliases)).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+}
+
+func (r *TagRepository) Update(tag *models.Tag) error {
+	err := r.DB.QueryRow(`
+		UPDATE tags
+		SET name=$2, aliases=$3, updated_at=NOW()
+		WHERE id=$1
+		RETURNING created_at, updated_at
+	`, tag.ID, tag.Name, pq.Array(tag.Aliases)).Scan(&tag.CreatedAt, &tag.UpdatedAt)
+	return err
+}
+
+func (r *TagRepository) Delete(id string) error {
+	res, err := r.DB.Exec(`DELETE FROM tags WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func scanTags(rows *sql.Rows) ([]*models.Tag, error) {
+	var tags []*models.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func scanTag(row rowScanner) (*models.Tag, error) {
+	tag := &models.Tag{}
+	err := row.Scan(&tag.ID, &tag.Name, pq.Array(&tag.Aliases), &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
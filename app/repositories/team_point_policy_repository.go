@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type ITeamPointPolicyRepository interface {
+	FindAll() ([]*models.TeamPointPolicy, error)
+	FindByType(achievementType string) (*models.TeamPointPolicy, error)
+	Upsert(policy *models.TeamPointPolicy) error
+}
+
+type TeamPointPolicyRepository struct {
+	DB *sql.DB
+}
+
+func NewTeamPointPolicyRepository(db *sql.DB) ITeamPointPolicyRepository {
+	return &TeamPointPolicyRepository{DB: db}
+}
+
+func (r *TeamPointPolicyRepository) FindAll() ([]*models.TeamPointPolicy, error) {
+	rows, err := r.DB.Query(`
+		SELECT achievement_type, policy, updated_at
+		FROM team_point_policies
+		ORDER BY achievement_type
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*models.TeamPointPolicy
+	for rows.Next() {
+		p := &models.TeamPointPolicy{}
+		if err := rows.Scan(&p.AchievementType, &p.Policy, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (r *TeamPointPolicyRepository) FindByType(achievementType string) (*models.TeamPointPolicy, error) {
+	p := &models.TeamPointPolicy{}
+	err := r.DB.QueryRow(`
+		SELECT achievement_type, policy, updated_at
+		FROM team_point_policies
+		WHERE achievement_type=$1
+	`, achievementType).Scan(&p.AchievementType, &p.Policy, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *TeamPointPolicyRepository) Upsert(policy *models.TeamPointPolicy) error {
+	return r.DB.QueryRow(`
+		INSERT INTO team_point_policies (achievement_type, policy, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (achievement_type)
+		DO UPDATE SET policy = EXCLUDED.policy, updated_at = NOW()
+		RETURNING updated_at
+	`, policy.AchievementType, policy.Policy).Scan(&policy.UpdatedAt)
+}
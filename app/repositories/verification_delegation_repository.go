@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+	"uas/app/models"
+)
+
+type IVerificationDelegationRepository interface {
+	Create(d *models.VerificationDelegation) error
+	FindByID(id string) (*models.VerificationDelegation, error)
+	FindByLecturer(lecturerID string) ([]*models.VerificationDelegation, error)
+	FindActive(advisorID, delegateID string, at time.Time) (*models.VerificationDelegation, error)
+	FindActiveByDelegate(delegateID string, at time.Time) ([]*models.VerificationDelegation, error)
+	HasOverlap(advisorID string, startsAt, endsAt time.Time) (bool, error)
+	Revoke(id string, at time.Time) error
+}
+
+type VerificationDelegationRepository struct {
+	DB *sql.DB
+}
+
+func NewVerificationDelegationRepository(db *sql.DB) IVerificationDelegationRepository {
+	return &VerificationDelegationRepository{DB: db}
+}
+
+const delegationColumns = `
+	id, advisor_id, delegate_id, starts_at, ends_at, reason, created_by, created_at, revoked_at
+`
+
+func scanDelegation(row rowScanner) (*models.VerificationDelegation, error) {
+	d := &models.VerificationDelegation{}
+	if err := row.Scan(
+		&d.ID,
+		&d.AdvisorID,
+		&d.DelegateID,
+		&d.StartsAt,
+		&d.EndsAt,
+		&d.Reason,
+		&d.CreatedBy,
+		&d.CreatedAt,
+		&d.RevokedAt,
+	); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func scanDelegations(rows *sql.Rows) ([]*models.VerificationDelegation, error) {
+	defer rows.Close()
+
+	var delegations []*models.VerificationDelegation
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, d)
+	}
+	return delegations, nil
+}
+
+func (r *VerificationDelegationRepository) Create(d *models.VerificationDelegation) error {
+	return r.DB.QueryRow(`
+		INSERT INTO verification_delegations
+		(advisor_id, delegate_id, starts_at, ends_at, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, created_at
+	`,
+		d.AdvisorID,
+		d.DelegateID,
+		d.StartsAt,
+		d.EndsAt,
+		d.Reason,
+		d.CreatedBy,
+	).Scan(&d.ID, &d.CreatedAt)
+}
+
+func (r *VerificationDelegationRepository) FindByID(id string) (*models.VerificationDelegation, error) {
+	return scanDelegation(r.DB.QueryRow(`
+		SELECT`+delegationColumns+`
+		FROM verification_delegations
+		WHERE id = $1
+	`, id))
+}
+
+// FindByLecturer mengembalikan delegasi di mana dosen menjadi pemberi atau
+// penerima. lecturerID kosong mengembalikan semua delegasi.
+func (r *VerificationDelegationRepository) FindByLecturer(lecturerID string) ([]*models.VerificationDelegation, error) {
+	rows, err := r.DB.Query(`
+		SELECT`+delegationColumns+`
+		FROM verification_delegations
+		WHERE $1 = '' OR advisor_id::text = $1 OR delegate_id::text = $1
+		ORDER BY starts_at DESC
+	`, lecturerID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDelegations(rows)
+}
+
+func (r *VerificationDelegationRepository) FindActive(
+	advisorID, delegateID string,
+	at time.Time,
+) (*models.VerificationDelegation, error) {
+	return scanDelegation(r.DB.QueryRow(`
+		SELECT`+delegationColumns+`
+		FROM verification_delegations
+		WHERE advisor_id = $1
+		  AND delegate_id = $2
+		  AND revoked_at IS NULL
+		  AND starts_at <= $3 AND ends_at > $3
+		ORDER BY starts_at DESC
+		LIMIT 1
+	`, advisorID, delegateID, at))
+}
+
+func (r *VerificationDelegationRepository) FindActiveByDelegate(
+	delegateID string,
+	at time.Time,
+) ([]*models.VerificationDelegation, error) {
+	rows, err := r.DB.Query(`
+		SELECT`+delegationColumns+`
+		FROM verification_delegations
+		WHERE delegate_id = $1
+		  AND revoked_at IS NULL
+		  AND starts_at <= $2 AND ends_at > $2
+	`, delegateID, at)
+	if err != nil {
+		return nil, err
+	}
+	return scanDelegations(rows)
+}
+
+func (r *VerificationDelegationRepository) HasOverlap(
+	advisorID string,
+	startsAt, endsAt time.Time,
+) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM verification_delegations
+			WHERE advisor_id = $1
+			  AND revoked_at IS NULL
+			  AND starts_at < $3 AND ends_at > $2
+		)
+	`, advisorID, startsAt, endsAt).Scan(&exists)
+	return exists, err
+}
+
+func (r *VerificationDelegationRepository) Revoke(id string, at time.Time) error {
+	res, err := r.DB.Exec(`
+		UPDATE verification_delegations
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id, at)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"database/sql"
+	"uas/app/models"
+)
+
+type IVerificationSLARepository interface {
+	FindAll() ([]*models.VerificationSLA, error)
+	Upsert(sla *models.VerificationSLA) error
+	Delete(programStudy string) error
+}
+
+type VerificationSLARepository struct {
+	DB *sql.DB
+}
+
+func NewVerificationSLARepository(db *sql.DB) IVerificationSLARepository {
+	return &VerificationSLARepository{DB: db}
+}
+
+func (r *VerificationSLARepository) FindAll() ([]*models.VerificationSLA, error) {
+	rows, err := r.DB.Query(`
+		SELECT program_study, due_days, escalate_days, updated_at
+		FROM verification_sla
+		ORDER BY program_study
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slas []*models.VerificationSLA
+	for rows.Next() {
+		sla := &models.VerificationSLA{}
+		if err := rows.Scan(&sla.ProgramStudy, &sla.DueDays, &sla.EscalateDays, &sla.UpdatedAt); err != nil {
+			return nil, err
+		}
+		slas = append(slas, sla)
+	}
+	return slas, nil
+}
+
+func (r *VerificationSLARepository) Upsert(sla *models.VerificationSLA) error {
+	return r.DB.QueryRow(`
+		INSERT INTO verification_sla (program_study, due_days, escalate_days, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (program_study)
+		DO UPDATE SET due_days = EXCLUDED.due_days,
+		              escalate_days = EXCLUDED.escalate_days,
+		              updated_at = NOW()
+		RETURNING updated_at
+	`, sla.ProgramStudy, sla.DueDays, sla.EscalateDays).Scan(&sla.UpdatedAt)
+}
+
+func (r *VerificationSLARepository) Delete(programStudy string) error {
+	res, err := r.DB.Exec(`DELETE FROM verification_sla WHERE program_study=$1`, programStudy)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+var (
+	ErrAchievementForbidden = errors.New("forbidden")
+	ErrStudentNotFound      = errors.New("student not found")
+
+	errAchievementNotFound = errors.New("achievement not found")
+)
+
+func isAdmin(user *models.JWTClaims) bool {
+	return strings.EqualFold(user.Role, "admin")
+}
+
+func isStudent(user *models.JWTClaims) bool {
+	return strings.EqualFold(user.Role, "mahasiswa")
+}
+
+// AchievementAccess adalah lapisan otorisasi tingkat resource untuk
+// prestasi: mahasiswa hanya miliknya sendiri, dosen wali hanya milik
+// mahasiswa bimbingannya (atau yang didelegasikan kepadanya), dan admin
+// semuanya. DelegationRepo boleh nil jika delegasi tidak dipakai.
+type AchievementAccess struct {
+	StudentRepo    repositories.IStudentRepository
+	LecturerRepo   repositories.ILecturerRepository
+	DelegationRepo repositories.IVerificationDelegationRepository
+}
+
+func NewAchievementAccess(
+	student repositories.IStudentRepository,
+	lecturer repositories.ILecturerRepository,
+	delegation repositories.IVerificationDelegationRepository,
+) *AchievementAccess {
+	return &AchievementAccess{
+		StudentRepo:    student,
+		LecturerRepo:   lecturer,
+		DelegationRepo: delegation,
+	}
+}
+
+// CanView mengizinkan pemilik, dosen wali, dan admin.
+func (a *AchievementAccess) CanView(user *models.JWTClaims, studentID string) error {
+	if isAdmin(user) {
+		return nil
+	}
+	if isStudent(user) {
+		return a.requireOwner(user, studentID)
+	}
+	return a.requireAdvisor(user, studentID)
+}
+
+// CanModify mengizinkan pemilik dan admin.
+func (a *AchievementAccess) CanModify(user *models.JWTClaims, studentID string) error {
+	if isAdmin(user) {
+		return nil
+	}
+	return a.requireOwner(user, studentID)
+}
+
+// CanReview mengizinkan dosen wali, penerima delegasinya, dan admin.
+func (a *AchievementAccess) CanReview(user *models.JWTClaims, studentID string) error {
+	_, err := a.Reviewer(user, studentID)
+	return err
+}
+
+// Reviewer memeriksa hak review seperti CanReview dan mengembalikan delegasi
+// yang dipakai jika user bertindak menggantikan dosen wali mahasiswa.
+func (a *AchievementAccess) Reviewer(user *models.JWTClaims, studentID string) (*models.VerificationDelegation, error) {
+	if isAdmin(user) {
+		return nil, nil
+	}
+	return a.advisorOrDelegate(user, studentID)
+}
+
+func (a *AchievementAccess) requireOwner(user *models.JWTClaims, studentID string) error {
+	student, err := a.StudentRepo.FindByUserID(user.UserID)
+	if err == sql.ErrNoRows {
+		return ErrAchievementForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if student.ID != studentID {
+		return ErrAchievementForbidden
+	}
+	return nil
+}
+
+func (a *AchievementAccess) requireAdvisor(user *models.JWTClaims, studentID string) error {
+	_, err := a.advisorOrDelegate(user, studentID)
+	return err
+}
+
+func (a *AchievementAccess) advisorOrDelegate(user *models.JWTClaims, studentID string) (*models.VerificationDelegation, error) {
+	lecturer, err := a.LecturerRepo.FindByUserID(user.UserID)
+	if err == sql.ErrNoRows {
+		return nil, ErrAchievementForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	student, err := a.StudentRepo.FindByID(studentID)
+	if err == sql.ErrNoRows {
+		return nil, ErrStudentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if student.AdvisorID == nil {
+		return nil, ErrAchievementForbidden
+	}
+	if *student.AdvisorID == lecturer.ID {
+		return nil, nil
+	}
+
+	if a.DelegationRepo != nil {
+		d, err := a.DelegationRepo.FindActive(*student.AdvisorID, lecturer.ID, time.Now())
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if d != nil {
+			return d, nil
+		}
+	}
+	return nil, ErrAchievementForbidden
+}
+
+func (s *AchievementService) access() *AchievementAccess {
+	return NewAchievementAccess(s.StudentRepo, s.LecturerRepo, s.DelegationRepo)
+}
+
+// delegatedAdvisors mengembalikan ID dosen wali yang saat ini
+// mendelegasikan verifikasi ke lecturerID.
+func (s *AchievementService) delegatedAdvisors(lecturerID string) ([]string, error) {
+	if s.DelegationRepo == nil {
+		return nil, nil
+	}
+
+	delegations, err := s.DelegationRepo.FindActiveByDelegate(lecturerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var advisors []string
+	for _, d := range delegations {
+		advisors = append(advisors, d.AdvisorID)
+	}
+	return advisors, nil
+}
+
+func accessDenied(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrAchievementForbidden):
+		return c.Status(403).JSON(fiber.Map{"error": "forbidden"})
+	case errors.Is(err, ErrStudentNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "student profile not found"})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+}
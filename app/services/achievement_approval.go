@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+var ErrAlreadyApproved = errors.New("you already signed off an earlier stage of this achievement")
+
+// ResolveApprovalChain memilih tahap persetujuan untuk prestasi: rantai
+// dengan tipe dan tingkat lomba yang sama, lalu rantai tipe tanpa tingkat,
+// lalu DefaultApprovalStages.
+func ResolveApprovalChain(chains []*models.ApprovalChain, doc *models.MongoAchievement) models.ApprovalStages {
+	level := approvalLevel(doc)
+
+	var fallback models.ApprovalStages
+	for _, chain := range chains {
+		if len(chain.Stages) == 0 || !strings.EqualFold(chain.AchievementType, doc.AchievementType) {
+			continue
+		}
+		if chain.CompetitionLevel == "" {
+			fallback = chain.Stages
+			continue
+		}
+		if level != "" && strings.EqualFold(chain.CompetitionLevel, level) {
+			return chain.Stages
+		}
+	}
+
+	if fallback != nil {
+		return fallback
+	}
+	return models.DefaultApprovalStages
+}
+
+// approvalLevel mengembalikan tingkat lomba yang dipakai memilih rantai
+// persetujuan dokumen.
+func approvalLevel(doc *models.MongoAchievement) string {
+	if doc.Details.CompetitionLevel == nil {
+		return ""
+	}
+	return strings.TrimSpace(*doc.Details.CompetitionLevel)
+}
+
+// currentStage mengembalikan tahap yang sedang menunggu sign-off. Indeks
+// dibatasi ke tahap terakhir jika rantai diubah saat prestasi diproses.
+func currentStage(stages models.ApprovalStages, ref *models.AchievementReference) (int, models.ApprovalStage) {
+	idx := ref.ApprovalStage
+	if idx >= len(stages) {
+		idx = len(stages) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	return idx, stages[idx]
+}
+
+func isAdvisorStage(stage models.ApprovalStage) bool {
+	return strings.EqualFold(stage.Role, models.ApprovalRoleAdvisor)
+}
+
+// canActOnStage menandakan role user menangani tahap non-dosen-wali.
+// Admin boleh menangani semua tahap.
+func canActOnStage(user *models.JWTClaims, stage models.ApprovalStage) bool {
+	return isAdmin(user) || strings.EqualFold(user.Role, stage.Role)
+}
+
+func (s *AchievementService) approvalChains() ([]*models.ApprovalChain, error) {
+	if s.ChainRepo == nil {
+		return nil, nil
+	}
+	return s.ChainRepo.FindAll()
+}
+
+// approvalStages menentukan rantai persetujuan prestasi. Dokumen Mongo
+// hanya dibaca jika ada rantai yang dikonfigurasi.
+func (s *AchievementService) approvalStages(ctx context.Context, ref *models.AchievementReference) (models.ApprovalStages, error) {
+	if s.ChainRepo == nil {
+		return models.DefaultApprovalStages, nil
+	}
+
+	chains, err := s.ChainRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(chains) == 0 {
+		return models.DefaultApprovalStages, nil
+	}
+
+	doc, err := s.MongoRepo.FindByID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, errAchievementNotFound
+	}
+	return ResolveApprovalChain(chains, doc), nil
+}
+
+// authorizeStage memeriksa apakah user boleh menangani tahap saat ini.
+// Tahap dosen wali memakai aturan CanReview (termasuk delegasi).
+func (s *AchievementService) authorizeStage(
+	user *models.JWTClaims,
+	ref *models.AchievementReference,
+	stage models.ApprovalStage,
+) (*models.VerificationDelegation, error) {
+	if isAdvisorStage(stage) {
+		return s.access().Reviewer(user, ref.StudentID)
+	}
+	if !canActOnStage(user, stage) {
+		return nil, ErrAchievementForbidden
+	}
+	return nil, nil
+}
+
+// checkNotYetApproved mencegah satu orang menandatangani dua tahap pada
+// pengajuan yang sama dari anggota yang sama.
+func (s *AchievementService) checkNotYetApproved(user *models.JWTClaims, ref *models.AchievementReference) error {
+	if s.ApprovalRepo == nil {
+		return nil
+	}
+
+	approvals, err := s.ApprovalRepo.GetByMongoID(ref.MongoAchievementID, ref.SubmittedAt)
+	if err != nil {
+		return err
+	}
+	for _, a := range approvals {
+		// Anggota tim lain punya tanda tangan sendiri.
+		if a.AchievementRefID != "" && a.AchievementRefID != ref.ID {
+			continue
+		}
+		if a.ApproverID == user.UserID {
+			return ErrAlreadyApproved
+		}
+	}
+	return nil
+}
+
+func (s *AchievementService) recordApproval(
+	user *models.JWTClaims,
+	delegation *models.VerificationDelegation,
+	ref *models.AchievementReference,
+	idx int,
+	stage models.ApprovalStage,
+) error {
+	if s.ApprovalRepo == nil {
+		return nil
+	}
+
+	approval := &models.AchievementApproval{
+		AchievementRefID:   ref.ID,
+		MongoAchievementID: ref.MongoAchievementID,
+		StageIndex:         idx,
+		StageName:          stage.Name,
+		ApproverID:         user.UserID,
+		ApproverRole:       user.Role,
+	}
+	if delegation != nil {
+		approval.OnBehalfOf = &delegation.AdvisorID
+	}
+	return s.ApprovalRepo.Create(approval)
+}
+
+// approvalKeyBackfillBatch membatasi jumlah dokumen yang dibaca per query
+// saat mengisi tipe dan tingkat referensi lama.
+const approvalKeyBackfillBatch = 500
+
+// BackfillApprovalKeys menyalin tipe dan tingkat dokumen ke referensi
+// submitted yang dikirim sebelum keduanya disimpan di Postgres, agar tahap
+// persetujuannya bisa dihitung di query daftar. Dokumen yang hilang
+// dilewati; rekonsiliasi yang menanganinya.
+func BackfillApprovalKeys(
+	ctx context.Context,
+	mongoRepo repositories.IAchievementMongoRepository,
+	refRepo repositories.IAchievementReferenceRepo,
+) (int, error) {
+
+	ids, err := refRepo.GetSubmittedWithoutApprovalKey()
+	if err != nil {
+		return 0, err
+	}
+
+	filled := 0
+	for start := 0; start < len(ids); start += approvalKeyBackfillBatch {
+		end := start + approvalKeyBackfillBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		docs, err := mongoRepo.FindByIDs(ctx, ids[start:end])
+		if err != nil {
+			return filled, err
+		}
+		for _, doc := range docs {
+			if err := refRepo.SetApprovalKeyByMongoID(doc.ID.Hex(), doc.AchievementType, approvalLevel(doc)); err != nil {
+				return filled, err
+			}
+			filled++
+		}
+	}
+	return filled, nil
+}
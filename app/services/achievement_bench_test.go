@@ -0,0 +1,257 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+	"uas/app/models"
+	"uas/app/repositories"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore adalah backend in-memory untuk benchmark listing. Setiap
+// pemanggilan repository dihitung sebagai satu round trip ke database.
+type memoryStore struct {
+	refs       []*models.AchievementReference
+	docs       map[string]*models.MongoAchievement
+	advisorOf  map[string]string
+	roundTrips int
+}
+
+// newMemoryStore membuat prestasi untuk advisees mahasiswa per dosen wali;
+// setengahnya submitted.
+func newMemoryStore(advisors, advisees, perStudent int) *memoryStore {
+	store := &memoryStore{
+		docs:      map[string]*models.MongoAchievement{},
+		advisorOf: map[string]string{},
+	}
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for a := 0; a < advisors; a++ {
+		for s := 0; s < advisees; s++ {
+			studentID := fmt.Sprintf("student-%d-%d", a, s)
+			store.advisorOf[studentID] = fmt.Sprintf("lecturer-%d", a)
+
+			for i := 0; i < perStudent; i++ {
+				oid := primitive.NewObjectID()
+				status := models.AchievementStatusDraft
+				if i%2 == 0 {
+					status = models.AchievementStatusSubmitted
+				}
+				created = created.Add(time.Minute)
+				store.refs = append(store.refs, &models.AchievementReference{
+					ID:                 oid.Hex(),
+					StudentID:          studentID,
+					MongoAchievementID: oid.Hex(),
+					Status:             status,
+					TeamRole:           models.TeamRoleOwner,
+					CreatedAt:          created,
+				})
+				store.docs[oid.Hex()] = &models.MongoAchievement{
+					ID:              oid,
+					StudentID:       studentID,
+					AchievementType: "competition",
+					Title:           fmt.Sprintf("Prestasi %s #%d", studentID, i),
+				}
+			}
+		}
+	}
+	return store
+}
+
+type memoryRefRepo struct {
+	repositories.IAchievementReferenceRepo
+	*memoryStore
+}
+
+func (r memoryRefRepo) List(filter models.AchievementListFilter) ([]*models.AchievementReference, int, error) {
+	r.roundTrips++
+
+	in := func(list []string, v string) bool {
+		if list == nil {
+			return true
+		}
+		for _, x := range list {
+			if x == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	var matched []*models.AchievementReference
+	for _, ref := range r.refs {
+		if in(filter.StudentIDs, ref.StudentID) &&
+			in(filter.AdvisorIDs, r.advisorOf[ref.StudentID]) &&
+			in(filter.MongoIDs, ref.MongoAchievementID) &&
+			in(filter.RefIDs, ref.ID) &&
+			in(filter.Statuses, ref.Status) {
+			matched = append(matched, ref)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if filter.Desc {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+	})
+
+	total := len(matched)
+	if filter.Limit > 0 {
+		if filter.Offset > len(matched) {
+			filter.Offset = len(matched)
+		}
+		end := filter.Offset + filter.Limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		matched = matched[filter.Offset:end]
+	}
+	return matched, total, nil
+}
+
+func (r memoryRefRepo) GetByStudentIDs(studentIDs []string) ([]*models.AchievementReference, error) {
+	r.roundTrips++
+
+	ids := map[string]bool{}
+	for _, id := range studentIDs {
+		ids[id] = true
+	}
+	var refs []*models.AchievementReference
+	for _, ref := range r.refs {
+		if ids[ref.StudentID] {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+type memoryMongoRepo struct {
+	repositories.IAchievementMongoRepository
+	*memoryStore
+}
+
+func (r memoryMongoRepo) FindByIDs(ctx context.Context, ids []string) ([]*models.MongoAchievement, error) {
+	r.roundTrips++
+
+	docs := make([]*models.MongoAchievement, 0, len(ids))
+	for _, id := range ids {
+		if doc, ok := r.docs[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+type memoryLecturerRepo struct {
+	repositories.ILecturerRepository
+	*memoryStore
+}
+
+func (r memoryLecturerRepo) FindByUserID(userID string) (*models.Lecturer, error) {
+	r.roundTrips++
+	return &models.Lecturer{ID: "lecturer-0", UserID: userID}, nil
+}
+
+func memoryListingApp(store *memoryStore, claims *models.JWTClaims) *fiber.App {
+	service := &services.AchievementService{
+		MongoRepo:    memoryMongoRepo{memoryStore: store},
+		RefRepo:      memoryRefRepo{memoryStore: store},
+		LecturerRepo: memoryLecturerRepo{memoryStore: store},
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", claims)
+		return c.Next()
+	})
+	app.Get("/achievements", service.ListAchievements())
+	return app
+}
+
+var (
+	benchAdmin   = &models.JWTClaims{UserID: "user-admin", Role: "Admin"}
+	benchAdvisor = &models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"}
+)
+
+func TestAchievementListingRoundTrips(t *testing.T) {
+	for _, claims := range []*models.JWTClaims{benchAdmin, benchAdvisor} {
+		var trips []int
+		for _, advisees := range []int{2, 200} {
+			store := newMemoryStore(3, advisees, 5)
+			app := memoryListingApp(store, claims)
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/achievements?limit=100", nil))
+			assert.Equal(t, 200, resp.StatusCode)
+			trips = append(trips, store.roundTrips)
+		}
+		assert.Equal(t, trips[0], trips[1], "%s listing round trips must not grow with data", claims.Role)
+	}
+
+	var trips []int
+	for _, perStudent := range []int{2, 500} {
+		store := newMemoryStore(1, 1, perStudent)
+		items, err := services.LoadStudentAchievements(
+			context.Background(),
+			memoryRefRepo{memoryStore: store},
+			memoryMongoRepo{memoryStore: store},
+			"student-0-0",
+		)
+		assert.NoError(t, err)
+		assert.Len(t, items, perStudent)
+		trips = append(trips, store.roundTrips)
+	}
+	assert.Equal(t, []int{2, 2}, trips)
+}
+
+func BenchmarkListAchievements(b *testing.B) {
+	for _, bench := range []struct {
+		name   string
+		claims *models.JWTClaims
+	}{
+		{"admin", benchAdmin},
+		{"advisor", benchAdvisor},
+	} {
+		for _, advisees := range []int{10, 100, 1000} {
+			b.Run(fmt.Sprintf("%s/advisees=%d", bench.name, advisees), func(b *testing.B) {
+				store := newMemoryStore(3, advisees, 5)
+				app := memoryListingApp(store, bench.claims)
+				store.roundTrips = 0
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					resp, err := app.Test(httptest.NewRequest("GET", "/achievements?limit=50", nil))
+					if err != nil || resp.StatusCode != 200 {
+						b.Fatalf("list failed: %v", err)
+					}
+				}
+				b.ReportMetric(float64(store.roundTrips)/float64(b.N), "roundtrips/op")
+			})
+		}
+	}
+}
+
+func BenchmarkLoadStudentAchievements(b *testing.B) {
+	for _, perStudent := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("achievements=%d", perStudent), func(b *testing.B) {
+			store := newMemoryStore(1, 1, perStudent)
+			refs := memoryRefRepo{memoryStore: store}
+			docs := memoryMongoRepo{memoryStore: store}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := services.LoadStudentAchievements(context.Background(), refs, docs, "student-0-0"); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(store.roundTrips)/float64(b.N), "roundtrips/op")
+		})
+	}
+}
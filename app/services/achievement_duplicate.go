@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"uas/app/models"
+)
+
+// Bobot kemiripan duplikat. Skor minimal DuplicateScoreThreshold dianggap
+// kemungkinan duplikat, mis. nama lomba dan tanggal sama (0.6) atau judul
+// identik dan tanggal sama (0.5).
+const (
+	duplicateWeightName      = 0.35
+	duplicateWeightEventDate = 0.25
+	duplicateWeightOrganizer = 0.15
+	duplicateWeightTitle     = 0.25
+
+	DuplicateScoreThreshold = 0.5
+	duplicateTitleMinSim    = 0.6
+	maxDuplicateMatches     = 5
+)
+
+// ScoreDuplicate menilai kemiripan dua prestasi dari nama kegiatan
+// (competitionName atau padanannya untuk tipe lain), tanggal kegiatan,
+// penyelenggara, dan kemiripan judul. Reasons berisi field yang cocok.
+func ScoreDuplicate(a, b *models.MongoAchievement) (float64, []string) {
+	score := 0.0
+	reasons := []string{}
+
+	if field, nameA := primaryName(a); nameA != "" {
+		if _, nameB := primaryName(b); nameA == nameB {
+			score += duplicateWeightName
+			reasons = append(reasons, "details."+field)
+		}
+	}
+
+	if a.Details.EventDate != nil && b.Details.EventDate != nil &&
+		sameDay(*a.Details.EventDate, *b.Details.EventDate) {
+		score += duplicateWeightEventDate
+		reasons = append(reasons, "details.eventDate")
+	}
+
+	if orgA := normalizeText(deref(a.Details.Organizer)); orgA != "" &&
+		orgA == normalizeText(deref(b.Details.Organizer)) {
+		score += duplicateWeightOrganizer
+		reasons = append(reasons, "details.organizer")
+	}
+
+	if sim := titleSimilarity(a.Title, b.Title); sim >= duplicateTitleMinSim {
+		score += duplicateWeightTitle * sim
+		reasons = append(reasons, "title")
+	}
+
+	return math.Round(score*100) / 100, reasons
+}
+
+// findDuplicates mencari prestasi bertipe sama milik mahasiswa yang sama,
+// atau milik mahasiswa lain dengan tanggal kegiatan berdekatan, lalu
+// mengembalikan yang skornya melewati ambang, urut dari yang paling mirip.
+func (s *AchievementService) findDuplicates(
+	ctx context.Context,
+	doc *models.MongoAchievement,
+	studentID, excludeID string,
+) ([]*models.DuplicateMatch, error) {
+
+	candidates, err := s.MongoRepo.FindDuplicateCandidates(ctx, doc.AchievementType, studentID, doc.Details.EventDate, excludeID)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		doc     *models.MongoAchievement
+		score   float64
+		reasons []string
+	}
+	var hits []scored
+	ids := []string{}
+	for _, cand := range candidates {
+		score, reasons := ScoreDuplicate(doc, cand)
+		if score < DuplicateScoreThreshold {
+			continue
+		}
+		hits = append(hits, scored{cand, score, reasons})
+		ids = append(ids, cand.ID.Hex())
+	}
+
+	matches := []*models.DuplicateMatch{}
+	if len(hits) == 0 {
+		return matches, nil
+	}
+
+	// Status dibaca sekali untuk semua kandidat. Prestasi tim punya satu
+	// referensi per anggota; yang dipakai adalah milik pemilik dokumen.
+	refs, _, err := s.RefRepo.List(models.AchievementListFilter{MongoIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	statusByDoc := map[[2]string]string{}
+	for _, ref := range refs {
+		statusByDoc[[2]string{ref.MongoAchievementID, ref.StudentID}] = ref.Status
+	}
+
+	for _, hit := range hits {
+		id := hit.doc.ID.Hex()
+		status, ok := statusByDoc[[2]string{id, hit.doc.StudentID}]
+		if !ok {
+			continue
+		}
+
+		matches = append(matches, &models.DuplicateMatch{
+			AchievementID: id,
+			StudentID:     hit.doc.StudentID,
+			Title:         hit.doc.Title,
+			Status:        status,
+			SameStudent:   hit.doc.StudentID == studentID,
+			Score:         hit.score,
+			Reasons:       hit.reasons,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > maxDuplicateMatches {
+		matches = matches[:maxDuplicateMatches]
+	}
+	return matches, nil
+}
+
+// duplicatesForUser menyembunyikan prestasi mahasiswa lain dari mahasiswa.
+// Mahasiswa hanya menerima kecocokan dengan prestasinya sendiri; kecocokan
+// lain (kemungkinan entri rekan satu tim) hanya dihitung. Reviewer menerima
+// semua kecocokan.
+func duplicatesForUser(user *models.JWTClaims, matches []*models.DuplicateMatch) ([]*models.DuplicateMatch, int) {
+	others := 0
+	visible := []*models.DuplicateMatch{}
+	for _, m := range matches {
+		if !m.SameStudent {
+			others++
+			if isStudent(user) {
+				continue
+			}
+		}
+		visible = append(visible, m)
+	}
+	return visible, others
+}
+
+// primaryName mengembalikan nama kegiatan utama sesuai tipe prestasi yang
+// sudah dinormalisasi.
+func primaryName(doc *models.MongoAchievement) (string, string) {
+	d := doc.Details
+	for _, f := range []struct {
+		field string
+		value *string
+	}{
+		{"competitionName", d.CompetitionName},
+		{"publicationTitle", d.PublicationTitle},
+		{"organizationName", d.OrganizationName},
+		{"certificationName", d.CertificationName},
+	} {
+		if v := normalizeText(deref(f.value)); v != "" {
+			return f.field, v
+		}
+	}
+	return "", ""
+}
+
+// normalizeText mengecilkan huruf, mengganti tanda baca dengan spasi, dan
+// merapatkan spasi.
+func normalizeText(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// titleSimilarity adalah koefisien Dice atas kumpulan kata judul.
+func titleSimilarity(a, b string) float64 {
+	ta, tb := wordSet(a), wordSet(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	common := 0
+	for w := range ta {
+		if tb[w] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(ta)+len(tb))
+}
+
+func wordSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(normalizeText(s)) {
+		set[w] = true
+	}
+	return set
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
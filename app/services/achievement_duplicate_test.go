@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestScoreDuplicate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	day := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+	otherDay := day.AddDate(0, 1, 0)
+
+	base := &models.MongoAchievement{
+		Title: "Juara 1 Gemastik 2025",
+		Details: models.AchievementDetails{
+			CompetitionName: str("GEMASTIK  2025"),
+			EventDate:       &day,
+			Organizer:       str("Kemendikbud"),
+		},
+	}
+
+	t.Run("Teammate Entry - Likely Duplicate", func(t *testing.T) {
+		other := &models.MongoAchievement{
+			Title: "Gemastik 2025 juara 1!",
+			Details: models.AchievementDetails{
+				CompetitionName: str("Gemastik-2025"),
+				EventDate:       &day,
+				Organizer:       str("kemendikbud"),
+			},
+		}
+
+		score, reasons := services.ScoreDuplicate(base, other)
+		assert.Equal(t, 1.0, score)
+		assert.Equal(t, []string{"details.competitionName", "details.eventDate", "details.organizer", "title"}, reasons)
+	})
+
+	t.Run("Same Competition Different Year - Not Duplicate", func(t *testing.T) {
+		other := &models.MongoAchievement{
+			Title: "Finalis Gemastik",
+			Details: models.AchievementDetails{
+				CompetitionName: str("Gemastik 2024"),
+				EventDate:       &otherDay,
+				Organizer:       str("Kemendikbud"),
+			},
+		}
+
+		score, _ := services.ScoreDuplicate(base, other)
+		assert.Less(t, score, services.DuplicateScoreThreshold)
+	})
+}
+
+func TestSubmitAchievementDuplicates(t *testing.T) {
+	str := func(s string) *string { return &s }
+	day := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+	entry := func(id primitive.ObjectID, studentID, title string) *models.MongoAchievement {
+		return &models.MongoAchievement{
+			ID:              id,
+			StudentID:       studentID,
+			AchievementType: "competition",
+			Title:           title,
+			Details: models.AchievementDetails{
+				CompetitionName: str("Gemastik 2025"),
+				EventDate:       &day,
+			},
+		}
+	}
+
+	submitted, ownCopy, teammate := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	submit := func(t *testing.T, claims *models.JWTClaims) map[string]interface{} {
+		mongoMock := new(mocks.AchievementMongoMock)
+		refMock := new(mocks.AchievementRefMock)
+		studentMock := new(mocks.StudentRepoMock)
+		rubricMock := new(mocks.RubricRepoMock)
+		historyMock := new(mocks.AchievementHistoryMock)
+		service := &services.AchievementService{
+			MongoRepo:   mongoMock,
+			RefRepo:     refMock,
+			StudentRepo: studentMock,
+			RubricRepo:  rubricMock,
+			HistoryRepo: historyMock,
+		}
+
+		id := submitted.Hex()
+		studentMock.On("FindByUserID", "user-student").Return(&models.Student{ID: "student-1"}, nil)
+		refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil)
+		mongoMock.On("FindByID", mock.Anything, id).Return(entry(submitted, "student-1", "Juara 1 Gemastik 2025"), nil)
+		rubricMock.On("FindByType", "competition").Return([]*models.RubricRule{}, nil)
+		refMock.On("SetApprovalKeyByMongoID", id, "competition", "").Return(nil)
+		refMock.On("UpdateStatusByMongoID", id, "student-1", "submitted", mock.Anything).Return(nil)
+		refMock.On("UpdateScoringByMongoID", id, "student-1", 0, (*string)(nil)).Return(nil)
+		historyMock.On("Create", mock.Anything).Return(nil)
+
+		mongoMock.On("FindDuplicateCandidates", mock.Anything, "competition", "student-1", &day, id).Return([]*models.MongoAchievement{
+			entry(ownCopy, "student-1", "Juara 1 Gemastik 2025"),
+			entry(teammate, "student-2", "Juara 1 Gemastik 2025 (tim)"),
+		}, nil)
+		// Satu query status untuk semua kandidat; anggota tim lain diabaikan.
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return len(f.MongoIDs) == 2 && f.MongoIDs[0] == ownCopy.Hex() && f.MongoIDs[1] == teammate.Hex()
+		})).Return([]*models.AchievementReference{
+			{MongoAchievementID: ownCopy.Hex(), StudentID: "student-1", Status: "draft"},
+			{MongoAchievementID: teammate.Hex(), StudentID: "student-2", Status: "verified"},
+			{MongoAchievementID: teammate.Hex(), StudentID: "student-3", Status: "draft", TeamRole: models.TeamRoleMember},
+		}, 3, nil).Once()
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user", claims)
+			return c.Next()
+		})
+		app.Post("/achievements/:id/submit", service.SubmitAchievement())
+
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/"+id+"/submit", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result
+	}
+
+	t.Run("Student - Other Students' Entries Redacted", func(t *testing.T) {
+		result := submit(t, &models.JWTClaims{UserID: "user-student", Role: "Mahasiswa"})
+
+		duplicates := result["possible_duplicates"].([]interface{})
+		assert.Len(t, duplicates, 1)
+		assert.Equal(t, ownCopy.Hex(), duplicates[0].(map[string]interface{})["achievementId"])
+		assert.Equal(t, float64(1), result["possible_teammate_entries"])
+
+		body, _ := json.Marshal(result)
+		assert.NotContains(t, string(body), teammate.Hex())
+		assert.NotContains(t, string(body), "student-2")
+	})
+
+	t.Run("Admin - All Matches", func(t *testing.T) {
+		result := submit(t, &models.JWTClaims{UserID: "user-admin", Role: "Admin"})
+
+		assert.Len(t, result["possible_duplicates"].([]interface{}), 2)
+		assert.Equal(t, float64(1), result["possible_teammate_entries"])
+	})
+}
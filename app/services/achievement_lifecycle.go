@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+)
+
+// achievementTransitions adalah satu-satunya sumber aturan perpindahan
+// status prestasi. Status yang tidak punya entri (verified, deleted)
+// bersifat final.
+var achievementTransitions = map[string][]string{
+	models.AchievementStatusDraft: {
+		models.AchievementStatusSubmitted,
+		models.AchievementStatusDeleted,
+	},
+	models.AchievementStatusSubmitted: {
+		models.AchievementStatusVerified,
+		models.AchievementStatusRejected,
+		models.AchievementStatusNeedsRevision,
+	},
+	models.AchievementStatusNeedsRevision: {
+		models.AchievementStatusSubmitted,
+		models.AchievementStatusDeleted,
+	},
+	models.AchievementStatusRejected: {
+		models.AchievementStatusDraft,
+		models.AchievementStatusDeleted,
+	},
+}
+
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition: %s -> %s", e.From, e.To)
+}
+
+// CheckTransition mengembalikan *TransitionError jika status from tidak
+// boleh berpindah ke status to.
+func CheckTransition(from, to string) error {
+	for _, next := range achievementTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return &TransitionError{From: from, To: to}
+}
+
+// IsEditable menandakan prestasi masih boleh diubah oleh mahasiswa.
+// Prestasi yang ditolak boleh diedit dan otomatis kembali menjadi draft,
+// sedangkan prestasi needs_revision diedit di tempat lalu diajukan ulang.
+func IsEditable(status string) bool {
+	return status == models.AchievementStatusDraft ||
+		status == models.AchievementStatusNeedsRevision ||
+		CheckTransition(status, models.AchievementStatusDraft) == nil
+}
+
+// canSubmit menandakan prestasi dengan status tersebut boleh dikirim untuk
+// diverifikasi.
+func canSubmit(status string) bool {
+	return CheckTransition(status, models.AchievementStatusSubmitted) == nil
+}
+
+func transitionConflict(c *fiber.Ctx, err error) error {
+	var te *TransitionError
+	if errors.As(err, &te) {
+		return c.Status(409).JSON(fiber.Map{
+			"error":            te.Error(),
+			"current_status":   te.From,
+			"requested_status": te.To,
+		})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+}
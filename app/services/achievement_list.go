@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// Ukuran halaman ListAchievements.
+const (
+	DefaultAchievementPageSize = 10
+	MaxAchievementPageSize     = 100
+)
+
+var achievementListStatuses = map[string]bool{
+	models.AchievementStatusDraft:         true,
+	models.AchievementStatusSubmitted:     true,
+	models.AchievementStatusVerified:      true,
+	models.AchievementStatusRejected:      true,
+	models.AchievementStatusNeedsRevision: true,
+}
+
+// parseAchievementListFilter membaca filter, urutan, dan halaman dari query
+// ListAchievements. Filter yang bergantung pada role diisi pemanggil.
+func parseAchievementListFilter(c *fiber.Ctx) (models.AchievementListFilter, int, int, error) {
+	var filter models.AchievementListFilter
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", DefaultAchievementPageSize)
+	if limit < 1 {
+		limit = DefaultAchievementPageSize
+	}
+	if limit > MaxAchievementPageSize {
+		limit = MaxAchievementPageSize
+	}
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
+
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if status == "" {
+			continue
+		}
+		if !achievementListStatuses[status] {
+			return filter, 0, 0, errors.New("unknown status " + status)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	filter.ProgramStudy = strings.TrimSpace(c.Query("program"))
+
+	var err error
+	if filter.From, err = parseListDate(c.Query("from"), false); err != nil {
+		return filter, 0, 0, errors.New("from must be YYYY-MM-DD or RFC3339")
+	}
+	if filter.To, err = parseListDate(c.Query("to"), true); err != nil {
+		return filter, 0, 0, errors.New("to must be YYYY-MM-DD or RFC3339")
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return filter, 0, 0, errors.New("from must be before to")
+	}
+
+	filter.Sort = c.Query("sort", models.AchievementSortCreatedAt)
+	switch filter.Sort {
+	case models.AchievementSortCreatedAt, models.AchievementSortUpdatedAt,
+		models.AchievementSortSubmittedAt, models.AchievementSortStatus, models.AchievementSortPoints:
+	default:
+		return filter, 0, 0, errors.New("unknown sort " + filter.Sort)
+	}
+
+	switch strings.ToLower(c.Query("order", "desc")) {
+	case "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		return filter, 0, 0, errors.New("order must be asc or desc")
+	}
+
+	return filter, page, limit, nil
+}
+
+// parseListDate menerima tanggal (YYYY-MM-DD) atau RFC3339. Tanggal saja
+// pada batas akhir berarti sampai akhir hari itu.
+func parseListDate(value string, endOfDay bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// restrictStudents mempersempit filter ke satu mahasiswa. Cakupan role
+// (mis. AdvisorIDs dosen wali) tetap berlaku sehingga mahasiswa di luar
+// cakupan menghasilkan daftar kosong.
+func restrictStudents(filter *models.AchievementListFilter, studentID string) {
+	if studentID != "" {
+		filter.StudentIDs = []string{studentID}
+	}
+}
+
+// restrictToStage membatasi filter ke prestasi submitted yang tahap
+// persetujuannya saat ini ditangani salah satu roles. Tahap dihitung di
+// query dari tipe dan tingkat yang disalin ke referensi saat dikirim,
+// sehingga tidak perlu membaca Mongo. Tanpa roles tidak ada yang cocok.
+func restrictToStage(filter *models.AchievementListFilter, roles ...string) {
+	if filter.Statuses != nil && !containsString(filter.Statuses, models.AchievementStatusSubmitted) {
+		filter.Statuses = []string{}
+		return
+	}
+	filter.Statuses = []string{models.AchievementStatusSubmitted}
+
+	filter.StageRoles = []string{}
+	for _, role := range roles {
+		filter.StageRoles = append(filter.StageRoles, strings.ToLower(role))
+	}
+}
+
+// docsByID membaca dokumen Mongo untuk refs dalam satu query.
+func (s *AchievementService) docsByID(ctx context.Context, refs []*models.AchievementReference) (map[string]*models.MongoAchievement, error) {
+	return findDocsByRefs(ctx, s.MongoRepo, refs)
+}
+
+func findDocsByRefs(
+	ctx context.Context,
+	mongoRepo repositories.IAchievementMongoRepository,
+	refs []*models.AchievementReference,
+) (map[string]*models.MongoAchievement, error) {
+
+	docs := map[string]*models.MongoAchievement{}
+	if len(refs) == 0 {
+		return docs, nil
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.MongoAchievementID)
+	}
+
+	found, err := mongoRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range found {
+		docs[doc.ID.Hex()] = doc
+	}
+	return docs, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestListAchievementsFilters(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	studentMock := new(mocks.StudentRepoMock)
+	lecturerMock := new(mocks.LecturerRepoMock)
+	delegationMock := new(mocks.DelegationRepoMock)
+	service := &services.AchievementService{
+		MongoRepo:      mongoMock,
+		RefRepo:        refMock,
+		StudentRepo:    studentMock,
+		LecturerRepo:   lecturerMock,
+		DelegationRepo: delegationMock,
+	}
+
+	newApp := func(claims *models.JWTClaims) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user", claims)
+			return c.Next()
+		})
+		app.Get("/achievements", service.ListAchievements())
+		return app
+	}
+	admin := newApp(&models.JWTClaims{UserID: "user-admin", Role: "Admin"})
+
+	t.Run("Admin - Filters Pushed To Repository", func(t *testing.T) {
+		docID := primitive.NewObjectID()
+		mongoMock.On("FilterIDs", mock.Anything, "competition", []string(nil)).Return([]string{docID.Hex()}, nil).Once()
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return f.Limit == 20 && f.Offset == 40 &&
+				f.Sort == "points" && !f.Desc &&
+				f.ProgramStudy == "Informatika" &&
+				len(f.Statuses) == 2 && f.Statuses[1] == "submitted" &&
+				len(f.MongoIDs) == 1 && f.MongoIDs[0] == docID.Hex() &&
+				len(f.StudentIDs) == 1 && f.StudentIDs[0] == "student-1" &&
+				f.From != nil && f.To != nil && f.To.Hour() == 23
+		})).Return([]*models.AchievementReference{
+			{MongoAchievementID: docID.Hex(), StudentID: "student-1", Status: "verified"},
+		}, 41, nil).Once()
+		mongoMock.On("FindByIDs", mock.Anything, []string{docID.Hex()}).Return([]*models.MongoAchievement{
+			{ID: docID, Title: "Juara 1 Hackathon", AchievementType: "competition"},
+		}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/achievements?page=3&limit=20&sort=points&order=asc&status=verified,submitted&type=competition&program=Informatika&student_id=student-1&from=2025-01-01&to=2025-06-30", nil)
+		resp, _ := admin.Test(req)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data       []map[string]interface{} `json:"data"`
+			Total      int                      `json:"total"`
+			Page       int                      `json:"page"`
+			TotalPages int                      `json:"total_pages"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "Juara 1 Hackathon", result.Data[0]["title"])
+		assert.Equal(t, 41, result.Total)
+		assert.Equal(t, 3, result.Page)
+		assert.Equal(t, 3, result.TotalPages)
+		refMock.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Sort", func(t *testing.T) {
+		resp, _ := admin.Test(httptest.NewRequest("GET", "/achievements?sort=title", nil))
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("Advisor - Submitted Only, Own And Delegated Advisees", func(t *testing.T) {
+		lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: "lecturer-1"}, nil).Once()
+		delegationMock.On("FindActiveByDelegate", "lecturer-1", mock.Anything).Return([]*models.VerificationDelegation{
+			{AdvisorID: "lecturer-2", DelegateID: "lecturer-1"},
+		}, nil).Once()
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return f.StudentIDs == nil &&
+				len(f.AdvisorIDs) == 2 && f.AdvisorIDs[1] == "lecturer-2" &&
+				len(f.Statuses) == 1 && f.Statuses[0] == "submitted" && f.RefIDs == nil &&
+				len(f.StageRoles) == 1 && f.StageRoles[0] == "advisor"
+		})).Return([]*models.AchievementReference{}, 0, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"}).Test(httptest.NewRequest("GET", "/achievements", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		refMock.AssertExpectations(t)
+		studentMock.AssertNotCalled(t, "FindByAdvisorID", mock.Anything)
+	})
+
+	t.Run("Advisor - Student Outside Scope", func(t *testing.T) {
+		lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: "lecturer-1"}, nil).Once()
+		delegationMock.On("FindActiveByDelegate", "lecturer-1", mock.Anything).Return([]*models.VerificationDelegation{}, nil).Once()
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			// student_id hanya mempersempit; cakupan dosen wali harus tetap ikut.
+			return len(f.StudentIDs) == 1 && f.StudentIDs[0] == "student-9" &&
+				len(f.AdvisorIDs) == 1 && f.AdvisorIDs[0] == "lecturer-1"
+		})).Return([]*models.AchievementReference{}, 0, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"}).Test(httptest.NewRequest("GET", "/achievements?student_id=student-9", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data  []map[string]interface{} `json:"data"`
+			Total int                      `json:"total"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Empty(t, result.Data)
+		assert.Equal(t, 0, result.Total)
+		refMock.AssertExpectations(t)
+	})
+
+	t.Run("Advisor - Draft Filter Matches Nothing", func(t *testing.T) {
+		lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: "lecturer-1"}, nil).Once()
+		delegationMock.On("FindActiveByDelegate", "lecturer-1", mock.Anything).Return([]*models.VerificationDelegation{}, nil).Once()
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return f.Statuses != nil && len(f.Statuses) == 0
+		})).Return([]*models.AchievementReference{}, 0, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"}).Test(httptest.NewRequest("GET", "/achievements?status=draft", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		refMock.AssertExpectations(t)
+	})
+
+	t.Run("Admin Awaiting - Includes Escalated Advisor Stage", func(t *testing.T) {
+		docID := primitive.NewObjectID()
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return f.IncludeEscalated && f.RefIDs == nil &&
+				len(f.StageRoles) == 1 && f.StageRoles[0] == "admin" &&
+				len(f.Statuses) == 1 && f.Statuses[0] == "submitted"
+		})).Return([]*models.AchievementReference{
+			{MongoAchievementID: docID.Hex(), StudentID: "student-1", Status: "submitted"},
+		}, 1, nil).Once()
+		mongoMock.On("FindByIDs", mock.Anything, []string{docID.Hex()}).Return([]*models.MongoAchievement{
+			{ID: docID, Title: "Juara 2 Gemastik", AchievementType: "competition"},
+		}, nil).Once()
+
+		resp, _ := admin.Test(httptest.NewRequest("GET", "/achievements?awaiting=true", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Len(t, result.Data, 1)
+		refMock.AssertExpectations(t)
+	})
+
+	t.Run("Faculty Reviewer - Own Stage Only", func(t *testing.T) {
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return !f.IncludeEscalated &&
+				len(f.StageRoles) == 1 && f.StageRoles[0] == "admin fakultas" &&
+				len(f.Statuses) == 1 && f.Statuses[0] == "submitted"
+		})).Return([]*models.AchievementReference{}, 0, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-faculty", Role: "Admin Fakultas"}).Test(httptest.NewRequest("GET", "/achievements", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		refMock.AssertExpectations(t)
+	})
+
+	t.Run("Missing Document - Kept In Page", func(t *testing.T) {
+		docID := primitive.NewObjectID()
+		missingID := primitive.NewObjectID()
+		refMock.On("List", mock.MatchedBy(func(f models.AchievementListFilter) bool {
+			return f.Limit == 2 && f.StudentIDs == nil
+		})).Return([]*models.AchievementReference{
+			{MongoAchievementID: docID.Hex(), StudentID: "student-1", Status: "verified"},
+			{MongoAchievementID: missingID.Hex(), StudentID: "student-2", Status: "draft"},
+		}, 2, nil).Once()
+		mongoMock.On("FindByIDs", mock.Anything, []string{docID.Hex(), missingID.Hex()}).Return([]*models.MongoAchievement{
+			{ID: docID, Title: "Juara 1 Hackathon", AchievementType: "competition"},
+		}, nil).Once()
+
+		resp, _ := admin.Test(httptest.NewRequest("GET", "/achievements?limit=2", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data  []map[string]interface{} `json:"data"`
+			Total int                      `json:"total"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Len(t, result.Data, 2)
+		assert.Equal(t, 2, result.Total)
+		assert.Nil(t, result.Data[0]["missingDocument"])
+		assert.Equal(t, missingID.Hex(), result.Data[1]["id"])
+		assert.Equal(t, true, result.Data[1]["missingDocument"])
+		refMock.AssertExpectations(t)
+	})
+
+	t.Run("Advisor - Delegation Lookup Fails", func(t *testing.T) {
+		lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: "lecturer-1"}, nil).Once()
+		delegationMock.On("FindActiveByDelegate", "lecturer-1", mock.Anything).Return(nil, assert.AnError).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"}).Test(httptest.NewRequest("GET", "/achievements", nil))
+		assert.Equal(t, 500, resp.StatusCode)
+		delegationMock.AssertExpectations(t)
+	})
+
+	t.Run("Approval Chain Lookup Fails", func(t *testing.T) {
+		chainMock := new(mocks.ApprovalChainRepoMock)
+		chainMock.On("FindAll").Return(nil, assert.AnError).Once()
+		failing := &services.AchievementService{
+			MongoRepo: mongoMock,
+			RefRepo:   refMock,
+			ChainRepo: chainMock,
+		}
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user", &models.JWTClaims{UserID: "user-admin", Role: "Admin"})
+			return c.Next()
+		})
+		app.Get("/achievements", failing.ListAchievements())
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/achievements?awaiting=true", nil))
+		assert.Equal(t, 500, resp.StatusCode)
+		chainMock.AssertExpectations(t)
+	})
+}
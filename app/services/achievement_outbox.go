@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// OutboxCompensationDelay adalah jeda sebelum relay membuang dokumen Mongo
+// yang referensinya gagal disimpan. Jeda ini harus lebih lama dari waktu
+// create normal agar dokumen yang masih diproses tidak ikut terbuang.
+const OutboxCompensationDelay = 5 * time.Minute
+
+// OutboxRelayBatch adalah jumlah pesan outbox yang diproses per putaran relay.
+const OutboxRelayBatch = 100
+
+// insertAchievement menyimpan dokumen Mongo dan referensi Postgres-nya
+// sebagai satu saga. Sebelum menulis Mongo dicatat pesan discard_document
+// yang tertunda; pesan itu ditandai selesai di transaksi yang sama dengan
+// referensi. Jika referensi gagal disimpan, dokumen langsung dibuang dan
+// bila pembuangan itu juga gagal relay yang akan membuangnya.
+func (s *AchievementService) insertAchievement(
+	ctx context.Context,
+	doc *models.MongoAchievement,
+	refs []*models.AchievementReference,
+) (string, error) {
+
+	doc.ID = primitive.NewObjectID()
+	mongoID := doc.ID.Hex()
+	for _, ref := range refs {
+		ref.MongoAchievementID = mongoID
+	}
+
+	intent := &models.OutboxEntry{
+		MongoAchievementID: mongoID,
+		Operation:          models.OutboxOpDiscardDocument,
+		AvailableAt:        time.Now().Add(OutboxCompensationDelay),
+	}
+	if s.OutboxRepo != nil {
+		if err := s.OutboxRepo.Enqueue(intent); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := s.MongoRepo.Insert(ctx, doc); err != nil {
+		s.markOutboxProcessed(intent.ID)
+		return "", err
+	}
+
+	if err := s.RefRepo.CreateWithOutbox(refs, intent.ID); err != nil {
+		if derr := s.MongoRepo.Discard(ctx, mongoID); derr != nil {
+			log.Println("outbox: discard", mongoID, "left to relay:", derr)
+		} else {
+			s.markOutboxProcessed(intent.ID)
+		}
+		return "", err
+	}
+
+	return mongoID, nil
+}
+
+// applyOutbox menerapkan perubahan dokumen Mongo yang sudah dicatat di
+// outbox. Jika gagal, pesan dibiarkan agar diulang relay.
+func (s *AchievementService) applyOutbox(outboxID string, apply func() error) {
+	if err := apply(); err != nil {
+		log.Println("outbox:", outboxID, "left to relay:", err)
+		return
+	}
+	s.markOutboxProcessed(outboxID)
+}
+
+func (s *AchievementService) markOutboxProcessed(outboxID string) {
+	if s.OutboxRepo == nil || outboxID == "" {
+		return
+	}
+	if err := s.OutboxRepo.MarkProcessed(outboxID); err != nil {
+		log.Println("outbox: mark processed", outboxID, ":", err)
+	}
+}
+
+// AchievementOutboxService mengulang perubahan dokumen Mongo yang tercatat
+// di outbox tetapi belum berhasil diterapkan.
+type AchievementOutboxService struct {
+	MongoRepo  repositories.IAchievementMongoRepository
+	RefRepo    repositories.IAchievementReferenceRepo
+	OutboxRepo repositories.IAchievementOutboxRepository
+}
+
+func NewAchievementOutboxService(
+	mongo repositories.IAchievementMongoRepository,
+	ref repositories.IAchievementReferenceRepo,
+	outbox repositories.IAchievementOutboxRepository,
+) *AchievementOutboxService {
+	return &AchievementOutboxService{
+		MongoRepo:  mongo,
+		RefRepo:    ref,
+		OutboxRepo: outbox,
+	}
+}
+
+// RunRelay menjalankan Relay setiap interval sampai ctx dibatalkan.
+func (s *AchievementOutboxService) RunRelay(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Relay(ctx, time.Now())
+		if err != nil {
+			log.Println("outbox relay:", err)
+		} else if result.Scanned > 0 {
+			log.Printf("outbox relay: %d processed, %d failed of %d", result.Processed, result.Failed, result.Scanned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay memproses pesan outbox yang sudah jatuh tempo. Pesan yang gagal
+// dijadwalkan ulang dengan jeda yang makin panjang.
+func (s *AchievementOutboxService) Relay(ctx context.Context, now time.Time) (*models.OutboxRelayResult, error) {
+	entries, err := s.OutboxRepo.Pending(now, OutboxRelayBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.OutboxRelayResult{Scanned: len(entries)}
+	for _, entry := range entries {
+		if err := s.apply(ctx, entry); err != nil {
+			result.Failed++
+			retryAt := now.Add(outboxBackoff(entry.Attempts + 1))
+			if err := s.OutboxRepo.MarkFailed(entry.ID, err.Error(), retryAt); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := s.OutboxRepo.MarkProcessed(entry.ID); err != nil {
+			return nil, err
+		}
+		result.Processed++
+	}
+
+	return result, nil
+}
+
+// apply menerapkan satu pesan sesuai keadaan referensi saat ini, sehingga
+// pesan yang diulang atau tertukar urutannya tetap menghasilkan dokumen
+// yang cocok dengan Postgres.
+func (s *AchievementOutboxService) apply(ctx context.Context, entry *models.OutboxEntry) error {
+	ref, err := s.RefRepo.GetByMongoID(entry.MongoAchievementID)
+	if err == sql.ErrNoRows {
+		ref = nil
+	} else if err != nil {
+		return err
+	}
+
+	switch entry.Operation {
+	case models.OutboxOpDiscardDocument:
+		// Referensi yang ternyata tersimpan berarti create berhasil.
+		if ref != nil {
+			return nil
+		}
+		return s.MongoRepo.Discard(ctx, entry.MongoAchievementID)
+	case models.OutboxOpSoftDeleteDocument, models.OutboxOpRestoreDocument:
+		if ref == nil {
+			return nil
+		}
+		if ref.Status == models.AchievementStatusDeleted {
+			return s.MongoRepo.SoftDelete(ctx, entry.MongoAchievementID)
+		}
+		return s.MongoRepo.Restore(ctx, entry.MongoAchievementID)
+	}
+	return nil
+}
+
+// outboxBackoff menggandakan jeda ulang per percobaan, maksimal satu jam.
+func outboxBackoff(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAchievementOutboxSaga(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	studentMock := new(mocks.StudentRepoMock)
+	historyMock := new(mocks.AchievementHistoryMock)
+	outboxMock := new(mocks.AchievementOutboxMock)
+	service := &services.AchievementService{
+		MongoRepo:   mongoMock,
+		RefRepo:     refMock,
+		StudentRepo: studentMock,
+		HistoryRepo: historyMock,
+		OutboxRepo:  outboxMock,
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: "user-student", Role: "Mahasiswa"})
+		return c.Next()
+	})
+	app.Post("/achievement", service.CreateAchievement())
+	app.Delete("/achievement/:id", service.DeleteAchievement())
+
+	studentMock.On("FindByUserID", "user-student").Return(&models.Student{ID: "student-1"}, nil)
+	historyMock.On("Create", mock.Anything).Return(nil)
+
+	t.Run("Create - References Fail, Document Discarded", func(t *testing.T) {
+		outboxMock.On("Enqueue", mock.MatchedBy(func(e *models.OutboxEntry) bool {
+			return e.Operation == models.OutboxOpDiscardDocument && e.AvailableAt.After(time.Now())
+		})).Run(func(args mock.Arguments) {
+			args.Get(0).(*models.OutboxEntry).ID = "outbox-1"
+		}).Return(nil).Once()
+		mongoMock.On("Insert", mock.Anything, mock.Anything).Return("", nil).Once()
+		refMock.On("CreateWithOutbox", mock.Anything, "outbox-1").Return(errors.New("pq: connection refused")).Once()
+		mongoMock.On("Discard", mock.Anything, mock.Anything).Return(nil).Once()
+		outboxMock.On("MarkProcessed", "outbox-1").Return(nil).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"achievementType": "competition",
+			"title":           "Juara 1 Hackathon",
+			"details":         map[string]interface{}{"competitionName": "Hackathon", "competitionLevel": "national", "rank": 1},
+		})
+		req := httptest.NewRequest("POST", "/achievement", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, 500, resp.StatusCode)
+		mongoMock.AssertExpectations(t)
+		outboxMock.AssertExpectations(t)
+	})
+
+	t.Run("Delete - Reference Update Fails", func(t *testing.T) {
+		id := "delete-1"
+		refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil).Once()
+		refMock.On("SoftDeleteWithOutbox", id).Return("", errors.New("pq: deadlock detected")).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("DELETE", "/achievement/"+id, nil))
+		assert.Equal(t, 500, resp.StatusCode)
+		mongoMock.AssertNotCalled(t, "SoftDelete", mock.Anything, id)
+	})
+
+	t.Run("Delete - Mongo Fails, Left To Relay", func(t *testing.T) {
+		id := "delete-2"
+		refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil).Once()
+		refMock.On("SoftDeleteWithOutbox", id).Return("outbox-2", nil).Once()
+		mongoMock.On("SoftDelete", mock.Anything, id).Return(errors.New("mongo: timeout")).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("DELETE", "/achievement/"+id, nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		outboxMock.AssertNotCalled(t, "MarkProcessed", "outbox-2")
+	})
+}
+
+func TestAchievementOutboxRelay(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	outboxMock := new(mocks.AchievementOutboxMock)
+	service := services.NewAchievementOutboxService(mongoMock, refMock, outboxMock)
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	outboxMock.On("Pending", now, services.OutboxRelayBatch).Return([]*models.OutboxEntry{
+		{ID: "o-1", MongoAchievementID: "orphan", Operation: models.OutboxOpDiscardDocument},
+		{ID: "o-2", MongoAchievementID: "created", Operation: models.OutboxOpDiscardDocument},
+		{ID: "o-3", MongoAchievementID: "deleted", Operation: models.OutboxOpSoftDeleteDocument, Attempts: 1},
+		{ID: "o-4", MongoAchievementID: "orphan-scanned", Operation: models.OutboxOpDiscardDocument},
+	}, nil).Once()
+
+	refMock.On("GetByMongoID", "orphan").Return(nil, sql.ErrNoRows).Once()
+	mongoMock.On("Discard", mock.Anything, "orphan").Return(nil).Once()
+	refMock.On("GetByMongoID", "created").Return(&models.AchievementReference{Status: "draft"}, nil).Once()
+	refMock.On("GetByMongoID", "deleted").Return(&models.AchievementReference{Status: "deleted"}, nil).Once()
+	mongoMock.On("SoftDelete", mock.Anything, "deleted").Return(errors.New("mongo: timeout")).Once()
+	// Referensi kosong yang menyertai ErrNoRows tidak dianggap tersimpan.
+	refMock.On("GetByMongoID", "orphan-scanned").Return(&models.AchievementReference{}, sql.ErrNoRows).Once()
+	mongoMock.On("Discard", mock.Anything, "orphan-scanned").Return(nil).Once()
+
+	outboxMock.On("MarkProcessed", "o-1").Return(nil).Once()
+	outboxMock.On("MarkProcessed", "o-2").Return(nil).Once()
+	outboxMock.On("MarkProcessed", "o-4").Return(nil).Once()
+	outboxMock.On("MarkFailed", "o-3", "mongo: timeout", now.Add(2*time.Minute)).Return(nil).Once()
+
+	result, err := service.Relay(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Scanned)
+	assert.Equal(t, 3, result.Processed)
+	assert.Equal(t, 1, result.Failed)
+	mongoMock.AssertNotCalled(t, "Discard", mock.Anything, "created")
+	mongoMock.AssertExpectations(t)
+	outboxMock.AssertExpectations(t)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+)
+
+// MaxBulkReviewItems membatasi jumlah prestasi per permintaan massal.
+const MaxBulkReviewItems = 100
+
+// OverrideError dikembalikan saat poin diubah dari hasil rubrik tanpa justifikasi.
+type OverrideError struct {
+	ComputedPoints int
+}
+
+func (e *OverrideError) Error() string {
+	return "justification is required to override computed points"
+}
+
+// reviewFailed memetakan error verify/reject ke respons HTTP.
+func reviewFailed(c *fiber.Ctx, err error) error {
+	var (
+		te *TransitionError
+		oe *OverrideError
+	)
+	switch {
+	case errors.Is(err, errAchievementNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+	case errors.Is(err, ErrAchievementForbidden), errors.Is(err, ErrStudentNotFound):
+		return accessDenied(c, err)
+	case errors.As(err, &te):
+		return transitionConflict(c, err)
+	case errors.Is(err, ErrAlreadyApproved):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ErrTeamMemberRequired):
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &oe):
+		return c.Status(400).JSON(fiber.Map{
+			"error":           oe.Error(),
+			"computed_points": oe.ComputedPoints,
+		})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+}
+
+// BulkVerifyAchievements godoc
+// @Summary Bulk verify achievements
+// @Description Dosen wali (atau admin) memverifikasi banyak prestasi sekaligus.
+// @Description Setiap item diperiksa dan diproses terpisah seperti VerifyAchievement.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param items body object true "{\"items\": [{\"id\": \"...\", \"student_id\": \"...\", \"points\": 0, \"justification\": \"...\"}]}"
+// @Success 200 {object} models.BulkReviewReport
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/bulk/verify [post]
+func (s *AchievementService) BulkVerifyAchievements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var payload struct {
+			Items []struct {
+				ID            string `json:"id"`
+				StudentID     string `json:"student_id"`
+				Points        *int   `json:"points"`
+				Justification string `json:"justification"`
+			} `json:"items"`
+		}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if msg := checkBulkSize(len(payload.Items)); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		report := &models.BulkReviewReport{Results: []*models.BulkReviewItemResult{}}
+		for _, item := range payload.Items {
+			id := strings.TrimSpace(item.ID)
+
+			result, err := s.verify(user, id, strings.TrimSpace(item.StudentID), item.Points, item.Justification)
+			entry := bulkItemResult(id, err)
+			if err == nil {
+				entry.Status = result.Status
+				entry.StudentID = result.StudentID
+				entry.NextStage = result.NextStage
+				if result.NextStage == "" {
+					entry.Points = &result.Points
+					entry.ComputedPoints = &result.ComputedPoints
+					entry.AwardedPoints = &result.AwardedPoints
+					entry.Overridden = result.Overridden
+				}
+			}
+			addBulkResult(report, entry)
+		}
+
+		return c.JSON(report)
+	}
+}
+
+// BulkRejectAchievements godoc
+// @Summary Bulk reject achievements
+// @Description Dosen wali (atau admin) menolak banyak prestasi sekaligus, masing-masing dengan catatan.
+// @Description Setiap item diperiksa dan diproses terpisah seperti RejectAchievement.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param items body object true "{\"items\": [{\"id\": \"...\", \"student_id\": \"...\", \"rejection_note\": \"...\"}]}"
+// @Success 200 {object} models.BulkReviewReport
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/bulk/reject [post]
+func (s *AchievementService) BulkRejectAchievements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var payload struct {
+			Items []struct {
+				ID            string `json:"id"`
+				StudentID     string `json:"student_id"`
+				RejectionNote string `json:"rejection_note"`
+			} `json:"items"`
+		}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if msg := checkBulkSize(len(payload.Items)); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		report := &models.BulkReviewReport{Results: []*models.BulkReviewItemResult{}}
+		for _, item := range payload.Items {
+			id := strings.TrimSpace(item.ID)
+
+			entry := bulkItemResult(id, s.reject(user, id, strings.TrimSpace(item.StudentID), item.RejectionNote))
+			if entry.Outcome == models.BulkOutcomeSucceeded {
+				entry.Status = models.AchievementStatusRejected
+			}
+			addBulkResult(report, entry)
+		}
+
+		return c.JSON(report)
+	}
+}
+
+func checkBulkSize(n int) string {
+	if n == 0 {
+		return "items is required"
+	}
+	if n > MaxBulkReviewItems {
+		return "too many items, maximum is " + strconv.Itoa(MaxBulkReviewItems)
+	}
+	return ""
+}
+
+func addBulkResult(r *models.BulkReviewReport, entry *models.BulkReviewItemResult) {
+	r.Total++
+	switch entry.Outcome {
+	case models.BulkOutcomeSucceeded:
+		r.Succeeded++
+	case models.BulkOutcomeConflict:
+		r.Conflicts++
+	case models.BulkOutcomeForbidden:
+		r.Forbidden++
+	case models.BulkOutcomeNotFound:
+		r.NotFound++
+	case models.BulkOutcomeInvalid:
+		r.Invalid++
+	default:
+		r.Failed++
+	}
+	r.Results = append(r.Results, entry)
+}
+
+// bulkItemResult mengklasifikasikan error satu item dengan aturan yang sama
+// seperti reviewFailed.
+func bulkItemResult(id string, err error) *models.BulkReviewItemResult {
+	entry := &models.BulkReviewItemResult{ID: id, Outcome: models.BulkOutcomeSucceeded}
+	if err == nil {
+		return entry
+	}
+
+	entry.Error = err.Error()
+
+	var (
+		te *TransitionError
+		oe *OverrideError
+	)
+	switch {
+	case id == "", errors.Is(err, errAchievementNotFound), errors.Is(err, ErrStudentNotFound):
+		entry.Outcome = models.BulkOutcomeNotFound
+	case errors.Is(err, ErrAchievementForbidden):
+		entry.Outcome = models.BulkOutcomeForbidden
+	case errors.As(err, &te):
+		entry.Outcome = models.BulkOutcomeConflict
+		entry.CurrentStatus = te.From
+	case errors.Is(err, ErrAlreadyApproved):
+		entry.Outcome = models.BulkOutcomeConflict
+	case errors.Is(err, ErrTeamMemberRequired):
+		entry.Outcome = models.BulkOutcomeInvalid
+	case errors.As(err, &oe):
+		entry.Outcome = models.BulkOutcomeInvalid
+		entry.ComputedPoints = &oe.ComputedPoints
+	default:
+		entry.Outcome = models.BulkOutcomeFailed
+	}
+	return entry
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"uas/app/models"
+)
+
+// GetAchievementRevisions godoc
+// @Summary List achievement revisions
+// @Description Melihat semua revisi isi prestasi beserta penulis dan waktunya
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/revisions [get]
+func (s *AchievementService) GetAchievementRevisions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		ref, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		if _, err := s.viewableRef(user, ref); err != nil {
+			return accessDenied(c, err)
+		}
+
+		revisions, err := s.RevisionRepo.FindByAchievementID(context.Background(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if revisions == nil {
+			revisions = []*models.AchievementRevision{}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":  revisions,
+			"total": len(revisions),
+		})
+	}
+}
+
+// DiffAchievementRevisions godoc
+// @Summary Diff two achievement revisions
+// @Description Melihat perbedaan per field antara dua revisi prestasi.
+// @Description Default: to = revisi terakhir, from = revisi sebelum to.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param from query int false "From version"
+// @Param to query int false "To version"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/revisions/diff [get]
+func (s *AchievementService) DiffAchievementRevisions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		ref, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		if _, err := s.viewableRef(user, ref); err != nil {
+			return accessDenied(c, err)
+		}
+
+		revisions, err := s.RevisionRepo.FindByAchievementID(context.Background(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if len(revisions) == 0 {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement has no revisions"})
+		}
+
+		byVersion := map[int]*models.AchievementRevision{}
+		latest := 0
+		for _, rev := range revisions {
+			byVersion[rev.Version] = rev
+			if rev.Version > latest {
+				latest = rev.Version
+			}
+		}
+
+		to, err := strconv.Atoi(c.Query("to", strconv.Itoa(latest)))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "to must be a version number"})
+		}
+		from, err := strconv.Atoi(c.Query("from", strconv.Itoa(to-1)))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "from must be a version number"})
+		}
+
+		toRev, ok := byVersion[to]
+		if !ok {
+			return c.Status(404).JSON(fiber.Map{"error": "revision " + strconv.Itoa(to) + " not found"})
+		}
+		fromRev, ok := byVersion[from]
+		if !ok {
+			return c.Status(404).JSON(fiber.Map{"error": "revision " + strconv.Itoa(from) + " not found"})
+		}
+
+		changes, err := DiffRevisions(fromRev, toRev)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{
+			"from":    fromRev,
+			"to":      toRev,
+			"changes": changes,
+		})
+	}
+}
+
+// DiffRevisions membandingkan isi dua revisi per field. Dokumen bersarang
+// (details, customFields) dibandingkan per field daun; array dibandingkan
+// utuh.
+func DiffRevisions(from, to *models.AchievementRevision) ([]models.RevisionChange, error) {
+	before, err := flattenRevision(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flattenRevision(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []models.RevisionChange{}
+	for _, field := range sortedKeys(before) {
+		old := before[field]
+		cur, ok := after[field]
+		switch {
+		case !ok:
+			changes = append(changes, models.RevisionChange{Field: field, Kind: models.RevisionChangeRemoved, Before: old})
+		case !reflect.DeepEqual(old, cur):
+			changes = append(changes, models.RevisionChange{Field: field, Kind: models.RevisionChangeModified, Before: old, After: cur})
+		}
+	}
+	for _, field := range sortedKeys(after) {
+		if _, ok := before[field]; !ok {
+			changes = append(changes, models.RevisionChange{Field: field, Kind: models.RevisionChangeAdded, After: after[field]})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// flattenRevision mengubah isi revisi menjadi map nama field bertitik ke
+// nilai, memakai nama bson yang sama dengan dokumen prestasi.
+func flattenRevision(rev *models.AchievementRevision) (map[string]interface{}, error) {
+	raw, err := bson.Marshal(bson.M{
+		"title":       rev.Title,
+		"description": rev.Description,
+		"details":     rev.Details,
+		"attachments": rev.Attachments,
+		"tags":        rev.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	flat := map[string]interface{}{}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		switch val := v.(type) {
+		case bson.M:
+			for k, child := range val {
+				walk(prefix+"."+k, child)
+			}
+		case primitive.D:
+			for _, e := range val {
+				walk(prefix+"."+e.Key, e.Value)
+			}
+		case nil:
+			// Field kosong dianggap tidak ada.
+		default:
+			flat[prefix] = revisionValue(val)
+		}
+	}
+	for k, v := range doc {
+		walk(k, v)
+	}
+
+	return flat, nil
+}
+
+// revisionValue menormalkan tipe bson agar mudah dibandingkan dan dibaca
+// sebagai JSON.
+func revisionValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case primitive.DateTime:
+		return val.Time().UTC()
+	case primitive.A:
+		out := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			out = append(out, revisionDocument(item))
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	}
+	return v
+}
+
+func revisionDocument(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.M:
+		m := map[string]interface{}{}
+		for k, child := range val {
+			m[k] = revisionDocument(child)
+		}
+		return m
+	case primitive.D:
+		m := map[string]interface{}{}
+		for _, e := range val {
+			m[e.Key] = revisionDocument(e.Value)
+		}
+		return m
+	}
+	return revisionValue(v)
+}
+
+// recordRevision membaca ulang dokumen yang baru disimpan dan menyimpannya
+// sebagai revisi baru. Jika revisi terakhir tidak sama dengan before
+// (prestasi lama tanpa revisi, atau revisi sebelumnya gagal dicatat),
+// before disimpan dulu sebagai revisi dasar agar riwayat tidak berlubang.
+// Dipanggil setelah perubahan tersimpan, sehingga pemanggil melaporkan
+// kegagalannya di respons lewat withRevisionError alih-alih menggagalkan
+// request.
+func (s *AchievementService) recordRevision(
+	ctx context.Context,
+	user *models.JWTClaims,
+	ref *models.AchievementReference,
+	before *models.MongoAchievement,
+) error {
+	if s.RevisionRepo == nil {
+		return nil
+	}
+
+	err := s.appendRevision(ctx, user, ref, before)
+	if err != nil {
+		log.Println("achievement revision", ref.MongoAchievementID, ":", err)
+	}
+	return err
+}
+
+func (s *AchievementService) appendRevision(
+	ctx context.Context,
+	user *models.JWTClaims,
+	ref *models.AchievementReference,
+	before *models.MongoAchievement,
+) error {
+	after, err := s.MongoRepo.FindByID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return err
+	}
+
+	if before != nil {
+		latest, err := s.RevisionRepo.Latest(ctx, ref.MongoAchievementID)
+		if err != nil {
+			return err
+		}
+
+		base := newRevision(ref, before)
+		recorded, err := sameRevisionContent(latest, base)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			base.CreatedAt = before.UpdatedAt
+			if err := s.RevisionRepo.Append(ctx, base); err != nil {
+				return err
+			}
+		}
+	}
+
+	rev := newRevision(ref, after)
+	rev.AuthorID = user.UserID
+	rev.AuthorRole = user.Role
+	return s.RevisionRepo.Append(ctx, rev)
+}
+
+// sameRevisionContent bernilai true jika isi a dan b sama. Revisi nil
+// tidak sama dengan apa pun.
+func sameRevisionContent(a, b *models.AchievementRevision) (bool, error) {
+	if a == nil || b == nil {
+		return false, nil
+	}
+	changes, err := DiffRevisions(a, b)
+	if err != nil {
+		return false, err
+	}
+	return len(changes) == 0, nil
+}
+
+// withRevisionError menambahkan revision_error ke respons jika revisi gagal
+// dicatat setelah perubahan tersimpan.
+func withRevisionError(resp fiber.Map, err error) fiber.Map {
+	if err != nil {
+		resp["revision_error"] = err.Error()
+	}
+	return resp
+}
+
+func newRevision(ref *models.AchievementReference, doc *models.MongoAchievement) *models.AchievementRevision {
+	return &models.AchievementRevision{
+		AchievementID: ref.MongoAchievementID,
+		Status:        ref.Status,
+		Title:         doc.Title,
+		Description:   doc.Description,
+		Details:       doc.Details,
+		Attachments:   doc.Attachments,
+		Tags:          doc.Tags,
+	}
+}
@@ -0,0 +1,147 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	oldName, newName := "Gemastik", "Gemastik XVII"
+	level := "national"
+	eventDate := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	from := &models.AchievementRevision{
+		Version: 1,
+		Title:   "Juara 2",
+		Details: models.AchievementDetails{CompetitionName: &oldName, CompetitionLevel: &level},
+		Tags:    []string{"ai"},
+	}
+	to := &models.AchievementRevision{
+		Version: 2,
+		Title:   "Juara 2",
+		Details: models.AchievementDetails{CompetitionName: &newName, EventDate: &eventDate},
+		Tags:    []string{"ai", "data"},
+	}
+
+	changes, err := services.DiffRevisions(from, to)
+	assert.NoError(t, err)
+
+	byField := map[string]models.RevisionChange{}
+	for _, ch := range changes {
+		byField[ch.Field] = ch
+	}
+
+	assert.Len(t, changes, 4)
+	assert.Equal(t, models.RevisionChangeModified, byField["details.competitionName"].Kind)
+	assert.Equal(t, "Gemastik", byField["details.competitionName"].Before)
+	assert.Equal(t, "Gemastik XVII", byField["details.competitionName"].After)
+	assert.Equal(t, models.RevisionChangeRemoved, byField["details.competitionLevel"].Kind)
+	assert.Equal(t, models.RevisionChangeAdded, byField["details.eventDate"].Kind)
+	assert.Equal(t, eventDate, byField["details.eventDate"].After)
+	assert.Equal(t, models.RevisionChangeModified, byField["tags"].Kind)
+	assert.NotContains(t, byField, "title")
+}
+
+func TestUpdateRecordsRevision(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	studentMock := new(mocks.StudentRepoMock)
+	revisionMock := new(mocks.AchievementRevisionRepoMock)
+	service := &services.AchievementService{
+		MongoRepo:    mongoMock,
+		RefRepo:      refMock,
+		StudentRepo:  studentMock,
+		RevisionRepo: revisionMock,
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: "user-student", Role: "Mahasiswa"})
+		return c.Next()
+	})
+	app.Put("/achievement/:id", service.UpdateAchievement())
+
+	update := func(id, title string) (int, map[string]interface{}) {
+		body, _ := json.Marshal(map[string]string{"title": title})
+		req := httptest.NewRequest("PUT", "/achievement/"+id, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	studentMock.On("FindByUserID", "user-student").Return(&models.Student{ID: "student-1"}, nil)
+	mongoMock.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// FindByID pertama membaca isi lama, yang kedua membaca ulang isi yang
+	// tersimpan untuk revisi.
+	stored := func(id, before, after string) {
+		mongoMock.On("FindByID", mock.Anything, id).Return(&models.MongoAchievement{
+			AchievementType: "other",
+			Title:           before,
+		}, nil).Once()
+		mongoMock.On("FindByID", mock.Anything, id).Return(&models.MongoAchievement{
+			AchievementType: "other",
+			Title:           after,
+			Tags:            []string{"disimpan"},
+		}, nil).Once()
+	}
+
+	id := "rev-1"
+	refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil)
+	stored(id, "Lama", "Baru")
+
+	// Dokumen lama tanpa revisi: revisi dasar lalu revisi dari dokumen tersimpan.
+	revisionMock.On("Latest", mock.Anything, id).Return(nil, nil).Once()
+	revisionMock.On("Append", mock.Anything, mock.MatchedBy(func(r *models.AchievementRevision) bool {
+		return r.Title == "Lama" && r.AuthorID == ""
+	})).Return(nil).Once()
+	revisionMock.On("Append", mock.Anything, mock.MatchedBy(func(r *models.AchievementRevision) bool {
+		return r.Title == "Baru" && r.AuthorID == "user-student" && r.Status == "draft" &&
+			len(r.Tags) == 1 && r.Tags[0] == "disimpan"
+	})).Return(nil).Once()
+
+	code, result := update(id, "Baru")
+	assert.Equal(t, 200, code)
+	assert.NotContains(t, result, "revision_error")
+	revisionMock.AssertExpectations(t)
+
+	// Revisi terakhir sama dengan isi lama: tidak perlu revisi dasar.
+	currentID := "rev-3"
+	refMock.On("GetByMongoID", currentID).Return(&models.AchievementReference{MongoAchievementID: currentID, StudentID: "student-1", Status: "draft"}, nil)
+	stored(currentID, "Sama", "Berikutnya")
+	revisionMock.On("Latest", mock.Anything, currentID).Return(&models.AchievementRevision{Version: 2, Title: "Sama"}, nil).Once()
+	revisionMock.On("Append", mock.Anything, mock.MatchedBy(func(r *models.AchievementRevision) bool {
+		return r.AchievementID == currentID
+	})).Return(nil).Once()
+
+	code, _ = update(currentID, "Berikutnya")
+	assert.Equal(t, 200, code)
+	revisionMock.AssertExpectations(t)
+
+	// Perubahan sudah tersimpan; revisi yang gagal dicatat tidak menggagalkan
+	// request tetapi dilaporkan di respons.
+	failedID := "rev-2"
+	refMock.On("GetByMongoID", failedID).Return(&models.AchievementReference{MongoAchievementID: failedID, StudentID: "student-1", Status: "draft"}, nil)
+	stored(failedID, "Lama", "Baru lagi")
+	revisionMock.On("Latest", mock.Anything, failedID).Return(&models.AchievementRevision{Version: 3, Title: "Lama"}, nil).Once()
+	revisionMock.On("Append", mock.Anything, mock.Anything).Return(errors.New("mongo: timeout")).Once()
+
+	code, result = update(failedID, "Baru lagi")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "mongo: timeout", result["revision_error"])
+	mongoMock.AssertCalled(t, "Update", mock.Anything, failedID, mock.Anything)
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+)
+
+// Batas pencarian prestasi.
+const (
+	DefaultSearchPageSize = 10
+	MaxSearchPageSize     = 50
+	MinSearchQueryLength  = 2
+
+	// searchSnippetLength adalah panjang kira-kira potongan deskripsi yang
+	// disorot.
+	searchSnippetLength = 160
+)
+
+// SearchAchievements godoc
+// @Summary Search achievements
+// @Description Pencarian teks penuh pada judul, deskripsi, nama lomba, judul publikasi, nama organisasi, dan tag.
+// @Description Hasil diurutkan dari yang paling relevan; highlights berisi potongan field yang cocok dengan
+// @Description kata kunci ditandai <mark>. Mahasiswa hanya mencari prestasinya sendiri, dosen wali prestasi
+// @Description submitted mahasiswa bimbingannya yang menunggu di tahap dosen wali, admin semua prestasi.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param q query string true "Kata kunci; \"frasa\" dan -kata didukung"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page (default 10, max 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/search [get]
+func (s *AchievementService) SearchAchievements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+		ctx := context.Background()
+
+		q := strings.TrimSpace(c.Query("q"))
+		if len([]rune(q)) < MinSearchQueryLength {
+			return c.Status(400).JSON(fiber.Map{"error": "q must be at least 2 characters"})
+		}
+
+		page := c.QueryInt("page", 1)
+		if page < 1 {
+			page = 1
+		}
+		limit := c.QueryInt("limit", DefaultSearchPageSize)
+		if limit < 1 {
+			limit = DefaultSearchPageSize
+		}
+		if limit > MaxSearchPageSize {
+			limit = MaxSearchPageSize
+		}
+
+		// scope nil berarti semua prestasi (admin).
+		var scope *models.AchievementListFilter
+
+		switch strings.ToLower(user.Role) {
+
+		case "mahasiswa":
+			student, err := s.StudentRepo.FindByUserID(user.UserID)
+			if err != nil {
+				return c.Status(404).JSON(fiber.Map{"error": "student profile not found"})
+			}
+			scope = &models.AchievementListFilter{StudentIDs: []string{student.ID}}
+
+		case "dosen wali", "dosen_wali":
+			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
+			if err != nil {
+				return c.Status(404).JSON(fiber.Map{"error": "lecturer profile not found"})
+			}
+			delegated, err := s.delegatedAdvisors(lecturer.ID)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			// Cakupan sama dengan daftar prestasi dosen wali: hanya yang
+			// menunggu di tahap dosen wali, bukan draft mahasiswa.
+			scope = &models.AchievementListFilter{
+				AdvisorIDs: append([]string{lecturer.ID}, delegated...),
+			}
+			restrictToStage(scope, models.ApprovalRoleAdvisor)
+
+		case "admin":
+
+		default:
+			return c.Status(403).JSON(fiber.Map{"error": "search is not available for this role"})
+		}
+
+		// Referensi dalam cakupan dibaca sekali: ID-nya membatasi pencarian
+		// Mongo dan statusnya dipakai untuk hasil.
+		var (
+			ids  []string
+			refs = map[string]*models.AchievementReference{}
+		)
+		if scope != nil {
+			scoped, _, err := s.RefRepo.List(*scope)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			ids = []string{}
+			for _, ref := range scoped {
+				if _, ok := refs[ref.MongoAchievementID]; !ok {
+					ids = append(ids, ref.MongoAchievementID)
+					refs[ref.MongoAchievementID] = ref
+				}
+			}
+		}
+
+		var (
+			hits  []*models.AchievementSearchHit
+			total int
+		)
+		if ids == nil || len(ids) > 0 {
+			var err error
+			hits, total, err = s.MongoRepo.Search(ctx, q, ids, limit, (page-1)*limit)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		if scope == nil && len(hits) > 0 {
+			hitIDs := make([]string, 0, len(hits))
+			for _, hit := range hits {
+				hitIDs = append(hitIDs, hit.ID.Hex())
+			}
+			found, _, err := s.RefRepo.List(models.AchievementListFilter{MongoIDs: hitIDs})
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			for _, ref := range found {
+				if _, ok := refs[ref.MongoAchievementID]; !ok || ref.TeamRole == models.TeamRoleOwner {
+					refs[ref.MongoAchievementID] = ref
+				}
+			}
+		}
+
+		terms := searchTerms(q)
+		results := []fiber.Map{}
+		for _, hit := range hits {
+			ref, ok := refs[hit.ID.Hex()]
+			if !ok {
+				// Dokumen tanpa referensi aktif tidak ditampilkan.
+				continue
+			}
+			results = append(results, fiber.Map{
+				"id":         hit.ID.Hex(),
+				"title":      hit.Title,
+				"type":       hit.AchievementType,
+				"status":     ref.Status,
+				"studentId":  ref.StudentID,
+				"tags":       hit.Tags,
+				"score":      hit.Score,
+				"highlights": searchHighlights(&hit.MongoAchievement, terms),
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"data":        results,
+			"query":       q,
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + limit - 1) / limit,
+		})
+	}
+}
+
+// searchTerms mengambil kata dan frasa dari query $text untuk disorot.
+// Kata yang dinegasikan (-kata) tidak disorot.
+func searchTerms(q string) []string {
+	var terms []string
+
+	for i, part := range strings.Split(q, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if strings.HasPrefix(word, "-") {
+				continue
+			}
+			if word = strings.Trim(word, ".,;:!?()[]{}"); word != "" {
+				terms = append(terms, word)
+			}
+		}
+	}
+	return terms
+}
+
+// searchHighlights menandai kata kunci pada field yang diindeks. Hanya
+// field yang cocok yang dikembalikan; deskripsi dipotong di sekitar
+// kecocokan pertama.
+func searchHighlights(doc *models.MongoAchievement, terms []string) map[string]interface{} {
+	highlights := map[string]interface{}{}
+	if len(terms) == 0 {
+		return highlights
+	}
+
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	re := regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+
+	fields := map[string]string{
+		"title":            doc.Title,
+		"competitionName":  deref(doc.Details.CompetitionName),
+		"publicationTitle": deref(doc.Details.PublicationTitle),
+		"organizationName": deref(doc.Details.OrganizationName),
+	}
+	for field, text := range fields {
+		if marked, ok := markMatches(re, text); ok {
+			highlights[field] = marked
+		}
+	}
+
+	if loc := re.FindStringIndex(doc.Description); loc != nil {
+		marked, _ := markMatches(re, snippet(doc.Description, loc[0], loc[1]))
+		highlights["description"] = marked
+	}
+
+	var tags []string
+	for _, tag := range doc.Tags {
+		if marked, ok := markMatches(re, tag); ok {
+			tags = append(tags, marked)
+		}
+	}
+	if len(tags) > 0 {
+		highlights["tags"] = tags
+	}
+
+	return highlights
+}
+
+// markMatches meng-escape text sebagai HTML lalu membungkus setiap
+// kecocokan dengan <mark>.
+func markMatches(re *regexp.Regexp, text string) (string, bool) {
+	locs := re.FindAllStringIndex(text, -1)
+	if len(locs) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString("</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String(), true
+}
+
+// snippet memotong text sekitar searchSnippetLength karakter di sekitar
+// kecocokan [start, end), dimulai dan diakhiri di batas kata.
+func snippet(text string, start, end int) string {
+	if len(text) <= searchSnippetLength {
+		return text
+	}
+
+	from := start - searchSnippetLength/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + searchSnippetLength
+	if to < end {
+		to = end
+	}
+	if to > len(text) {
+		to = len(text)
+	}
+
+	if from > 0 {
+		if i := strings.IndexByte(text[from:start], ' '); i >= 0 {
+			from += i + 1
+		} else {
+			from = start
+		}
+	}
+	if to < len(text) {
+		if i := strings.LastIndexByte(text[end:to], ' '); i >= 0 {
+			to = end + i
+		} else {
+			to = end
+		}
+	}
+
+	out := text[from:to]
+	if from > 0 {
+		out = "…" + out
+	}
+	if to < len(text) {
+		out += "…"
+	}
+	return out
+}
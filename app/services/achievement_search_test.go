@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSearchAchievements(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	studentMock := new(mocks.StudentRepoMock)
+	lecturerMock := new(mocks.LecturerRepoMock)
+	delegationMock := new(mocks.DelegationRepoMock)
+	service := &services.AchievementService{
+		MongoRepo:      mongoMock,
+		RefRepo:        refMock,
+		StudentRepo:    studentMock,
+		LecturerRepo:   lecturerMock,
+		DelegationRepo: delegationMock,
+	}
+
+	newApp := func(claims *models.JWTClaims) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user", claims)
+			return c.Next()
+		})
+		app.Get("/achievements/search", service.SearchAchievements())
+		return app
+	}
+
+	type searchResult struct {
+		Data []struct {
+			ID         string                 `json:"id"`
+			Status     string                 `json:"status"`
+			Score      float64                `json:"score"`
+			Highlights map[string]interface{} `json:"highlights"`
+		} `json:"data"`
+		Total int `json:"total"`
+	}
+
+	ownID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+	desc := strings.Repeat("Tim kami mengembangkan sistem irigasi. ", 10) + "Model deteksi hama berbasis AI <v2> dilatih di lapangan." + strings.Repeat(" Uji coba dilakukan di tiga desa.", 10)
+	competition := "Gemastik AI Challenge"
+
+	t.Run("Student - Scoped To Own Achievements", func(t *testing.T) {
+		studentMock.On("FindByUserID", "user-student").Return(&models.Student{ID: "student-1"}, nil).Once()
+		refMock.On("List", models.AchievementListFilter{StudentIDs: []string{"student-1"}}).Return([]*models.AchievementReference{
+			{MongoAchievementID: ownID.Hex(), StudentID: "student-1", Status: "verified"},
+		}, 1, nil).Once()
+		mongoMock.On("Search", mock.Anything, `ai "deteksi hama" -drone`, []string{ownID.Hex()}, 10, 0).Return([]*models.AchievementSearchHit{
+			{
+				MongoAchievement: models.MongoAchievement{
+					ID:          ownID,
+					Title:       "Juara 1 Lomba AI",
+					Description: desc,
+					Details:     models.AchievementDetails{CompetitionName: &competition},
+					Tags:        []string{"AI", "Pertanian"},
+				},
+				Score: 12.5,
+			},
+		}, 1, nil).Once()
+
+		req := httptest.NewRequest("GET", `/achievements/search?q=ai+%22deteksi+hama%22+-drone`, nil)
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-student", Role: "Mahasiswa"}).Test(req)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result searchResult
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, "verified", result.Data[0].Status)
+		assert.Equal(t, 12.5, result.Data[0].Score)
+
+		h := result.Data[0].Highlights
+		assert.Equal(t, "Juara 1 Lomba <mark>AI</mark>", h["title"])
+		assert.Equal(t, "Gemastik <mark>AI</mark> Challenge", h["competitionName"])
+		assert.Equal(t, []interface{}{"<mark>AI</mark>"}, h["tags"])
+
+		snippet := h["description"].(string)
+		assert.Contains(t, snippet, "<mark>deteksi hama</mark> berbasis <mark>AI</mark> &lt;v2&gt;")
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.Less(t, len(snippet), len(desc))
+		mongoMock.AssertExpectations(t)
+	})
+
+	t.Run("Admin - Unscoped, Ranked Order Kept", func(t *testing.T) {
+		mongoMock.On("Search", mock.Anything, "robot", []string(nil), 5, 5).Return([]*models.AchievementSearchHit{
+			{MongoAchievement: models.MongoAchievement{ID: otherID, Title: "Robot"}, Score: 9},
+			{MongoAchievement: models.MongoAchievement{ID: ownID, Title: "Lomba robot"}, Score: 4},
+		}, 7, nil).Once()
+		refMock.On("List", models.AchievementListFilter{MongoIDs: []string{otherID.Hex(), ownID.Hex()}}).Return([]*models.AchievementReference{
+			{MongoAchievementID: ownID.Hex(), StudentID: "student-1", Status: "verified", TeamRole: "owner"},
+			{MongoAchievementID: otherID.Hex(), StudentID: "student-2", Status: "submitted", TeamRole: "owner"},
+		}, 2, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-admin", Role: "Admin"}).Test(httptest.NewRequest("GET", "/achievements/search?q=robot&page=2&limit=5", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result searchResult
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Equal(t, 7, result.Total)
+		assert.Equal(t, otherID.Hex(), result.Data[0].ID)
+		assert.Equal(t, "submitted", result.Data[0].Status)
+		assert.Equal(t, "Lomba <mark>robot</mark>", result.Data[1].Highlights["title"])
+	})
+
+	t.Run("Advisor - Same Scope As Achievement List", func(t *testing.T) {
+		lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: "lecturer-1"}, nil).Once()
+		delegationMock.On("FindActiveByDelegate", "lecturer-1", mock.Anything).Return([]*models.VerificationDelegation{}, nil).Once()
+		refMock.On("List", models.AchievementListFilter{
+			AdvisorIDs: []string{"lecturer-1"},
+			Statuses:   []string{"submitted"},
+			StageRoles: []string{"advisor"},
+		}).Return([]*models.AchievementReference{
+			{MongoAchievementID: otherID.Hex(), StudentID: "student-2", Status: "submitted"},
+		}, 1, nil).Once()
+		mongoMock.On("Search", mock.Anything, "robot", []string{otherID.Hex()}, 10, 0).Return([]*models.AchievementSearchHit{}, 0, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"}).Test(httptest.NewRequest("GET", "/achievements/search?q=robot", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		refMock.AssertExpectations(t)
+		mongoMock.AssertExpectations(t)
+	})
+
+	t.Run("Limit Above Max - Clamped", func(t *testing.T) {
+		mongoMock.On("Search", mock.Anything, "robot", []string(nil), services.MaxSearchPageSize, 0).Return([]*models.AchievementSearchHit{}, 0, nil).Once()
+
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-admin", Role: "Admin"}).Test(httptest.NewRequest("GET", "/achievements/search?q=robot&limit=80", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Limit int `json:"limit"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Equal(t, services.MaxSearchPageSize, result.Limit)
+		mongoMock.AssertExpectations(t)
+	})
+
+	t.Run("Query Too Short", func(t *testing.T) {
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-admin", Role: "Admin"}).Test(httptest.NewRequest("GET", "/achievements/search?q=a", nil))
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("Other Role Forbidden", func(t *testing.T) {
+		resp, _ := newApp(&models.JWTClaims{UserID: "user-x", Role: "Admin Fakultas"}).Test(httptest.NewRequest("GET", "/achievements/search?q=robot", nil))
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
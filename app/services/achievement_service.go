@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
-	"strconv"
+	"database/sql"
+	"sort"
 	"time"
 	"strings"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"fmt"
+	"log"
 	"uas/app/models"
 	"uas/app/repositories"
 )
@@ -17,6 +19,17 @@ type AchievementService struct {
 	RefRepo      repositories.IAchievementReferenceRepo
 	StudentRepo  repositories.IStudentRepository
 	LecturerRepo repositories.ILecturerRepository
+	HistoryRepo  repositories.IAchievementHistoryRepository
+	RubricRepo   repositories.IRubricRepository
+	TypeRepo     repositories.IAchievementTypeRepository
+
+	DelegationRepo repositories.IVerificationDelegationRepository
+	ChainRepo      repositories.IApprovalChainRepository
+	ApprovalRepo   repositories.IAchievementApprovalRepository
+	RevisionRepo   repositories.IAchievementRevisionRepository
+	PolicyRepo     repositories.ITeamPointPolicyRepository
+	TagRepo        repositories.ITagRepository
+	OutboxRepo     repositories.IAchievementOutboxRepository
 }
 
 func NewAchievementService(
@@ -24,18 +37,41 @@ func NewAchievementService(
 	ref repositories.IAchievementReferenceRepo,
 	student repositories.IStudentRepository,
 	lecturer repositories.ILecturerRepository,
+	history repositories.IAchievementHistoryRepository,
+	rubric repositories.IRubricRepository,
+	types repositories.IAchievementTypeRepository,
+	delegation repositories.IVerificationDelegationRepository,
+	chain repositories.IApprovalChainRepository,
+	approval repositories.IAchievementApprovalRepository,
+	revision repositories.IAchievementRevisionRepository,
+	policy repositories.ITeamPointPolicyRepository,
+	tags repositories.ITagRepository,
+	outbox repositories.IAchievementOutboxRepository,
 ) *AchievementService {
 	return &AchievementService{
 		MongoRepo:    mongo,
 		RefRepo:      ref,
 		StudentRepo:  student,
 		LecturerRepo: lecturer,
+		HistoryRepo:  history,
+		RubricRepo:   rubric,
+		TypeRepo:     types,
+
+		DelegationRepo: delegation,
+		ChainRepo:      chain,
+		ApprovalRepo:   approval,
+		RevisionRepo:   revision,
+		PolicyRepo:     policy,
+		TagRepo:        tags,
+		OutboxRepo:     outbox,
 	}
 }
 
 // CreateAchievement godoc
 // @Summary Create new achievement
-// @Description Mahasiswa menambahkan prestasi baru
+// @Description Mahasiswa menambahkan prestasi baru. Prestasi miliknya yang mirip dikembalikan sebagai possible_duplicates;
+// @Description prestasi mirip milik mahasiswa lain hanya dihitung pada possible_teammate_entries.
+// @Description members berisi anggota prestasi tim; pembuat entri otomatis menjadi anggota.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -43,6 +79,7 @@ func NewAchievementService(
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements [post]
@@ -63,70 +100,130 @@ func (s *AchievementService) CreateAchievement() fiber.Handler {
 			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
 
+		errs, err := s.validateAchievement(&payload)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		vocab, err := loadTagVocabulary(s.TagRepo)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		payload.Tags = vocab.Normalize(payload.Tags)
+
+		members, memberErrs := s.teamMembers(payload.Members, student.ID)
+		errs = append(errs, memberErrs...)
+		if len(errs) > 0 {
+			return validationFailed(c, errs)
+		}
+
 		now := time.Now()
 		payload.StudentID = student.ID
+		payload.Members = members
 		payload.Attachments = []models.AchievementAttachment{}
 		payload.Points = 0
 		payload.CreatedAt = now
 		payload.UpdatedAt = now
 
-		mongoID, err := s.MongoRepo.Insert(context.Background(), &payload)
+		refs := []*models.AchievementReference{{
+			ID:        uuid.New().String(),
+			StudentID: student.ID,
+			Status:    models.AchievementStatusDraft,
+			TeamRole:  models.TeamRoleOwner,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}}
+		refs = append(refs, memberRefs(members, student.ID, now)...)
+
+		mongoID, err := s.insertAchievement(context.Background(), &payload, refs)
 		if err != nil {
-			fmt.Println("Error Mongo Insert:", err)
+			log.Println("Error insert achievement:", err)
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
+		ref := refs[0]
 
-		ref := &models.AchievementReference{
-			ID:                 uuid.New().String(),
-			StudentID:          student.ID,
-			MongoAchievementID: mongoID,
-			Status:             "draft",
-			CreatedAt:          now,
-			UpdatedAt:          now,
+		for _, r := range refs {
+			if err := s.recordTransition(user, r, "", models.AchievementStatusDraft, nil); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
 		}
 
-		if _, err := s.RefRepo.Create(ref); err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		revisionErr := s.recordRevision(context.Background(), user, ref, nil)
+
+		duplicates, err := s.findDuplicates(context.Background(), &payload, student.ID, mongoID)
+		if err != nil {
+			log.Println("duplicate check:", err)
 		}
+		duplicates, teammateEntries := duplicatesForUser(user, duplicates)
 
-		return c.Status(201).JSON(fiber.Map{
-			"id":     mongoID,
-			"title":  payload.Title,
-			"status": "draft",
-		})
+		return c.Status(201).JSON(withRevisionError(fiber.Map{
+			"id":      mongoID,
+			"title":   payload.Title,
+			"status":  models.AchievementStatusDraft,
+			"members": members,
+
+			"possible_duplicates":       duplicates,
+			"possible_teammate_entries": teammateEntries,
+		}, revisionErr))
 	}
 }
 
 // ListAchievements godoc
 // @Summary List achievements
-// @Description Melihat daftar prestasi berdasarkan role (mahasiswa, dosen, admin)
+// @Description Melihat daftar prestasi berdasarkan role (mahasiswa, dosen, admin).
+// @Description Reviewer hanya melihat prestasi yang menunggu di tahap persetujuannya.
+// @Description Filter, urutan, dan halaman diproses di database; respons memuat total dan total_pages.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
-// @Param limit query int false "Limit per page"
-// @Success 200 {array} map[string]interface{}
+// @Param limit query int false "Limit per page (default 10, max 100)"
+// @Param status query string false "Status, dipisah koma (draft,submitted,verified,rejected,needs_revision)"
+// @Param type query string false "Achievement type"
+// @Param student_id query string false "Student ID"
+// @Param program query string false "Program study"
+// @Param from query string false "Created from (YYYY-MM-DD atau RFC3339)"
+// @Param to query string false "Created to (YYYY-MM-DD atau RFC3339)"
+// @Param sort query string false "created_at, updated_at, submitted_at, status, points (default created_at)"
+// @Param order query string false "asc atau desc (default desc)"
+// @Param awaiting query bool false "Admin: only items waiting at the admin stage or escalated by SLA"
+// @Param tag query string false "Tag (nama atau alias)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements [get]
 func (s *AchievementService) ListAchievements() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
-		page, _ := strconv.Atoi(c.Query("page", "1"))
-		limit, _ := strconv.Atoi(c.Query("limit", "10"))
-
-		if page < 1 {
-			page = 1
-		}
-		if limit < 1 {
-			limit = 10
-		}
-
-		offset := (page - 1) * limit
 		user := c.Locals("user").(*models.JWTClaims)
 		ctx := context.Background()
+		chains, err := s.approvalChains()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
 
-		results := []fiber.Map{}
+		filter, page, limit, err := parseAchievementListFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		achievementType := strings.TrimSpace(c.Query("type"))
+		var tags []string
+		if tag := strings.TrimSpace(c.Query("tag")); tag != "" {
+			vocab, err := loadTagVocabulary(s.TagRepo)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			tags = vocab.Spellings(tag)
+		}
+		if achievementType != "" || len(tags) > 0 {
+			ids, err := s.MongoRepo.FilterIDs(ctx, achievementType, tags)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			filter.MongoIDs = ids
+		}
 
 		switch strings.ToLower(user.Role) {
 
@@ -135,19 +232,7 @@ func (s *AchievementService) ListAchievements() fiber.Handler {
 			if err != nil {
 				return c.Status(404).JSON(fiber.Map{"error": "student profile not found"})
 			}
-
-			refs, _ := s.RefRepo.GetByStudentID(student.ID)
-			for _, ref := range refs {
-				doc, err := s.MongoRepo.FindByID(ctx, ref.MongoAchievementID)
-				if err != nil {
-					continue
-				}
-				results = append(results, fiber.Map{
-					"id":     ref.MongoAchievementID,
-					"title":  doc.Title,
-					"status": ref.Status,
-				})
-			}
+			filter.StudentIDs = []string{student.ID}
 
 		case "dosen wali", "dosen_wali":
 			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
@@ -155,62 +240,91 @@ func (s *AchievementService) ListAchievements() fiber.Handler {
 				return c.Status(404).JSON(fiber.Map{"error": "lecturer profile not found"})
 			}
 
-			students, _ := s.StudentRepo.FindByAdvisorID(lecturer.ID)
-			for _, student := range students {
-				refs, _ := s.RefRepo.GetByStudentID(student.ID)
-				for _, ref := range refs {
-					if ref.Status != "submitted" {
-						continue
-					}
-					doc, err := s.MongoRepo.FindByID(ctx, ref.MongoAchievementID)
-					if err != nil {
-						continue
-					}
-					results = append(results, fiber.Map{
-						"id":     ref.MongoAchievementID,
-						"title":  doc.Title,
-						"status": ref.Status,
-					})
-				}
+			// Cakupan dibatasi lewat advisor_id di query yang sama, bukan
+			// dengan membaca mahasiswa bimbingan satu per satu.
+			delegated, err := s.delegatedAdvisors(lecturer.ID)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 			}
+			filter.AdvisorIDs = append([]string{lecturer.ID}, delegated...)
+			restrictStudents(&filter, c.Query("student_id"))
+			restrictToStage(&filter, models.ApprovalRoleAdvisor)
 
 		case "admin":
-			refs, _ := s.RefRepo.GetAll()
-			for _, ref := range refs {
-				doc, err := s.MongoRepo.FindByID(ctx, ref.MongoAchievementID)
-				if err != nil {
-					continue
-				}
-				results = append(results, fiber.Map{
-					"id":        ref.MongoAchievementID,
-					"title":     doc.Title,
-					"status":    ref.Status,
-					"studentId": ref.StudentID,
-				})
+			restrictStudents(&filter, c.Query("student_id"))
+			if c.QueryBool("awaiting", false) {
+				restrictToStage(&filter, user.Role)
+				// Prestasi yang dieskalasi SLA dialihkan ke antrean admin
+				// di tahap mana pun.
+				filter.IncludeEscalated = true
 			}
+
+		default:
+			// Reviewer tahap lain (mis. admin fakultas) melihat prestasi
+			// yang sedang menunggu di tahap dengan role-nya.
+			restrictStudents(&filter, c.Query("student_id"))
+			// Tahap dosen wali hanya untuk dosen wali mahasiswanya.
+			if strings.EqualFold(user.Role, models.ApprovalRoleAdvisor) {
+				restrictToStage(&filter)
+			} else {
+				restrictToStage(&filter, user.Role)
+			}
+		}
+
+		refs, total, err := s.RefRepo.List(filter)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		end := offset + limit
-		if offset > len(results) {
-			results = []fiber.Map{}
-		} else if end > len(results) {
-			results = results[offset:]
-		} else {
-			results = results[offset:end]
+		docs, err := s.docsByID(ctx, refs)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		results := []fiber.Map{}
+		for _, ref := range refs {
+			item := fiber.Map{
+				"id":        ref.MongoAchievementID,
+				"status":    ref.Status,
+				"studentId": ref.StudentID,
+				"createdAt": ref.CreatedAt,
+			}
+			// Referensi tanpa dokumen tetap dikirim agar isi halaman sesuai
+			// dengan total; rekonsiliasi yang memperbaikinya.
+			doc, ok := docs[ref.MongoAchievementID]
+			if !ok {
+				item["missingDocument"] = true
+				results = append(results, item)
+				continue
+			}
+			item["title"] = doc.Title
+			item["type"] = doc.AchievementType
+			if ref.Status == models.AchievementStatusSubmitted {
+				_, stage := currentStage(ResolveApprovalChain(chains, doc), ref)
+				item["stage"] = stage.Name
+			}
+			results = append(results, item)
 		}
 
-		return c.JSON(results)
+		return c.JSON(fiber.Map{
+			"data":        results,
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + limit - 1) / limit,
+		})
 	}
 }
 
 // GetAchievementByID godoc
 // @Summary Get achievement by ID
-// @Description Melihat detail prestasi tertentu
+// @Description Melihat detail prestasi tertentu. Reviewer juga melihat possible_duplicates.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id} [get]
@@ -218,19 +332,25 @@ func (s *AchievementService) GetAchievementByID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
 		ctx := context.Background()
 
-		ref, err := s.RefRepo.GetByMongoID(id)
+		owner, err := s.RefRepo.GetByMongoID(id)
 		if err != nil {
 			return c.Status(404).JSON(fiber.Map{"error": "not found"})
 		}
 
+		ref, err := s.viewableRef(user, owner)
+		if err != nil {
+			return accessDenied(c, err)
+		}
+
 		doc, err := s.MongoRepo.FindByID(ctx, id)
 		if err != nil {
 			return c.Status(404).JSON(fiber.Map{"error": "not found"})
 		}
 
-		return c.JSON(fiber.Map{
+		result := fiber.Map{
 			"id":          id,
 			"title":       doc.Title,
 			"description": doc.Description,
@@ -238,7 +358,26 @@ func (s *AchievementService) GetAchievementByID() fiber.Handler {
 			"attachments": doc.Attachments,
 			"points":      doc.Points,
 			"status":      ref.Status,
-		})
+			"members":     doc.Members,
+
+			"revision_count":    ref.RevisionCount,
+			"revision_feedback": ref.RevisionFeedback,
+
+			"suggested_points":     ref.SuggestedPoints,
+			"points_justification": ref.PointsJustification,
+			"awarded_points":       ref.Points,
+		}
+
+		// Reviewer melihat prestasi lain yang kemungkinan duplikat.
+		if !isStudent(user) {
+			duplicates, err := s.findDuplicates(ctx, doc, ref.StudentID, id)
+			if err != nil {
+				log.Println("duplicate check:", err)
+			}
+			result["possible_duplicates"] = duplicates
+		}
+
+		return c.JSON(result)
 	}
 }
 
@@ -252,6 +391,10 @@ func (s *AchievementService) GetAchievementByID() fiber.Handler {
 // @Param achievement body models.MongoAchievement true "Achievement Data"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id} [put]
@@ -259,17 +402,62 @@ func (s *AchievementService) UpdateAchievement() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
 		var payload models.MongoAchievement
 
 		if err := c.BodyParser(&payload); err != nil {
 			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
 
+		owner, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		ref, team, err := s.actingRef(user, owner, IsEditable)
+		if err != nil {
+			return accessDenied(c, err)
+		}
+
+		if !IsEditable(ref.Status) {
+			return transitionConflict(c, &TransitionError{From: ref.Status, To: models.AchievementStatusDraft})
+		}
+
+		existing, err := s.MongoRepo.FindByID(context.Background(), id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		// Tipe prestasi tidak bisa diubah lewat update.
+		payload.AchievementType = existing.AchievementType
+		errs, err := s.validateAchievement(&payload)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if len(errs) > 0 {
+			return validationFailed(c, errs)
+		}
+
+		// Tag hanya diganti jika dikirim.
+		if payload.Tags != nil {
+			vocab, err := loadTagVocabulary(s.TagRepo)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			payload.Tags = vocab.Normalize(payload.Tags)
+		}
+
 		if err := s.MongoRepo.Update(context.Background(), id, &payload); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		return c.JSON(fiber.Map{"message": "updated"})
+		revisionErr := s.recordRevision(context.Background(), user, ref, existing)
+
+		if err := s.reopenRejected(user, team); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(withRevisionError(fiber.Map{"message": "updated"}, revisionErr))
 	}
 }
 
@@ -281,15 +469,58 @@ func (s *AchievementService) UpdateAchievement() fiber.Handler {
 // @Produce json
 // @Param id path string true "Achievement ID"
 // @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id} [delete]
 func (s *AchievementService) DeleteAchievement() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		ref, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
 
-		_ = s.MongoRepo.SoftDelete(context.Background(), id)
-		_ = s.RefRepo.SoftDeleteByMongoID(id)
+		if err := s.access().CanModify(user, ref.StudentID); err != nil {
+			return accessDenied(c, err)
+		}
+
+		// Dokumen dipakai bersama seluruh anggota tim, sehingga setiap
+		// anggota harus boleh dihapus; anggota yang sudah diverifikasi atau
+		// sedang ditinjau menahan penghapusan.
+		team := []*models.AchievementReference{ref}
+		if ref.TeamSize > 1 {
+			team, err = s.RefRepo.GetTeamByMongoID(id)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+		for _, member := range team {
+			if err := CheckTransition(member.Status, models.AchievementStatusDeleted); err != nil {
+				return transitionConflict(c, err)
+			}
+		}
+
+		outboxID, err := s.RefRepo.SoftDeleteWithOutbox(id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(409).JSON(fiber.Map{"error": "achievement can no longer be deleted"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		s.applyOutbox(outboxID, func() error {
+			return s.MongoRepo.SoftDelete(context.Background(), id)
+		})
+
+		for _, member := range team {
+			if err := s.recordTransition(user, member, member.Status, models.AchievementStatusDeleted, nil); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
 
 		return c.JSON(fiber.Map{"message": "deleted"})
 	}
@@ -298,12 +529,19 @@ func (s *AchievementService) DeleteAchievement() fiber.Handler {
 
 // SubmitAchievement godoc
 // @Summary Submit achievement
-// @Description Mahasiswa mengirim prestasi untuk diverifikasi
+// @Description Mahasiswa mengirim prestasi untuk diverifikasi. Poin usulan dihitung dari rubrik.
+// @Description Prestasi yang mirip dikembalikan sebagai peringatan possible_duplicates; untuk mahasiswa, prestasi
+// @Description mirip milik mahasiswa lain hanya dihitung pada possible_teammate_entries.
+// @Description Pada prestasi tim, anggota yang statusnya boleh dikirim ikut dikirim; anggota yang ditolak
+// @Description atau sudah diverifikasi tidak berubah.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id}/submit [post]
@@ -311,26 +549,90 @@ func (s *AchievementService) SubmitAchievement() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
 		now := time.Now()
 
-		if err := s.RefRepo.UpdateStatusByMongoID(id, "submitted", &now); err != nil {
+		owner, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		ref, team, err := s.actingRef(user, owner, canSubmit)
+		if err != nil {
+			return accessDenied(c, err)
+		}
+
+		if err := CheckTransition(ref.Status, models.AchievementStatusSubmitted); err != nil {
+			return transitionConflict(c, err)
+		}
+
+		doc, err := s.MongoRepo.FindByID(context.Background(), id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		score, err := s.scoreDocument(doc)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "failed to compute points: " + err.Error()})
+		}
+
+		// Tipe dan tingkat disalin ke referensi agar antrean reviewer bisa
+		// difilter per tahap di SQL.
+		if err := s.RefRepo.SetApprovalKeyByMongoID(id, doc.AchievementType, approvalLevel(doc)); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		return c.JSON(fiber.Map{"message": "submitted"})
+		// Setiap anggota berpindah sesuai statusnya sendiri; anggota yang
+		// ditolak harus kembali ke draft lewat perubahan lebih dulu.
+		for _, member := range team {
+			if !canSubmit(member.Status) {
+				continue
+			}
+			if err := s.RefRepo.UpdateStatusByMongoID(id, member.StudentID, models.AchievementStatusSubmitted, &now); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			if err := s.RefRepo.UpdateScoringByMongoID(id, member.StudentID, score.Points, nil); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			if err := s.recordTransition(user, member, member.Status, models.AchievementStatusSubmitted, nil); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		duplicates, err := s.findDuplicates(context.Background(), doc, ref.StudentID, id)
+		if err != nil {
+			log.Println("duplicate check:", err)
+		}
+		duplicates, teammateEntries := duplicatesForUser(user, duplicates)
+
+		return c.JSON(fiber.Map{
+			"message":          "submitted",
+			"suggested_points": score.Points,
+			"rubric_matches":   score.Matches,
+
+			"possible_duplicates":       duplicates,
+			"possible_teammate_entries": teammateEntries,
+		})
 	}
 }
 
 // VerifyAchievement godoc
 // @Summary Verify achievement
-// @Description Dosen/admin memverifikasi prestasi
+// @Description Reviewer tahap saat ini menandatangani prestasi. Prestasi baru verified setelah
+// @Description semua tahap rantai persetujuan selesai. Poin final dihitung dari rubrik pada
+// @Description tahap terakhir; override poin wajib disertai justifikasi.
+// @Description Prestasi tim ditinjau per anggota oleh dosen walinya masing-masing; student_id memilih anggota
+// @Description jika reviewer dapat meninjau lebih dari satu anggota.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
-// @Param points body object true "Points to assign" 
-// @Success 200 {object} map[string]string
+// @Param points body object false "Optional override {\"student_id\": \"...\", \"points\": 0, \"justification\": \"...\"}"
+// @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id}/verify [post]
@@ -339,7 +641,9 @@ func (s *AchievementService) VerifyAchievement() fiber.Handler {
         id := c.Params("id")
 
         var payload struct {
-            Points int `json:"points"`
+            StudentID     string `json:"student_id"`
+            Points        *int   `json:"points"`
+            Justification string `json:"justification"`
         }
         if err := c.BodyParser(&payload); err != nil {
             return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON format"})
@@ -347,25 +651,36 @@ func (s *AchievementService) VerifyAchievement() fiber.Handler {
 
         user := c.Locals("user").(*models.JWTClaims)
 
-        if err := s.MongoRepo.UpdatePoints(context.Background(), id, payload.Points); err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "failed to update points: " + err.Error()})
+        result, err := s.verify(user, id, payload.StudentID, payload.Points, payload.Justification)
+        if err != nil {
+            return reviewFailed(c, err)
         }
 
-        if err := s.RefRepo.VerifyByMongoID(id, user.UserID, time.Now()); err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "failed to verify: " + err.Error()})
+        if result.NextStage != "" {
+            return c.JSON(fiber.Map{
+                "status":         result.Status,
+                "id":             id,
+                "approved_stage": result.ApprovedStage,
+                "next_stage":     result.NextStage,
+                "student_id":     result.StudentID,
+            })
         }
 
         return c.JSON(fiber.Map{
-            "status": "verified",
-            "id":     id,
-            "points": payload.Points,
+            "status":          "verified",
+            "id":              id,
+            "student_id":      result.StudentID,
+            "points":          result.Points,
+            "computed_points": result.ComputedPoints,
+            "awarded_points":  result.AwardedPoints,
+            "overridden":      result.Overridden,
         })
     }
 }
 
 // RejectAchievement godoc
 // @Summary Reject achievement
-// @Description Dosen/admin menolak prestasi
+// @Description Dosen wali mahasiswa pemilik prestasi (atau admin) menolak prestasi
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -373,14 +688,19 @@ func (s *AchievementService) VerifyAchievement() fiber.Handler {
 // @Param rejection_note body object true "Reason for rejection"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id}/reject [post]
 func (s *AchievementService) RejectAchievement() fiber.Handler {
     return func(c *fiber.Ctx) error {
 
         id := c.Params("id")
+        user := c.Locals("user").(*models.JWTClaims)
 
         var payload struct {
+            StudentID     string `json:"student_id"`
             RejectionNote string `json:"rejection_note"`
         }
 
@@ -388,23 +708,100 @@ func (s *AchievementService) RejectAchievement() fiber.Handler {
             return c.Status(400).JSON(fiber.Map{"error": err.Error()})
         }
 
-        if err := s.RefRepo.RejectByMongoID(id, payload.RejectionNote); err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "failed to reject: " + err.Error()})
+        if err := s.reject(user, id, payload.StudentID, payload.RejectionNote); err != nil {
+            return reviewFailed(c, err)
         }
-        // ------------------------------------------------
 
         return c.JSON(fiber.Map{"status": "rejected"})
     }
 }
 
+// RequestRevision godoc
+// @Summary Request achievement revision
+// @Description Dosen wali (atau admin) mengembalikan prestasi ke mahasiswa untuk diperbaiki dengan catatan per field
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param feedback body object true "Field-level feedback {\"note\": \"...\", \"feedback\": [{\"field\": \"...\", \"comment\": \"...\"}]}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/request-revision [post]
+func (s *AchievementService) RequestRevision() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var payload struct {
+			StudentID string                  `json:"student_id"`
+			Note      string                  `json:"note"`
+			Feedback  models.RevisionFeedback `json:"feedback"`
+		}
+
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if len(payload.Feedback) == 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "feedback is required"})
+		}
+		for _, fb := range payload.Feedback {
+			if strings.TrimSpace(fb.Field) == "" || strings.TrimSpace(fb.Comment) == "" {
+				return c.Status(400).JSON(fiber.Map{"error": "each feedback item needs field and comment"})
+			}
+		}
+
+		ref, stages, err := s.reviewTarget(context.Background(), user, id, payload.StudentID)
+		if err != nil {
+			return reviewFailed(c, err)
+		}
+		_, stage := currentStage(stages, ref)
+
+		delegation, err := s.authorizeStage(user, ref, stage)
+		if err != nil {
+			return accessDenied(c, err)
+		}
+
+		if err := CheckTransition(ref.Status, models.AchievementStatusNeedsRevision); err != nil {
+			return transitionConflict(c, err)
+		}
+
+		if err := s.RefRepo.RequestRevisionByMongoID(id, ref.StudentID, payload.Feedback); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "failed to request revision: " + err.Error()})
+		}
+
+		var note *string
+		if payload.Note != "" {
+			note = &payload.Note
+		}
+		if err := s.recordReviewTransition(user, delegation, ref, ref.Status, models.AchievementStatusNeedsRevision, note); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{
+			"status":         models.AchievementStatusNeedsRevision,
+			"revision_count": ref.RevisionCount + 1,
+			"feedback":       payload.Feedback,
+		})
+	}
+}
+
 // GetAchievementHistory godoc
 // @Summary Get achievement history
-// @Description Melihat riwayat status prestasi
+// @Description Melihat riwayat perpindahan status prestasi (timeline)
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
-// @Success 200 {array} map[string]interface{}
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id}/history [get]
@@ -412,13 +809,41 @@ func (s *AchievementService) GetAchievementHistory() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
 
-		history, err := s.RefRepo.GetHistoryByMongoID(id)
+		ref, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		if _, err := s.viewableRef(user, ref); err != nil {
+			return accessDenied(c, err)
+		}
+
+		history, err := s.HistoryRepo.GetByMongoID(id)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		return c.JSON(history)
+		if history == nil {
+			history = []*models.AchievementStatusHistory{}
+		}
+
+		submitted, rejected := 0, 0
+		for _, h := range history {
+			switch h.ToStatus {
+			case models.AchievementStatusSubmitted:
+				submitted++
+			case models.AchievementStatusRejected:
+				rejected++
+			}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":            history,
+			"submitted_count": submitted,
+			"rejected_count":  rejected,
+		})
 	}
 }
 
@@ -433,6 +858,7 @@ func (s *AchievementService) GetAchievementHistory() fiber.Handler {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /achievements/{id}/attachments [post]
@@ -443,18 +869,23 @@ func (s *AchievementService) UploadAttachment() fiber.Handler {
 		user := c.Locals("user").(*models.JWTClaims)
 		ctx := context.Background()
 
-		student, err := s.StudentRepo.FindByUserID(user.UserID)
+		owner, err := s.RefRepo.GetByMongoID(id)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "student profile not found"})
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
 		}
 
-		ref, err := s.RefRepo.GetByMongoID(id)
+		ref, team, err := s.actingRef(user, owner, IsEditable)
 		if err != nil {
-			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+			return accessDenied(c, err)
 		}
 
-		if ref.StudentID != student.ID {
-			return c.Status(403).JSON(fiber.Map{"error": "forbidden"})
+		if !IsEditable(ref.Status) {
+			return transitionConflict(c, &TransitionError{From: ref.Status, To: models.AchievementStatusDraft})
+		}
+
+		existing, err := s.MongoRepo.FindByID(ctx, id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
 		}
 
 		form, err := c.MultipartForm()
@@ -489,10 +920,251 @@ func (s *AchievementService) UploadAttachment() fiber.Handler {
 			}
 		}
 
-		return c.JSON(fiber.Map{
+		revisionErr := s.recordRevision(ctx, user, ref, existing)
+
+		if err := s.reopenRejected(user, team); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(withRevisionError(fiber.Map{
 			"message":     "files uploaded",
 			"attachments": attachments,
-		})
+		}, revisionErr))
 	}
 }
 
+// verify menjalankan pemeriksaan dan penyimpanan verifikasi satu prestasi.
+// Dipakai oleh VerifyAchievement dan verifikasi massal. Pada prestasi tim,
+// studentID memilih anggota yang diverifikasi.
+func (s *AchievementService) verify(
+	user *models.JWTClaims,
+	id, studentID string,
+	override *int,
+	justification string,
+) (*models.VerificationResult, error) {
+
+	ctx := context.Background()
+
+	ref, stages, err := s.reviewTarget(ctx, user, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+	idx, stage := currentStage(stages, ref)
+
+	delegation, err := s.authorizeStage(user, ref, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckTransition(ref.Status, models.AchievementStatusVerified); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotYetApproved(user, ref); err != nil {
+		return nil, err
+	}
+
+	// Tahap antara hanya menandatangani; status tetap submitted.
+	if idx < len(stages)-1 {
+		if err := s.RefRepo.AdvanceApprovalStage(id, ref.StudentID, idx); err != nil {
+			return nil, fmt.Errorf("failed to approve: %w", err)
+		}
+		if err := s.recordApproval(user, delegation, ref, idx, stage); err != nil {
+			return nil, err
+		}
+		return &models.VerificationResult{
+			Status:        models.AchievementStatusSubmitted,
+			ApprovedStage: stage.Name,
+			NextStage:     stages[idx+1].Name,
+			StudentID:     ref.StudentID,
+		}, nil
+	}
+
+	doc, err := s.MongoRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errAchievementNotFound
+	}
+
+	score, err := s.scoreDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute points: %w", err)
+	}
+
+	result := &models.VerificationResult{
+		Status:         models.AchievementStatusVerified,
+		ApprovedStage:  stage.Name,
+		Points:         score.Points,
+		ComputedPoints: score.Points,
+		StudentID:      ref.StudentID,
+	}
+	var note *string
+	if override != nil && *override != score.Points {
+		j := strings.TrimSpace(justification)
+		if j == "" {
+			return nil, &OverrideError{ComputedPoints: score.Points}
+		}
+		result.Points = *override
+		result.Overridden = true
+		note = &j
+	}
+
+	policy, err := s.teamPolicy(doc.AchievementType)
+	if err != nil {
+		return nil, err
+	}
+	result.AwardedPoints = AwardPoints(policy, result.Points, ref.TeamSize)
+
+	// Poin dokumen mengikuti verifikasi pemilik entri sehingga tidak
+	// bergantung pada urutan verifikasi anggota; override untuk anggota
+	// lain hanya disimpan di referensinya.
+	if ref.TeamRole != models.TeamRoleMember {
+		if err := s.MongoRepo.UpdatePoints(ctx, id, result.Points); err != nil {
+			return nil, fmt.Errorf("failed to update points: %w", err)
+		}
+	}
+
+	if err := s.RefRepo.VerifyByMongoID(id, ref.StudentID, user.UserID, time.Now(), result.AwardedPoints); err != nil {
+		return nil, fmt.Errorf("failed to verify: %w", err)
+	}
+
+	if err := s.RefRepo.UpdateScoringByMongoID(id, ref.StudentID, score.Points, note); err != nil {
+		return nil, err
+	}
+
+	history := fmt.Sprintf("points: %d", result.Points)
+	if note != nil {
+		history = fmt.Sprintf("points: %d (computed %d, override: %s)", result.Points, score.Points, *note)
+	}
+	if result.AwardedPoints != result.Points {
+		history += fmt.Sprintf(", awarded %d (%s)", result.AwardedPoints, policy)
+	}
+	if err := s.recordReviewTransition(user, delegation, ref, ref.Status, models.AchievementStatusVerified, &history); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordApproval(user, delegation, ref, idx, stage); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// reject menjalankan pemeriksaan dan penyimpanan penolakan satu prestasi.
+// Pada prestasi tim hanya anggota yang dipilih yang ditolak.
+func (s *AchievementService) reject(user *models.JWTClaims, id, studentID, note string) error {
+	ref, stages, err := s.reviewTarget(context.Background(), user, id, studentID)
+	if err != nil {
+		return err
+	}
+	_, stage := currentStage(stages, ref)
+
+	delegation, err := s.authorizeStage(user, ref, stage)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckTransition(ref.Status, models.AchievementStatusRejected); err != nil {
+		return err
+	}
+
+	if err := s.RefRepo.RejectByMongoID(id, ref.StudentID, note); err != nil {
+		return fmt.Errorf("failed to reject: %w", err)
+	}
+
+	return s.recordReviewTransition(user, delegation, ref, ref.Status, models.AchievementStatusRejected, &note)
+}
+
+// recordTransition menambahkan satu baris ke riwayat status prestasi.
+// from kosong berarti prestasi baru dibuat.
+func (s *AchievementService) recordTransition(
+	user *models.JWTClaims,
+	ref *models.AchievementReference,
+	from, to string,
+	note *string,
+) error {
+	return s.recordReviewTransition(user, nil, ref, from, to, note)
+}
+
+// recordReviewTransition seperti recordTransition, tetapi jika reviewer
+// bertindak lewat delegasi, dosen wali asli dan delegasinya ikut dicatat.
+func (s *AchievementService) recordReviewTransition(
+	user *models.JWTClaims,
+	delegation *models.VerificationDelegation,
+	ref *models.AchievementReference,
+	from, to string,
+	note *string,
+) error {
+	entry := &models.AchievementStatusHistory{
+		AchievementRefID:   ref.ID,
+		MongoAchievementID: ref.MongoAchievementID,
+		ToStatus:           to,
+		ActorID:            user.UserID,
+		ActorRole:          user.Role,
+		Note:               note,
+	}
+	if from != "" {
+		entry.FromStatus = &from
+	}
+	if delegation != nil {
+		entry.OnBehalfOf = &delegation.AdvisorID
+		entry.DelegationID = &delegation.ID
+	}
+	return s.HistoryRepo.Create(entry)
+}
+
+// scoreAchievement menghitung poin prestasi berdasarkan rubrik untuk tipenya.
+func (s *AchievementService) scoreAchievement(ctx context.Context, mongoID string) (models.RubricScore, error) {
+	doc, err := s.MongoRepo.FindByID(ctx, mongoID)
+	if err != nil {
+		return models.RubricScore{}, err
+	}
+	return s.scoreDocument(doc)
+}
+
+func (s *AchievementService) scoreDocument(doc *models.MongoAchievement) (models.RubricScore, error) {
+	rules, err := s.RubricRepo.FindByType(doc.AchievementType)
+	if err != nil {
+		return models.RubricScore{}, err
+	}
+
+	if !isBuiltinAchievementType(doc.AchievementType) {
+		def, err := s.TypeRepo.FindByCode(doc.AchievementType)
+		if err != nil && err != sql.ErrNoRows {
+			return models.RubricScore{}, err
+		}
+		if def != nil {
+			rules = withTypeDefaults(rules, def)
+		}
+	}
+
+	return ScoreAchievement(rules, doc), nil
+}
+
+// validateAchievement memvalidasi tipe bawaan dengan spesifikasinya dan tipe
+// tambahan dengan definisi yang didaftarkan admin. Tipe yang tidak dikenal
+// ditolak.
+func (s *AchievementService) validateAchievement(doc *models.MongoAchievement) ([]FieldError, error) {
+	if isBuiltinAchievementType(doc.AchievementType) || strings.TrimSpace(doc.AchievementType) == "" {
+		return ValidateAchievement(doc), nil
+	}
+
+	def, err := s.TypeRepo.FindByCode(strings.TrimSpace(doc.AchievementType))
+	if err == sql.ErrNoRows {
+		defs, err := s.TypeRepo.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		types := knownAchievementTypes()
+		for _, d := range defs {
+			types = append(types, d.Code)
+		}
+		sort.Strings(types)
+		return []FieldError{{"achievementType", "must be one of: " + strings.Join(types, ", ")}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc.AchievementType = def.Code
+	return ValidateCustomAchievement(doc, def), nil
+}
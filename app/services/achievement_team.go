@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"uas/app/models"
+)
+
+// ErrTeamMemberRequired dikembalikan saat prestasi tim punya lebih dari satu
+// anggota yang bisa ditinjau user dan student_id tidak disebutkan.
+var ErrTeamMemberRequired = errors.New("student_id is required for team achievements")
+
+// AwardPoints menghitung poin yang diterima satu anggota tim. split membagi
+// rata poin prestasi (dibulatkan), duplicate memberi poin penuh ke setiap
+// anggota.
+func AwardPoints(policy string, points, teamSize int) int {
+	if teamSize <= 1 || policy != models.TeamPointPolicySplit {
+		return points
+	}
+	return int(math.Round(float64(points) / float64(teamSize)))
+}
+
+// teamPolicy mengembalikan kebijakan poin tim untuk tipe prestasi. Tipe
+// tanpa kebijakan memakai duplicate.
+func (s *AchievementService) teamPolicy(achievementType string) (string, error) {
+	if s.PolicyRepo == nil {
+		return models.TeamPointPolicyDuplicate, nil
+	}
+
+	policy, err := s.PolicyRepo.FindByType(achievementType)
+	if err == sql.ErrNoRows {
+		return models.TeamPointPolicyDuplicate, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return policy.Policy, nil
+}
+
+// reviewTarget memilih referensi anggota yang ditinjau. Prestasi tim punya
+// satu referensi per anggota dan setiap anggota ditinjau dosen walinya
+// sendiri; tanpa studentID dipilih satu-satunya anggota submitted yang
+// boleh ditinjau user.
+func (s *AchievementService) reviewTarget(
+	ctx context.Context,
+	user *models.JWTClaims,
+	id, studentID string,
+) (*models.AchievementReference, models.ApprovalStages, error) {
+
+	var (
+		ref *models.AchievementReference
+		err error
+	)
+	if studentID != "" {
+		ref, err = s.RefRepo.GetByMongoIDAndStudent(id, studentID)
+	} else {
+		ref, err = s.RefRepo.GetByMongoID(id)
+	}
+	if err != nil {
+		return nil, nil, errAchievementNotFound
+	}
+
+	stages, err := s.approvalStages(ctx, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if studentID != "" || ref.TeamSize <= 1 {
+		return ref, stages, nil
+	}
+
+	team, err := s.RefRepo.GetTeamByMongoID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var candidates []*models.AchievementReference
+	for _, member := range team {
+		if member.Status != models.AchievementStatusSubmitted {
+			continue
+		}
+		_, stage := currentStage(stages, member)
+		if _, err := s.authorizeStage(user, member, stage); err == nil {
+			candidates = append(candidates, member)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return ref, stages, nil
+	case 1:
+		return candidates[0], stages, nil
+	}
+	return nil, nil, ErrTeamMemberRequired
+}
+
+// viewableRef mengembalikan referensi yang membuat user boleh melihat
+// prestasi: milik pemilik entri, atau milik anggota tim yang terkait dengan
+// user.
+func (s *AchievementService) viewableRef(user *models.JWTClaims, ref *models.AchievementReference) (*models.AchievementReference, error) {
+	viewErr := s.access().CanView(user, ref.StudentID)
+	if viewErr == nil || ref.TeamSize <= 1 {
+		return ref, viewErr
+	}
+
+	team, err := s.RefRepo.GetTeamByMongoID(ref.MongoAchievementID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range team {
+		if member.StudentID == ref.StudentID {
+			continue
+		}
+		if s.access().CanView(user, member.StudentID) == nil {
+			return member, nil
+		}
+	}
+	return nil, viewErr
+}
+
+// actingRef mengembalikan referensi anggota yang menjadi dasar pemeriksaan
+// status saat user mengubah atau mengirim prestasi, beserta semua anggota
+// prestasi. Mahasiswa memakai referensinya sendiri; admin memakai pemilik
+// entri, atau anggota pertama yang statusnya memenuhi usable jika status
+// pemilik tidak memenuhi.
+func (s *AchievementService) actingRef(
+	user *models.JWTClaims,
+	owner *models.AchievementReference,
+	usable func(status string) bool,
+) (*models.AchievementReference, []*models.AchievementReference, error) {
+
+	team := []*models.AchievementReference{owner}
+	if owner.TeamSize > 1 {
+		var err error
+		team, err = s.RefRepo.GetTeamByMongoID(owner.MongoAchievementID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if isAdmin(user) {
+		for _, member := range team {
+			if usable(member.Status) {
+				return member, team, nil
+			}
+		}
+		return owner, team, nil
+	}
+
+	for _, member := range team {
+		err := s.access().CanModify(user, member.StudentID)
+		if err == nil {
+			return member, team, nil
+		}
+		if !errors.Is(err, ErrAchievementForbidden) {
+			return nil, nil, err
+		}
+	}
+	return nil, nil, ErrAchievementForbidden
+}
+
+// reopenRejected mengembalikan setiap anggota yang ditolak menjadi draft
+// setelah dokumen prestasi diubah, sesuai aturan rejected -> draft.
+func (s *AchievementService) reopenRejected(user *models.JWTClaims, team []*models.AchievementReference) error {
+	for _, member := range team {
+		if member.Status != models.AchievementStatusRejected {
+			continue
+		}
+		if err := CheckTransition(member.Status, models.AchievementStatusDraft); err != nil {
+			return err
+		}
+		if err := s.RefRepo.UpdateStatusByMongoID(member.MongoAchievementID, member.StudentID, models.AchievementStatusDraft, nil); err != nil {
+			return err
+		}
+		if err := s.recordTransition(user, member, member.Status, models.AchievementStatusDraft, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teamMembers menormalkan daftar anggota tim pada payload create. Pembuat
+// entri selalu menjadi anggota; daftar berisi satu anggota berarti
+// prestasi perorangan.
+func (s *AchievementService) teamMembers(members []models.TeamMember, ownerID string) ([]models.TeamMember, []FieldError) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	var (
+		out  []models.TeamMember
+		errs []FieldError
+	)
+	seen := map[string]bool{}
+	hasOwner := false
+
+	for _, m := range members {
+		m.StudentID = strings.TrimSpace(m.StudentID)
+		m.Role = strings.TrimSpace(m.Role)
+		if m.StudentID == "" {
+			errs = append(errs, FieldError{Field: "members", Message: "each member needs a studentId"})
+			continue
+		}
+		if seen[m.StudentID] {
+			errs = append(errs, FieldError{Field: "members", Message: "duplicate member " + m.StudentID})
+			continue
+		}
+		seen[m.StudentID] = true
+
+		if m.StudentID == ownerID {
+			hasOwner = true
+		} else if _, err := s.StudentRepo.FindByID(m.StudentID); err != nil {
+			errs = append(errs, FieldError{Field: "members", Message: "student " + m.StudentID + " not found"})
+			continue
+		}
+		out = append(out, m)
+	}
+
+	if !hasOwner {
+		out = append([]models.TeamMember{{StudentID: ownerID}}, out...)
+	}
+	if len(out) <= 1 {
+		return nil, errs
+	}
+	return out, errs
+}
+
+// memberRefs menyiapkan referensi draft untuk anggota tim selain pemilik
+// entri.
+func memberRefs(members []models.TeamMember, ownerID string, now time.Time) []*models.AchievementReference {
+	var refs []*models.AchievementReference
+	for _, m := range members {
+		if m.StudentID == ownerID {
+			continue
+		}
+		refs = append(refs, &models.AchievementReference{
+			ID:        uuid.New().String(),
+			StudentID: m.StudentID,
+			Status:    models.AchievementStatusDraft,
+			TeamRole:  models.TeamRoleMember,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+	return refs
+}
@@ -0,0 +1,230 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAwardPoints(t *testing.T) {
+	assert.Equal(t, 100, services.AwardPoints(models.TeamPointPolicyDuplicate, 100, 3))
+	assert.Equal(t, 33, services.AwardPoints(models.TeamPointPolicySplit, 100, 3))
+	assert.Equal(t, 50, services.AwardPoints(models.TeamPointPolicySplit, 100, 2))
+	assert.Equal(t, 100, services.AwardPoints(models.TeamPointPolicySplit, 100, 1))
+	assert.Equal(t, 100, services.AwardPoints(models.TeamPointPolicySplit, 100, 0))
+}
+
+func TestTeamAchievementVerification(t *testing.T) {
+	refMock := new(mocks.AchievementRefMock)
+	mongoMock := new(mocks.AchievementMongoMock)
+	rubricMock := new(mocks.RubricRepoMock)
+	studentMock := new(mocks.StudentRepoMock)
+	lecturerMock := new(mocks.LecturerRepoMock)
+	historyMock := new(mocks.AchievementHistoryMock)
+	policyMock := new(mocks.TeamPointPolicyRepoMock)
+	service := &services.AchievementService{
+		MongoRepo:    mongoMock,
+		RefRepo:      refMock,
+		StudentRepo:  studentMock,
+		LecturerRepo: lecturerMock,
+		HistoryRepo:  historyMock,
+		RubricRepo:   rubricMock,
+		PolicyRepo:   policyMock,
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: c.Get("X-User"), Role: c.Get("X-Role", "Dosen Wali")})
+		return c.Next()
+	})
+	app.Post("/achievement/:id/verify", service.VerifyAchievement())
+	app.Post("/achievement/:id/request-revision", service.RequestRevision())
+
+	advisor1, advisor2 := "lecturer-1", "lecturer-2"
+	lecturerMock.On("FindByUserID", "user-advisor-1").Return(&models.Lecturer{ID: advisor1}, nil)
+	lecturerMock.On("FindByUserID", "user-advisor-2").Return(&models.Lecturer{ID: advisor2}, nil)
+	studentMock.On("FindByID", "student-1").Return(&models.Student{ID: "student-1", AdvisorID: &advisor1}, nil)
+	studentMock.On("FindByID", "student-2").Return(&models.Student{ID: "student-2", AdvisorID: &advisor2}, nil)
+	historyMock.On("Create", mock.Anything).Return(nil)
+	policyMock.On("FindByType", "competition").Return(&models.TeamPointPolicy{
+		AchievementType: "competition",
+		Policy:          models.TeamPointPolicySplit,
+	}, nil)
+	stubRubric(mongoMock, rubricMock, refMock)
+
+	id := "team-1"
+	owner := &models.AchievementReference{ID: "ref-1", StudentID: "student-1", MongoAchievementID: id, Status: "submitted", TeamRole: models.TeamRoleOwner, TeamSize: 2}
+	member := &models.AchievementReference{ID: "ref-2", StudentID: "student-2", MongoAchievementID: id, Status: "submitted", TeamRole: models.TeamRoleMember, TeamSize: 2}
+	refMock.On("GetByMongoID", id).Return(owner, nil)
+	refMock.On("GetTeamByMongoID", id).Return([]*models.AchievementReference{owner, member}, nil)
+	mongoMock.On("UpdatePoints", mock.Anything, id, 100).Return(nil)
+
+	verify := func(userID, role string, payload interface{}) (int, map[string]interface{}) {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", "/achievement/"+id+"/verify", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", userID)
+		req.Header.Set("X-Role", role)
+		resp, _ := app.Test(req)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	t.Run("Member Advisor Verifies Own Advisee - Split Points", func(t *testing.T) {
+		refMock.On("VerifyByMongoID", id, "student-2", "user-advisor-2", mock.Anything, 50).Return(nil).Once()
+
+		code, result := verify("user-advisor-2", "Dosen Wali", map[string]interface{}{})
+		assert.Equal(t, 200, code)
+		assert.Equal(t, float64(100), result["points"])
+		assert.Equal(t, float64(50), result["awarded_points"])
+		assert.Equal(t, "student-2", result["student_id"])
+		refMock.AssertNotCalled(t, "VerifyByMongoID", id, "student-1", mock.Anything, mock.Anything, mock.Anything)
+		refMock.AssertCalled(t, "UpdateScoringByMongoID", id, "student-2", 100, (*string)(nil))
+		refMock.AssertNotCalled(t, "UpdateScoringByMongoID", id, "student-1", mock.Anything, mock.Anything)
+		mongoMock.AssertNotCalled(t, "UpdatePoints", mock.Anything, id, mock.Anything)
+	})
+
+	t.Run("Admin Without Student ID - Ambiguous", func(t *testing.T) {
+		code, _ := verify("admin-123", "Admin", map[string]interface{}{})
+		assert.Equal(t, 400, code)
+	})
+
+	t.Run("Admin With Student ID", func(t *testing.T) {
+		refMock.On("GetByMongoIDAndStudent", id, "student-1").Return(owner, nil).Once()
+		refMock.On("VerifyByMongoID", id, "student-1", "admin-123", mock.Anything, 50).Return(nil).Once()
+
+		code, result := verify("admin-123", "Admin", map[string]interface{}{"student_id": "student-1"})
+		assert.Equal(t, 200, code)
+		assert.Equal(t, "student-1", result["student_id"])
+		mongoMock.AssertCalled(t, "UpdatePoints", mock.Anything, id, 100)
+	})
+
+	t.Run("Member Advisor Requests Revision - Own Advisee Only", func(t *testing.T) {
+		feedback := models.RevisionFeedback{{Field: "attachments", Comment: "lampirkan sertifikat anggota"}}
+		refMock.On("RequestRevisionByMongoID", id, "student-2", feedback).Return(nil).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{"feedback": feedback})
+		req := httptest.NewRequest("POST", "/achievement/"+id+"/request-revision", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "user-advisor-2")
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, 200, resp.StatusCode)
+		refMock.AssertNotCalled(t, "RequestRevisionByMongoID", id, "student-1", mock.Anything)
+	})
+}
+
+func TestTeamAchievementLifecycle(t *testing.T) {
+	refMock := new(mocks.AchievementRefMock)
+	mongoMock := new(mocks.AchievementMongoMock)
+	rubricMock := new(mocks.RubricRepoMock)
+	studentMock := new(mocks.StudentRepoMock)
+	historyMock := new(mocks.AchievementHistoryMock)
+	service := &services.AchievementService{
+		MongoRepo:   mongoMock,
+		RefRepo:     refMock,
+		StudentRepo: studentMock,
+		HistoryRepo: historyMock,
+		RubricRepo:  rubricMock,
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: c.Get("X-User"), Role: "Mahasiswa"})
+		return c.Next()
+	})
+	app.Put("/achievement/:id", service.UpdateAchievement())
+	app.Post("/achievement/:id/submit", service.SubmitAchievement())
+	app.Delete("/achievement/:id", service.DeleteAchievement())
+
+	studentMock.On("FindByUserID", "user-owner").Return(&models.Student{ID: "student-1"}, nil)
+	studentMock.On("FindByUserID", "user-member").Return(&models.Student{ID: "student-2"}, nil)
+	mongoMock.On("FindByID", mock.Anything, mock.Anything).Return(&models.MongoAchievement{AchievementType: "other", Title: "Lomba"}, nil)
+	mongoMock.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mongoMock.On("FindDuplicateCandidates", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*models.MongoAchievement{}, nil)
+	rubricMock.On("FindByType", "other").Return([]*models.RubricRule{}, nil)
+	refMock.On("UpdateScoringByMongoID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	historyMock.On("Create", mock.Anything).Return(nil)
+
+	team := func(id string, statuses ...string) *models.AchievementReference {
+		var refs []*models.AchievementReference
+		for i, status := range statuses {
+			role := models.TeamRoleMember
+			if i == 0 {
+				role = models.TeamRoleOwner
+			}
+			refs = append(refs, &models.AchievementReference{
+				ID:                 fmt.Sprintf("%s-ref-%d", id, i+1),
+				StudentID:          fmt.Sprintf("student-%d", i+1),
+				MongoAchievementID: id,
+				Status:             status,
+				TeamRole:           role,
+				TeamSize:           len(statuses),
+			})
+		}
+		refMock.On("GetByMongoID", id).Return(refs[0], nil)
+		refMock.On("GetTeamByMongoID", id).Return(refs, nil)
+		return refs[0]
+	}
+
+	send := func(method, path, userID string) int {
+		body, _ := json.Marshal(map[string]string{"title": "Lomba (revisi)"})
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", userID)
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+
+	t.Run("Rejected Member Edits After Owner Verified", func(t *testing.T) {
+		id := "team-edit"
+		team(id, "verified", "rejected")
+		refMock.On("UpdateStatusByMongoID", id, "student-2", "draft", (*time.Time)(nil)).Return(nil).Once()
+
+		assert.Equal(t, 200, send("PUT", "/achievement/"+id, "user-member"))
+		refMock.AssertNotCalled(t, "UpdateStatusByMongoID", id, "student-1", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Verified Owner Cannot Edit", func(t *testing.T) {
+		id := "team-verified"
+		team(id, "verified", "needs_revision")
+		assert.Equal(t, 409, send("PUT", "/achievement/"+id, "user-owner"))
+	})
+
+	t.Run("Submit Skips Rejected And Verified Members", func(t *testing.T) {
+		id := "team-submit"
+		team(id, "verified", "draft", "rejected")
+		refMock.On("SetApprovalKeyByMongoID", id, mock.Anything, "").Return(nil).Once()
+		refMock.On("UpdateStatusByMongoID", id, "student-2", "submitted", mock.Anything).Return(nil).Once()
+
+		assert.Equal(t, 200, send("POST", "/achievement/"+id+"/submit", "user-member"))
+		refMock.AssertNotCalled(t, "UpdateStatusByMongoID", id, "student-1", mock.Anything, mock.Anything)
+		refMock.AssertNotCalled(t, "UpdateStatusByMongoID", id, "student-3", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Delete Refused While Member Verified", func(t *testing.T) {
+		id := "team-delete"
+		team(id, "rejected", "verified")
+		assert.Equal(t, 409, send("DELETE", "/achievement/"+id, "user-owner"))
+		refMock.AssertNotCalled(t, "SoftDeleteWithOutbox", id)
+	})
+
+	t.Run("Non Member Forbidden", func(t *testing.T) {
+		id := "team-other"
+		team(id, "draft", "draft")
+		studentMock.On("FindByUserID", "user-stranger").Return(&models.Student{ID: "student-9"}, nil)
+		assert.Equal(t, 403, send("PUT", "/achievement/"+id, "user-stranger"))
+	})
+}
@@ -2,6 +2,8 @@ package services_test
 
 import (
     "bytes"
+    "context"
+    "database/sql"
     "encoding/json"
     "net/http/httptest"
     "testing"
@@ -13,40 +15,68 @@ import (
     "github.com/gofiber/fiber/v2"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
+    "go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestAchievementService(t *testing.T) {
     mongoMock := new(mocks.AchievementMongoMock)
     refMock := new(mocks.AchievementRefMock)
+    historyMock := new(mocks.AchievementHistoryMock)
+    rubricMock := new(mocks.RubricRepoMock)
     service := &services.AchievementService{
-        MongoRepo: mongoMock,
-        RefRepo:   refMock,
+        MongoRepo:   mongoMock,
+        RefRepo:     refMock,
+        HistoryRepo: historyMock,
+        RubricRepo:  rubricMock,
     }
 
     app := fiber.New()
 
     app.Use(func(c *fiber.Ctx) error {
-        c.Locals("user", &models.JWTClaims{UserID: "admin-123"})
+        c.Locals("user", &models.JWTClaims{UserID: "admin-123", Role: "Admin"})
         return c.Next()
     })
 
     app.Post("/achievement/:id/submit", service.SubmitAchievement())
     app.Post("/achievement/:id/verify", service.VerifyAchievement())
     app.Post("/achievement/:id/reject", service.RejectAchievement())
+    app.Get("/achievement/:id/history", service.GetAchievementHistory())
+    app.Post("/achievement/:id/request-revision", service.RequestRevision())
+
+    historyMock.On("Create", mock.Anything).Return(nil)
+    stubRubric(mongoMock, rubricMock, refMock)
+    mongoMock.On("FindDuplicateCandidates", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*models.MongoAchievement{}, nil)
+
     t.Run("Submit - Success", func(t *testing.T) {
         id := "123"
-        refMock.On("UpdateStatusByMongoID", id, "submitted", mock.Anything).Return(nil).Once()
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "draft"}, nil).Once()
+        refMock.On("SetApprovalKeyByMongoID", id, "competition", "national").Return(nil).Once()
+        refMock.On("UpdateStatusByMongoID", id, "", "submitted", mock.Anything).Return(nil).Once()
         req := httptest.NewRequest("POST", "/achievement/"+id+"/submit", nil)
         resp, _ := app.Test(req)
         assert.Equal(t, 200, resp.StatusCode)
     })
 
+    t.Run("Submit - Already Verified", func(t *testing.T) {
+        id := "124"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "verified"}, nil).Once()
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/submit", nil)
+        resp, _ := app.Test(req)
+        assert.Equal(t, 409, resp.StatusCode)
+
+        var result map[string]interface{}
+        json.NewDecoder(resp.Body).Decode(&result)
+        assert.Equal(t, "verified", result["current_status"])
+        assert.Equal(t, "submitted", result["requested_status"])
+    })
+
     t.Run("Verify - Success", func(t *testing.T) {
         id := "65818e69d9f58c42a0a6d001"
         payload := map[string]int{"points": 100}
         body, _ := json.Marshal(payload)
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted"}, nil).Once()
         mongoMock.On("UpdatePoints", mock.Anything, id, 100).Return(nil).Once()
-        refMock.On("VerifyByMongoID", id, "admin-123", mock.Anything).Return(nil).Once()
+        refMock.On("VerifyByMongoID", id, mock.Anything, "admin-123", mock.Anything, mock.Anything).Return(nil).Once()
         
         req := httptest.NewRequest("POST", "/achievement/"+id+"/verify", bytes.NewBuffer(body))
         req.Header.Set("Content-Type", "application/json")
@@ -54,13 +84,43 @@ func TestAchievementService(t *testing.T) {
         assert.Equal(t, 200, resp.StatusCode)
     })
 
+    t.Run("Verify - Override Without Justification", func(t *testing.T) {
+        id := "65818e69d9f58c42a0a6d002"
+        body, _ := json.Marshal(map[string]int{"points": 150})
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted"}, nil).Once()
+
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/verify", bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 400, resp.StatusCode)
+    })
+
+    t.Run("Verify - Override With Justification", func(t *testing.T) {
+        id := "65818e69d9f58c42a0a6d003"
+        body, _ := json.Marshal(map[string]interface{}{"points": 150, "justification": "juara umum"})
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted"}, nil).Once()
+        mongoMock.On("UpdatePoints", mock.Anything, id, 150).Return(nil).Once()
+        refMock.On("VerifyByMongoID", id, mock.Anything, "admin-123", mock.Anything, mock.Anything).Return(nil).Once()
+
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/verify", bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 200, resp.StatusCode)
+
+        var result map[string]interface{}
+        json.NewDecoder(resp.Body).Decode(&result)
+        assert.Equal(t, float64(100), result["computed_points"])
+        assert.Equal(t, true, result["overridden"])
+    })
+
     t.Run("Reject - Success", func(t *testing.T) {
         id := "mongo-id-123"
         note := "Berkas tidak lengkap"
         payload := map[string]string{"rejection_note": note}
         body, _ := json.Marshal(payload)
 
-        refMock.On("RejectByMongoID", id, note).Return(nil).Once()
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted"}, nil).Once()
+        refMock.On("RejectByMongoID", id, mock.Anything, note).Return(nil).Once()
 
         req := httptest.NewRequest("POST", "/achievement/"+id+"/reject", bytes.NewBuffer(body))
         req.Header.Set("Content-Type", "application/json")
@@ -75,7 +135,8 @@ func TestAchievementService(t *testing.T) {
         payload := map[string]string{"rejection_note": note}
         body, _ := json.Marshal(payload)
 
-        refMock.On("RejectByMongoID", id, note).
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted"}, nil).Once()
+        refMock.On("RejectByMongoID", id, mock.Anything, note).
             Return(errors.New("database connection lost")).Once()
 
         req := httptest.NewRequest("POST", "/achievement/"+id+"/reject", bytes.NewBuffer(body))
@@ -85,6 +146,56 @@ func TestAchievementService(t *testing.T) {
         assert.Equal(t, 500, resp.StatusCode)
     })
 
+    t.Run("Request Revision - Success", func(t *testing.T) {
+        id := "mongo-id-rev"
+        feedback := models.RevisionFeedback{{Field: "attachments", Comment: "lampirkan scan sertifikat"}}
+        body, _ := json.Marshal(map[string]interface{}{"feedback": feedback})
+
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted", RevisionCount: 1}, nil).Once()
+        refMock.On("RequestRevisionByMongoID", id, "", feedback).Return(nil).Once()
+
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/request-revision", bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 200, resp.StatusCode)
+
+        var result map[string]interface{}
+        json.NewDecoder(resp.Body).Decode(&result)
+        assert.Equal(t, "needs_revision", result["status"])
+        assert.Equal(t, float64(2), result["revision_count"])
+    })
+
+    t.Run("Request Revision - Empty Feedback", func(t *testing.T) {
+        body, _ := json.Marshal(map[string]interface{}{"note": "perbaiki"})
+        req := httptest.NewRequest("POST", "/achievement/mongo-id-rev/request-revision", bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 400, resp.StatusCode)
+    })
+
+    t.Run("History - Timeline", func(t *testing.T) {
+        id := "mongo-id-789"
+        draft, submitted := "draft", "submitted"
+        history := []*models.AchievementStatusHistory{
+            {MongoAchievementID: id, ToStatus: "draft"},
+            {MongoAchievementID: id, FromStatus: &draft, ToStatus: "submitted"},
+            {MongoAchievementID: id, FromStatus: &submitted, ToStatus: "rejected"},
+            {MongoAchievementID: id, FromStatus: &draft, ToStatus: "submitted"},
+        }
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, Status: "submitted"}, nil).Once()
+        historyMock.On("GetByMongoID", id).Return(history, nil).Once()
+
+        req := httptest.NewRequest("GET", "/achievement/"+id+"/history", nil)
+        resp, _ := app.Test(req)
+        assert.Equal(t, 200, resp.StatusCode)
+
+        var result map[string]interface{}
+        json.NewDecoder(resp.Body).Decode(&result)
+        assert.Len(t, result["data"], 4)
+        assert.Equal(t, float64(2), result["submitted_count"])
+        assert.Equal(t, float64(1), result["rejected_count"])
+    })
+
     t.Run("Get Report - Success", func(t *testing.T) {
     ids := []string{"id1", "id2"}
     mockData := []map[string]interface{}{
@@ -97,3 +208,457 @@ func TestAchievementService(t *testing.T) {
 })
 }
 
+// stubRubric membuat setiap prestasi bertipe competition tingkat nasional
+// dengan rubrik yang menghasilkan 100 poin.
+func stubRubric(mongoMock *mocks.AchievementMongoMock, rubricMock *mocks.RubricRepoMock, refMock *mocks.AchievementRefMock) {
+    level := "national"
+    mongoMock.On("FindByID", mock.Anything, mock.Anything).Return(&models.MongoAchievement{
+        AchievementType: "competition",
+        Details:         models.AchievementDetails{CompetitionLevel: &level},
+    }, nil)
+    rubricMock.On("FindByType", "competition").Return([]*models.RubricRule{
+        {AchievementType: "competition", Field: "base", Points: 20},
+        {AchievementType: "competition", Field: "competitionLevel", Value: "national", Points: 80},
+    }, nil)
+    refMock.On("UpdateScoringByMongoID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestAchievementReviewScope(t *testing.T) {
+    refMock := new(mocks.AchievementRefMock)
+    mongoMock := new(mocks.AchievementMongoMock)
+    rubricMock := new(mocks.RubricRepoMock)
+    studentMock := new(mocks.StudentRepoMock)
+    lecturerMock := new(mocks.LecturerRepoMock)
+    historyMock := new(mocks.AchievementHistoryMock)
+    service := &services.AchievementService{
+        MongoRepo:    mongoMock,
+        RefRepo:      refMock,
+        StudentRepo:  studentMock,
+        LecturerRepo: lecturerMock,
+        HistoryRepo:  historyMock,
+        RubricRepo:   rubricMock,
+    }
+
+    app := fiber.New()
+    app.Use(func(c *fiber.Ctx) error {
+        c.Locals("user", &models.JWTClaims{UserID: c.Get("X-User"), Role: c.Get("X-Role", "Dosen Wali")})
+        return c.Next()
+    })
+    app.Post("/achievement/:id/reject", service.RejectAchievement())
+    app.Post("/achievement/:id/submit", service.SubmitAchievement())
+    app.Get("/achievement/:id", service.GetAchievementByID())
+
+    advisorID := "lecturer-1"
+    historyMock.On("Create", mock.Anything).Return(nil)
+    stubRubric(mongoMock, rubricMock, refMock)
+    mongoMock.On("FindDuplicateCandidates", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*models.MongoAchievement{}, nil)
+    lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: advisorID}, nil)
+    lecturerMock.On("FindByUserID", "user-other").Return(&models.Lecturer{ID: "lecturer-2"}, nil)
+    studentMock.On("FindByID", "student-1").Return(&models.Student{ID: "student-1", AdvisorID: &advisorID}, nil)
+    studentMock.On("FindByUserID", "user-owner").Return(&models.Student{ID: "student-1"}, nil)
+    studentMock.On("FindByUserID", "user-stranger").Return(&models.Student{ID: "student-2"}, nil)
+
+    reject := func(id, userID string) int {
+        body, _ := json.Marshal(map[string]string{"rejection_note": "kurang bukti"})
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/reject", bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("X-User", userID)
+        resp, _ := app.Test(req)
+        return resp.StatusCode
+    }
+
+    t.Run("Advisor - Allowed", func(t *testing.T) {
+        id := "ach-1"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted"}, nil).Once()
+        refMock.On("RejectByMongoID", id, mock.Anything, "kurang bukti").Return(nil).Once()
+        assert.Equal(t, 200, reject(id, "user-advisor"))
+    })
+
+    t.Run("Other Lecturer - Forbidden", func(t *testing.T) {
+        id := "ach-2"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted"}, nil).Once()
+        assert.Equal(t, 403, reject(id, "user-other"))
+    })
+
+    t.Run("Unknown Student - Not Found", func(t *testing.T) {
+        id := "ach-6"
+        studentMock.On("FindByID", "student-gone").Return(nil, sql.ErrNoRows).Once()
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-gone", Status: "submitted"}, nil).Once()
+        assert.Equal(t, 404, reject(id, "user-advisor"))
+    })
+
+    t.Run("Student Lookup Fails - Server Error", func(t *testing.T) {
+        id := "ach-7"
+        studentMock.On("FindByID", "student-7").Return(nil, errors.New("connection reset")).Once()
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-7", Status: "submitted"}, nil).Once()
+        assert.Equal(t, 500, reject(id, "user-advisor"))
+    })
+
+    t.Run("Student - Submit Own", func(t *testing.T) {
+        id := "ach-3"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil).Once()
+        refMock.On("SetApprovalKeyByMongoID", id, "competition", "national").Return(nil).Once()
+        refMock.On("UpdateStatusByMongoID", id, "student-1", "submitted", mock.Anything).Return(nil).Once()
+
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/submit", nil)
+        req.Header.Set("X-User", "user-owner")
+        req.Header.Set("X-Role", "Mahasiswa")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 200, resp.StatusCode)
+    })
+
+    t.Run("Student - Submit Others Forbidden", func(t *testing.T) {
+        id := "ach-4"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil).Once()
+
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/submit", nil)
+        req.Header.Set("X-User", "user-stranger")
+        req.Header.Set("X-Role", "Mahasiswa")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 403, resp.StatusCode)
+    })
+
+    t.Run("Student - View Others Forbidden", func(t *testing.T) {
+        id := "ach-5"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "verified"}, nil).Once()
+
+        req := httptest.NewRequest("GET", "/achievement/"+id, nil)
+        req.Header.Set("X-User", "user-stranger")
+        req.Header.Set("X-Role", "Mahasiswa")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 403, resp.StatusCode)
+    })
+}
+
+func TestCheckTransition(t *testing.T) {
+    cases := []struct {
+        from, to string
+        allowed  bool
+    }{
+        {"draft", "submitted", true},
+        {"draft", "deleted", true},
+        {"draft", "verified", false},
+        {"submitted", "verified", true},
+        {"submitted", "rejected", true},
+        {"submitted", "draft", false},
+        {"submitted", "deleted", false},
+        {"submitted", "needs_revision", true},
+        {"needs_revision", "submitted", true},
+        {"needs_revision", "verified", false},
+        {"rejected", "draft", true},
+        {"rejected", "deleted", true},
+        {"rejected", "submitted", false},
+        {"verified", "draft", false},
+        {"verified", "deleted", false},
+        {"deleted", "draft", false},
+    }
+
+    for _, tc := range cases {
+        err := services.CheckTransition(tc.from, tc.to)
+        assert.Equal(t, tc.allowed, err == nil, "%s -> %s", tc.from, tc.to)
+    }
+
+    assert.True(t, services.IsEditable("draft"))
+    assert.True(t, services.IsEditable("rejected"))
+    assert.True(t, services.IsEditable("needs_revision"))
+    assert.False(t, services.IsEditable("submitted"))
+    assert.False(t, services.IsEditable("verified"))
+}
+
+func TestBulkReview(t *testing.T) {
+    refMock := new(mocks.AchievementRefMock)
+    mongoMock := new(mocks.AchievementMongoMock)
+    rubricMock := new(mocks.RubricRepoMock)
+    studentMock := new(mocks.StudentRepoMock)
+    lecturerMock := new(mocks.LecturerRepoMock)
+    historyMock := new(mocks.AchievementHistoryMock)
+    service := &services.AchievementService{
+        MongoRepo:    mongoMock,
+        RefRepo:      refMock,
+        StudentRepo:  studentMock,
+        LecturerRepo: lecturerMock,
+        HistoryRepo:  historyMock,
+        RubricRepo:   rubricMock,
+    }
+
+    app := fiber.New()
+    app.Use(func(c *fiber.Ctx) error {
+        c.Locals("user", &models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"})
+        return c.Next()
+    })
+    app.Post("/achievement/bulk/verify", service.BulkVerifyAchievements())
+    app.Post("/achievement/bulk/reject", service.BulkRejectAchievements())
+
+    advisorID := "lecturer-1"
+    otherID := "lecturer-2"
+    historyMock.On("Create", mock.Anything).Return(nil)
+    stubRubric(mongoMock, rubricMock, refMock)
+    lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: advisorID}, nil)
+    studentMock.On("FindByID", "student-1").Return(&models.Student{ID: "student-1", AdvisorID: &advisorID}, nil)
+    studentMock.On("FindByID", "student-2").Return(&models.Student{ID: "student-2", AdvisorID: &otherID}, nil)
+
+    post := func(path string, payload interface{}) models.BulkReviewReport {
+        body, _ := json.Marshal(payload)
+        req := httptest.NewRequest("POST", path, bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        resp, _ := app.Test(req)
+        assert.Equal(t, 200, resp.StatusCode)
+
+        var report models.BulkReviewReport
+        json.NewDecoder(resp.Body).Decode(&report)
+        return report
+    }
+
+    t.Run("Verify - Mixed Outcomes", func(t *testing.T) {
+        refMock.On("GetByMongoID", "b-1").Return(&models.AchievementReference{MongoAchievementID: "b-1", StudentID: "student-1", Status: "submitted"}, nil).Once()
+        refMock.On("GetByMongoID", "b-2").Return(&models.AchievementReference{MongoAchievementID: "b-2", StudentID: "student-1", Status: "verified"}, nil).Once()
+        refMock.On("GetByMongoID", "b-3").Return(&models.AchievementReference{MongoAchievementID: "b-3", StudentID: "student-2", Status: "submitted"}, nil).Once()
+        refMock.On("GetByMongoID", "b-4").Return(nil, errors.New("not found")).Once()
+        refMock.On("GetByMongoID", "b-5").Return(&models.AchievementReference{MongoAchievementID: "b-5", StudentID: "student-1", Status: "submitted"}, nil).Once()
+        mongoMock.On("UpdatePoints", mock.Anything, "b-1", 100).Return(nil).Once()
+        refMock.On("VerifyByMongoID", "b-1", mock.Anything, "user-advisor", mock.Anything, mock.Anything).Return(nil).Once()
+
+        report := post("/achievement/bulk/verify", map[string]interface{}{
+            "items": []map[string]interface{}{
+                {"id": "b-1"},
+                {"id": "b-2"},
+                {"id": "b-3"},
+                {"id": "b-4"},
+                {"id": "b-5", "points": 500},
+            },
+        })
+
+        assert.Equal(t, 5, report.Total)
+        assert.Equal(t, 1, report.Succeeded)
+        assert.Equal(t, 1, report.Conflicts)
+        assert.Equal(t, 1, report.Forbidden)
+        assert.Equal(t, 1, report.NotFound)
+        assert.Equal(t, 1, report.Invalid)
+        assert.Equal(t, "verified", report.Results[0].Status)
+        assert.Equal(t, 100, *report.Results[0].Points)
+        assert.Equal(t, "verified", report.Results[1].CurrentStatus)
+    })
+
+    t.Run("Reject - Independent Items", func(t *testing.T) {
+        refMock.On("GetByMongoID", "r-1").Return(&models.AchievementReference{MongoAchievementID: "r-1", StudentID: "student-1", Status: "submitted"}, nil).Once()
+        refMock.On("GetByMongoID", "r-2").Return(&models.AchievementReference{MongoAchievementID: "r-2", StudentID: "student-1", Status: "submitted"}, nil).Once()
+        refMock.On("RejectByMongoID", "r-1", mock.Anything, "duplikat").Return(errors.New("db down")).Once()
+        refMock.On("RejectByMongoID", "r-2", mock.Anything, "kurang bukti").Return(nil).Once()
+
+        report := post("/achievement/bulk/reject", map[string]interface{}{
+            "items": []map[string]string{
+                {"id": "r-1", "rejection_note": "duplikat"},
+                {"id": "r-2", "rejection_note": "kurang bukti"},
+            },
+        })
+
+        assert.Equal(t, 1, report.Failed)
+        assert.Equal(t, 1, report.Succeeded)
+        assert.Equal(t, "rejected", report.Results[1].Status)
+    })
+}
+
+func TestDelegatedReview(t *testing.T) {
+    refMock := new(mocks.AchievementRefMock)
+    studentMock := new(mocks.StudentRepoMock)
+    lecturerMock := new(mocks.LecturerRepoMock)
+    historyMock := new(mocks.AchievementHistoryMock)
+    delegationMock := new(mocks.DelegationRepoMock)
+    service := &services.AchievementService{
+        RefRepo:        refMock,
+        StudentRepo:    studentMock,
+        LecturerRepo:   lecturerMock,
+        HistoryRepo:    historyMock,
+        DelegationRepo: delegationMock,
+    }
+
+    app := fiber.New()
+    app.Use(func(c *fiber.Ctx) error {
+        c.Locals("user", &models.JWTClaims{UserID: c.Get("X-User"), Role: "Dosen Wali"})
+        return c.Next()
+    })
+    app.Post("/achievement/:id/reject", service.RejectAchievement())
+
+    advisorID := "lecturer-1"
+    delegation := &models.VerificationDelegation{ID: "del-1", AdvisorID: advisorID, DelegateID: "lecturer-2"}
+    lecturerMock.On("FindByUserID", "user-delegate").Return(&models.Lecturer{ID: "lecturer-2"}, nil)
+    lecturerMock.On("FindByUserID", "user-other").Return(&models.Lecturer{ID: "lecturer-3"}, nil)
+    studentMock.On("FindByID", "student-1").Return(&models.Student{ID: "student-1", AdvisorID: &advisorID}, nil)
+    delegationMock.On("FindActive", advisorID, "lecturer-2", mock.Anything).Return(delegation, nil)
+    lecturerMock.On("FindByUserID", "user-broken").Return(&models.Lecturer{ID: "lecturer-4"}, nil)
+    delegationMock.On("FindActive", advisorID, "lecturer-3", mock.Anything).Return(nil, sql.ErrNoRows)
+    delegationMock.On("FindActive", advisorID, "lecturer-4", mock.Anything).Return(nil, errors.New("connection reset"))
+
+    reject := func(id, userID string) int {
+        body, _ := json.Marshal(map[string]string{"rejection_note": "bukti kurang"})
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/reject", bytes.NewBuffer(body))
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("X-User", userID)
+        resp, _ := app.Test(req)
+        return resp.StatusCode
+    }
+
+    t.Run("Delegate - Allowed And Recorded", func(t *testing.T) {
+        id := "del-ach-1"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted"}, nil).Once()
+        refMock.On("RejectByMongoID", id, mock.Anything, "bukti kurang").Return(nil).Once()
+        historyMock.On("Create", mock.MatchedBy(func(h *models.AchievementStatusHistory) bool {
+            return h.ActorID == "user-delegate" &&
+                h.OnBehalfOf != nil && *h.OnBehalfOf == advisorID &&
+                h.DelegationID != nil && *h.DelegationID == "del-1"
+        })).Return(nil).Once()
+
+        assert.Equal(t, 200, reject(id, "user-delegate"))
+        historyMock.AssertExpectations(t)
+    })
+
+    t.Run("No Delegation - Forbidden", func(t *testing.T) {
+        id := "del-ach-2"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted"}, nil).Once()
+        assert.Equal(t, 403, reject(id, "user-other"))
+    })
+
+    t.Run("Delegation Lookup Fails - 500", func(t *testing.T) {
+        id := "del-ach-3"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted"}, nil).Once()
+        assert.Equal(t, 500, reject(id, "user-broken"))
+    })
+}
+
+func TestApprovalChain(t *testing.T) {
+    refMock := new(mocks.AchievementRefMock)
+    mongoMock := new(mocks.AchievementMongoMock)
+    rubricMock := new(mocks.RubricRepoMock)
+    studentMock := new(mocks.StudentRepoMock)
+    lecturerMock := new(mocks.LecturerRepoMock)
+    historyMock := new(mocks.AchievementHistoryMock)
+    chainMock := new(mocks.ApprovalChainRepoMock)
+    approvalMock := new(mocks.AchievementApprovalRepoMock)
+    service := &services.AchievementService{
+        MongoRepo:    mongoMock,
+        RefRepo:      refMock,
+        StudentRepo:  studentMock,
+        LecturerRepo: lecturerMock,
+        HistoryRepo:  historyMock,
+        RubricRepo:   rubricMock,
+        ChainRepo:    chainMock,
+        ApprovalRepo: approvalMock,
+    }
+
+    app := fiber.New()
+    app.Use(func(c *fiber.Ctx) error {
+        c.Locals("user", &models.JWTClaims{UserID: c.Get("X-User"), Role: c.Get("X-Role")})
+        return c.Next()
+    })
+    app.Post("/achievement/:id/verify", service.VerifyAchievement())
+
+    advisorID := "lecturer-1"
+    chainMock.On("FindAll").Return([]*models.ApprovalChain{
+        {AchievementType: "competition", CompetitionLevel: "national", Stages: models.ApprovalStages{
+            {Name: "advisor", Role: models.ApprovalRoleAdvisor},
+            {Name: "faculty", Role: "Admin"},
+        }},
+    }, nil)
+    stubRubric(mongoMock, rubricMock, refMock)
+    historyMock.On("Create", mock.Anything).Return(nil)
+    lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: advisorID}, nil)
+    studentMock.On("FindByID", "student-1").Return(&models.Student{ID: "student-1", AdvisorID: &advisorID}, nil)
+
+    verify := func(id, userID, role string) (int, map[string]interface{}) {
+        req := httptest.NewRequest("POST", "/achievement/"+id+"/verify", bytes.NewBufferString("{}"))
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("X-User", userID)
+        req.Header.Set("X-Role", role)
+        resp, _ := app.Test(req)
+
+        var body map[string]interface{}
+        json.NewDecoder(resp.Body).Decode(&body)
+        return resp.StatusCode, body
+    }
+
+    t.Run("Advisor Stage - Stays Submitted", func(t *testing.T) {
+        id := "chain-1"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted"}, nil).Once()
+        approvalMock.On("GetByMongoID", id, mock.Anything).Return(nil, nil).Once()
+        refMock.On("AdvanceApprovalStage", id, mock.Anything, 0).Return(nil).Once()
+        approvalMock.On("Create", mock.MatchedBy(func(a *models.AchievementApproval) bool {
+            return a.StageName == "advisor" && a.ApproverID == "user-advisor"
+        })).Return(nil).Once()
+
+        code, body := verify(id, "user-advisor", "Dosen Wali")
+        assert.Equal(t, 200, code)
+        assert.Equal(t, "submitted", body["status"])
+        assert.Equal(t, "faculty", body["next_stage"])
+        refMock.AssertNotCalled(t, "VerifyByMongoID", id, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+    })
+
+    t.Run("Faculty Stage - Advisor Forbidden", func(t *testing.T) {
+        id := "chain-2"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted", ApprovalStage: 1}, nil).Once()
+
+        code, _ := verify(id, "user-advisor", "Dosen Wali")
+        assert.Equal(t, 403, code)
+    })
+
+    t.Run("Faculty Stage - Admin Verifies", func(t *testing.T) {
+        id := "chain-3"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted", ApprovalStage: 1}, nil).Once()
+        approvalMock.On("GetByMongoID", id, mock.Anything).Return([]*models.AchievementApproval{{ApproverID: "user-advisor"}}, nil).Once()
+        mongoMock.On("UpdatePoints", mock.Anything, id, 100).Return(nil).Once()
+        refMock.On("VerifyByMongoID", id, mock.Anything, "user-admin", mock.Anything, mock.Anything).Return(nil).Once()
+        approvalMock.On("Create", mock.MatchedBy(func(a *models.AchievementApproval) bool {
+            return a.StageName == "faculty" && a.StageIndex == 1
+        })).Return(nil).Once()
+
+        code, body := verify(id, "user-admin", "Admin")
+        assert.Equal(t, 200, code)
+        assert.Equal(t, "verified", body["status"])
+    })
+
+    t.Run("Same Approver Twice - Conflict", func(t *testing.T) {
+        id := "chain-4"
+        refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "submitted", ApprovalStage: 1}, nil).Once()
+        approvalMock.On("GetByMongoID", id, mock.Anything).Return([]*models.AchievementApproval{{ApproverID: "user-admin"}}, nil).Once()
+
+        code, _ := verify(id, "user-admin", "Admin")
+        assert.Equal(t, 409, code)
+    })
+}
+
+func TestResolveApprovalChain(t *testing.T) {
+    national := "national"
+    local := "local"
+    chains := []*models.ApprovalChain{
+        {AchievementType: "competition", Stages: models.ApprovalStages{{Name: "advisor", Role: "advisor"}, {Name: "dept", Role: "Kaprodi"}}},
+        {AchievementType: "competition", CompetitionLevel: "national", Stages: models.ApprovalStages{{Name: "advisor", Role: "advisor"}, {Name: "faculty", Role: "Admin"}}},
+    }
+
+    got := services.ResolveApprovalChain(chains, &models.MongoAchievement{AchievementType: "competition", Details: models.AchievementDetails{CompetitionLevel: &national}})
+    assert.Equal(t, "faculty", got[1].Name)
+
+    got = services.ResolveApprovalChain(chains, &models.MongoAchievement{AchievementType: "competition", Details: models.AchievementDetails{CompetitionLevel: &local}})
+    assert.Equal(t, "dept", got[1].Name)
+
+    got = services.ResolveApprovalChain(chains, &models.MongoAchievement{AchievementType: "publication"})
+    assert.Equal(t, models.DefaultApprovalStages, got)
+}
+
+func TestBackfillApprovalKeys(t *testing.T) {
+    mongoMock := new(mocks.AchievementMongoMock)
+    refMock := new(mocks.AchievementRefMock)
+
+    national := " national "
+    found := primitive.NewObjectID()
+    missing := primitive.NewObjectID()
+    refMock.On("GetSubmittedWithoutApprovalKey").Return([]string{found.Hex(), missing.Hex()}, nil).Once()
+    mongoMock.On("FindByIDs", mock.Anything, []string{found.Hex(), missing.Hex()}).Return([]*models.MongoAchievement{
+        {ID: found, AchievementType: "competition", Details: models.AchievementDetails{CompetitionLevel: &national}},
+    }, nil).Once()
+    refMock.On("SetApprovalKeyByMongoID", found.Hex(), "competition", "national").Return(nil).Once()
+
+    n, err := services.BackfillApprovalKeys(context.Background(), mongoMock, refMock)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, n)
+    refMock.AssertExpectations(t)
+    refMock.AssertNotCalled(t, "SetApprovalKeyByMongoID", missing.Hex(), mock.Anything, mock.Anything)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// DefaultTrashRetentionDays adalah lama prestasi disimpan di tempat sampah
+// sebelum dihapus permanen.
+const DefaultTrashRetentionDays = 30
+
+// ListTrash godoc
+// @Summary List deleted achievements
+// @Description Mahasiswa melihat prestasinya yang dihapus; admin melihat semua (opsional ?student_id=)
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param student_id query string false "Student ID (admin only)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/trash [get]
+func (s *AchievementService) ListTrash() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var studentID string
+		switch {
+		case isAdmin(user):
+			studentID = c.Query("student_id")
+		case isStudent(user):
+			student, err := s.StudentRepo.FindByUserID(user.UserID)
+			if err != nil {
+				return c.Status(404).JSON(fiber.Map{"error": "student profile not found"})
+			}
+			studentID = student.ID
+		default:
+			return c.Status(403).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		refs, err := s.RefRepo.GetDeleted(studentID, nil)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		ids := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			ids = append(ids, ref.MongoAchievementID)
+		}
+		docs, err := s.MongoRepo.FindDeletedByIDs(context.Background(), ids)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		byID := map[string]*models.MongoAchievement{}
+		for _, doc := range docs {
+			byID[doc.ID.Hex()] = doc
+		}
+
+		items := []*models.TrashedAchievement{}
+		for _, ref := range refs {
+			item := &models.TrashedAchievement{
+				ID:                 ref.MongoAchievementID,
+				StudentID:          ref.StudentID,
+				StatusBeforeDelete: models.AchievementStatusDraft,
+				DeletedAt:          ref.DeletedAt,
+			}
+			if ref.StatusBeforeDelete != nil {
+				item.StatusBeforeDelete = *ref.StatusBeforeDelete
+			}
+			if doc, ok := byID[ref.MongoAchievementID]; ok {
+				item.Title = doc.Title
+				item.AchievementType = doc.AchievementType
+			}
+			items = append(items, item)
+		}
+
+		return c.JSON(fiber.Map{
+			"data":  items,
+			"total": len(items),
+		})
+	}
+}
+
+// RestoreAchievement godoc
+// @Summary Restore deleted achievement
+// @Description Pemilik prestasi (atau admin) mengembalikan prestasi dari tempat sampah ke status sebelum dihapus
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/restore [post]
+func (s *AchievementService) RestoreAchievement() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		ref, err := s.RefRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+		}
+
+		if err := s.access().CanModify(user, ref.StudentID); err != nil {
+			return accessDenied(c, err)
+		}
+
+		if ref.Status != models.AchievementStatusDeleted {
+			return c.Status(409).JSON(fiber.Map{
+				"error":          "achievement is not in trash",
+				"current_status": ref.Status,
+			})
+		}
+
+		status, outboxID, err := s.RefRepo.RestoreWithOutbox(id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		s.applyOutbox(outboxID, func() error {
+			return s.MongoRepo.Restore(context.Background(), id)
+		})
+
+		if err := s.recordTransition(user, ref, ref.Status, status, nil); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{
+			"message": "restored",
+			"id":      id,
+			"status":  status,
+		})
+	}
+}
+
+// TrashPurgeService menghapus permanen prestasi yang sudah melewati masa
+// simpan di tempat sampah: dokumen Mongo, revisinya, baris referensi, dan
+// file lampiran.
+type TrashPurgeService struct {
+	MongoRepo    repositories.IAchievementMongoRepository
+	RefRepo      repositories.IAchievementReferenceRepo
+	RevisionRepo repositories.IAchievementRevisionRepository
+	OutboxRepo   repositories.IAchievementOutboxRepository
+	Retention    time.Duration
+	UploadDir    string
+}
+
+func NewTrashPurgeService(
+	mongo repositories.IAchievementMongoRepository,
+	ref repositories.IAchievementReferenceRepo,
+	revision repositories.IAchievementRevisionRepository,
+	outbox repositories.IAchievementOutboxRepository,
+	retention time.Duration,
+) *TrashPurgeService {
+	return &TrashPurgeService{
+		MongoRepo:    mongo,
+		RefRepo:      ref,
+		RevisionRepo: revision,
+		OutboxRepo:   outbox,
+		Retention:    retention,
+		UploadDir:    "./uploads",
+	}
+}
+
+// RunPurge menjalankan Purge setiap interval sampai ctx dibatalkan.
+func (s *TrashPurgeService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Purge(ctx, time.Now())
+		if err != nil {
+			log.Println("trash purge:", err)
+		} else if result.Purged > 0 || result.Failed > 0 {
+			log.Printf("trash purge: %d purged, %d failed of %d expired", result.Purged, result.Failed, result.Scanned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge menghapus prestasi yang dihapus sebelum now dikurangi masa simpan.
+// Kegagalan satu prestasi tidak menghentikan yang lain; prestasi yang
+// referensinya gagal dihapus dicoba lagi pada putaran berikutnya.
+func (s *TrashPurgeService) Purge(ctx context.Context, now time.Time) (*models.TrashPurgeResult, error) {
+	retention := s.Retention
+	if retention <= 0 {
+		retention = days(DefaultTrashRetentionDays)
+	}
+	cutoff := now.Add(-retention)
+
+	refs, err := s.RefRepo.GetDeleted("", &cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.TrashPurgeResult{Scanned: len(refs)}
+	if len(refs) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.MongoAchievementID)
+	}
+	docs, err := s.MongoRepo.FindDeletedByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]*models.MongoAchievement{}
+	for _, doc := range docs {
+		byID[doc.ID.Hex()] = doc
+	}
+
+	for _, ref := range refs {
+		if err := s.purgeOne(ctx, ref.MongoAchievementID, byID[ref.MongoAchievementID]); err != nil {
+			log.Printf("trash purge %s: %v", ref.MongoAchievementID, err)
+			result.Failed++
+			continue
+		}
+		result.Purged++
+	}
+
+	return result, nil
+}
+
+// purgeOne menghapus baris referensi lebih dulu. Transaksi itu juga
+// mencatat pesan discard_document, sehingga dokumen Mongo yang gagal
+// dihapus di sini tetap dibuang oleh relay outbox. File lampiran dan
+// revisi dibersihkan setelahnya; kegagalannya dilaporkan tanpa
+// menghentikan langkah lain.
+func (s *TrashPurgeService) purgeOne(ctx context.Context, id string, doc *models.MongoAchievement) error {
+	outboxID, err := s.RefRepo.HardDeleteWithOutbox(id)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if doc != nil {
+		for _, att := range doc.Attachments {
+			if err := s.removeAttachment(att); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := s.MongoRepo.Discard(ctx, id); err != nil {
+		errs = append(errs, fmt.Errorf("document left to outbox relay: %w", err))
+	} else if s.OutboxRepo != nil && outboxID != "" {
+		if err := s.OutboxRepo.MarkProcessed(outboxID); err != nil {
+			log.Println("outbox: mark processed", outboxID, ":", err)
+		}
+	}
+
+	if s.RevisionRepo != nil {
+		if err := s.RevisionRepo.DeleteByAchievementID(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeAttachment hanya menghapus file di dalam UploadDir.
+func (s *TrashPurgeService) removeAttachment(att models.AchievementAttachment) error {
+	name := filepath.Base(att.FileUrl)
+	if name == "." || name == string(filepath.Separator) {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(s.UploadDir, name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
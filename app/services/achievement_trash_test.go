@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRestoreAchievement(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	studentMock := new(mocks.StudentRepoMock)
+	historyMock := new(mocks.AchievementHistoryMock)
+	service := &services.AchievementService{
+		MongoRepo:   mongoMock,
+		RefRepo:     refMock,
+		StudentRepo: studentMock,
+		HistoryRepo: historyMock,
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: "user-student", Role: "Mahasiswa"})
+		return c.Next()
+	})
+	app.Post("/achievement/:id/restore", service.RestoreAchievement())
+
+	studentMock.On("FindByUserID", "user-student").Return(&models.Student{ID: "student-1"}, nil)
+
+	t.Run("Restore - Previous Status", func(t *testing.T) {
+		id := "trash-1"
+		refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "deleted"}, nil).Once()
+		refMock.On("RestoreWithOutbox", id).Return("needs_revision", "", nil).Once()
+		mongoMock.On("Restore", mock.Anything, id).Return(nil).Once()
+		historyMock.On("Create", mock.MatchedBy(func(h *models.AchievementStatusHistory) bool {
+			return *h.FromStatus == "deleted" && h.ToStatus == "needs_revision"
+		})).Return(nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievement/"+id+"/restore", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+		historyMock.AssertExpectations(t)
+	})
+
+	t.Run("Restore - Not Deleted", func(t *testing.T) {
+		id := "trash-2"
+		refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1", Status: "draft"}, nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievement/"+id+"/restore", nil))
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("Restore - Other Student", func(t *testing.T) {
+		id := "trash-3"
+		refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-2", Status: "deleted"}, nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievement/"+id+"/restore", nil))
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
+
+func TestTrashPurge(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	revisionMock := new(mocks.AchievementRevisionRepoMock)
+	outboxMock := new(mocks.AchievementOutboxMock)
+	service := services.NewTrashPurgeService(mongoMock, refMock, revisionMock, outboxMock, 30*24*time.Hour)
+	service.UploadDir = t.TempDir()
+
+	file := filepath.Join(service.UploadDir, "sertifikat.pdf")
+	assert.NoError(t, os.WriteFile(file, []byte("pdf"), 0o644))
+
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	okID, failID, mongoDownID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	refMock.On("GetDeleted", "", mock.MatchedBy(func(before *time.Time) bool {
+		return before.Equal(now.AddDate(0, 0, -30))
+	})).Return([]*models.AchievementReference{
+		{MongoAchievementID: okID.Hex()},
+		{MongoAchievementID: failID.Hex()},
+		{MongoAchievementID: mongoDownID.Hex()},
+	}, nil)
+	mongoMock.On("FindDeletedByIDs", mock.Anything, []string{okID.Hex(), failID.Hex(), mongoDownID.Hex()}).Return([]*models.MongoAchievement{
+		{ID: okID, Attachments: []models.AchievementAttachment{{FileUrl: "./uploads/sertifikat.pdf"}}},
+	}, nil)
+
+	// Referensi dihapus lebih dulu; gagal di sini berarti dokumen tidak disentuh.
+	refMock.On("HardDeleteWithOutbox", okID.Hex()).Return("outbox-ok", nil).Once()
+	refMock.On("HardDeleteWithOutbox", failID.Hex()).Return("", errors.New("db down")).Once()
+	refMock.On("HardDeleteWithOutbox", mongoDownID.Hex()).Return("outbox-down", nil).Once()
+
+	mongoMock.On("Discard", mock.Anything, okID.Hex()).Return(nil).Once()
+	mongoMock.On("Discard", mock.Anything, mongoDownID.Hex()).Return(errors.New("mongo down")).Once()
+	outboxMock.On("MarkProcessed", "outbox-ok").Return(nil).Once()
+	revisionMock.On("DeleteByAchievementID", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := service.Purge(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Scanned)
+	assert.Equal(t, 1, result.Purged)
+	assert.Equal(t, 2, result.Failed)
+
+	_, statErr := os.Stat(file)
+	assert.True(t, os.IsNotExist(statErr))
+
+	mongoMock.AssertNotCalled(t, "Discard", mock.Anything, failID.Hex())
+	revisionMock.AssertNotCalled(t, "DeleteByAchievementID", mock.Anything, failID.Hex())
+	// Dokumen yang gagal dibuang tetap menunggu relay outbox.
+	outboxMock.AssertNotCalled(t, "MarkProcessed", "outbox-down")
+	outboxMock.AssertExpectations(t)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+var achievementTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+type AchievementTypeService struct {
+	TypeRepo  repositories.IAchievementTypeRepository
+	MongoRepo repositories.IAchievementMongoRepository
+}
+
+func NewAchievementTypeService(
+	types repositories.IAchievementTypeRepository,
+	mongo repositories.IAchievementMongoRepository,
+) *AchievementTypeService {
+	return &AchievementTypeService{TypeRepo: types, MongoRepo: mongo}
+}
+
+// ListAchievementTypes godoc
+// @Summary List achievement types
+// @Description Melihat tipe prestasi bawaan dan tipe tambahan beserta schema customFields
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievement-types [get]
+func (s *AchievementTypeService) ListAchievementTypes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		defs, err := s.TypeRepo.FindAll()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if defs == nil {
+			defs = []*models.AchievementTypeDefinition{}
+		}
+
+		return c.JSON(fiber.Map{
+			"builtin": knownAchievementTypes(),
+			"custom":  defs,
+		})
+	}
+}
+
+// CreateAchievementType godoc
+// @Summary Register achievement type
+// @Description Admin mendaftarkan tipe prestasi baru dengan JSON Schema customFields dan poin default
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Param type body models.AchievementTypeDefinition true "Achievement type"
+// @Success 201 {object} models.AchievementTypeDefinition
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievement-types [post]
+func (s *AchievementTypeService) CreateAchievementType() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var def models.AchievementTypeDefinition
+		if err := c.BodyParser(&def); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if msg := validateAchievementType(&def); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		if _, err := s.TypeRepo.FindByCode(def.Code); err == nil {
+			return c.Status(409).JSON(fiber.Map{"error": "achievement type already exists"})
+		} else if err != sql.ErrNoRows {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if err := s.TypeRepo.Create(&def); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.Status(201).JSON(def)
+	}
+}
+
+// UpdateAchievementType godoc
+// @Summary Update achievement type
+// @Description Admin mengubah schema customFields atau poin default tipe prestasi tambahan
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Param code path string true "Achievement type code"
+// @Param type body models.AchievementTypeDefinition true "Achievement type"
+// @Success 200 {object} models.AchievementTypeDefinition
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievement-types/{code} [put]
+func (s *AchievementTypeService) UpdateAchievementType() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var def models.AchievementTypeDefinition
+		if err := c.BodyParser(&def); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		def.Code = c.Params("code")
+
+		if msg := validateAchievementType(&def); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		if err := s.TypeRepo.Update(&def); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "achievement type not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(def)
+	}
+}
+
+// DeleteAchievementType godoc
+// @Summary Delete achievement type
+// @Description Admin menghapus tipe prestasi tambahan yang belum dipakai prestasi mana pun
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Param code path string true "Achievement type code"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievement-types/{code} [delete]
+func (s *AchievementTypeService) DeleteAchievementType() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		code := c.Params("code")
+
+		// Prestasi yang sudah memakai tipe ini (termasuk di tempat sampah)
+		// masih butuh definisinya untuk validasi dan poin bawaan.
+		used, err := s.MongoRepo.CountByType(context.Background(), code)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if used > 0 {
+			return c.Status(409).JSON(fiber.Map{
+				"error":        "achievement type is used by existing achievements",
+				"achievements": used,
+			})
+		}
+
+		if err := s.TypeRepo.Delete(code); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "achievement type not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+func validateAchievementType(def *models.AchievementTypeDefinition) string {
+	def.Code = strings.ToLower(strings.TrimSpace(def.Code))
+	def.Name = strings.TrimSpace(def.Name)
+
+	if !achievementTypeCodePattern.MatchString(def.Code) {
+		return "code must be 2-50 characters of lowercase letters, digits or underscore"
+	}
+	if isBuiltinAchievementType(def.Code) {
+		return "code is reserved for a built-in achievement type"
+	}
+	if def.Name == "" {
+		return "name is required"
+	}
+	if def.DefaultPoints < 0 {
+		return "defaultPoints must not be negative"
+	}
+	if len(def.CustomFieldsSchema) == 0 {
+		def.CustomFieldsSchema = []byte("{}")
+	}
+	if _, err := CompileCustomFieldsSchema(def.CustomFieldsSchema); err != nil {
+		return "invalid customFieldsSchema: " + err.Error()
+	}
+	return ""
+}
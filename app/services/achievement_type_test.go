@@ -0,0 +1,58 @@
+package services_test
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteAchievementType(t *testing.T) {
+	typeMock := new(mocks.AchievementTypeRepoMock)
+	mongoMock := new(mocks.AchievementMongoMock)
+	service := services.NewAchievementTypeService(typeMock, mongoMock)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: "user-admin", Role: "Admin"})
+		return c.Next()
+	})
+	app.Delete("/achievement-types/:code", service.DeleteAchievementType())
+
+	del := func(code string) int {
+		resp, _ := app.Test(httptest.NewRequest("DELETE", "/achievement-types/"+code, nil))
+		return resp.StatusCode
+	}
+
+	t.Run("Unused - Deleted", func(t *testing.T) {
+		mongoMock.On("CountByType", mock.Anything, "hackathon").Return(int64(0), nil).Once()
+		typeMock.On("Delete", "hackathon").Return(nil).Once()
+		assert.Equal(t, 200, del("hackathon"))
+		typeMock.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("In Use - Conflict", func(t *testing.T) {
+		mongoMock.On("CountByType", mock.Anything, "olympiad").Return(int64(3), nil).Once()
+		assert.Equal(t, 409, del("olympiad"))
+		typeMock.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mongoMock.On("CountByType", mock.Anything, "unknown").Return(int64(0), nil).Once()
+		typeMock.On("Delete", "unknown").Return(sql.ErrNoRows).Once()
+		assert.Equal(t, 404, del("unknown"))
+		typeMock.AssertNumberOfCalls(t, "Delete", 2)
+	})
+
+	t.Run("Count Fails", func(t *testing.T) {
+		mongoMock.On("CountByType", mock.Anything, "seminar").Return(int64(0), assert.AnError).Once()
+		assert.Equal(t, 500, del("seminar"))
+		typeMock.AssertNumberOfCalls(t, "Delete", 2)
+	})
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"uas/app/models"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type achievementTypeSpec struct {
+	required []string
+	optional []string
+}
+
+// commonDetailFields boleh diisi untuk semua tipe prestasi.
+var commonDetailFields = []string{"eventDate", "location", "organizer", "score", "customFields"}
+
+// achievementTypeSpecs mendefinisikan field details yang wajib dan yang
+// diizinkan untuk setiap AchievementType bawaan.
+var achievementTypeSpecs = map[string]achievementTypeSpec{
+	"competition": {
+		required: []string{"competitionName", "competitionLevel"},
+		optional: []string{"rank", "medalType"},
+	},
+	"publication": {
+		required: []string{"publicationType", "publicationTitle", "authors"},
+		optional: []string{"publisher", "issn"},
+	},
+	"organization": {
+		required: []string{"organizationName", "position", "period"},
+	},
+	"certification": {
+		required: []string{"certificationName", "issuedBy"},
+		optional: []string{"certificationNumber", "validUntil"},
+	},
+	"academic": {},
+	"other":    {},
+}
+
+var detailEnums = map[string][]string{
+	"competitionLevel": {"international", "national", "regional", "local"},
+	"medalType":        {"gold", "silver", "bronze"},
+	"publicationType":  {"journal", "conference", "book"},
+}
+
+// ValidateAchievement memeriksa judul, field details sesuai tipe, nilai
+// enum, dan konsistensi tanggal. Semua kesalahan dikembalikan sekaligus.
+func ValidateAchievement(doc *models.MongoAchievement) []FieldError {
+	errs := []FieldError{}
+
+	if strings.TrimSpace(doc.Title) == "" {
+		errs = append(errs, FieldError{"title", "is required"})
+	}
+
+	achType := strings.ToLower(strings.TrimSpace(doc.AchievementType))
+	spec, ok := achievementTypeSpecs[achType]
+	if !ok {
+		errs = append(errs, FieldError{"achievementType", "must be one of: " + strings.Join(knownAchievementTypes(), ", ")})
+		return errs
+	}
+
+	present := presentDetailFields(&doc.Details)
+
+	for _, f := range spec.required {
+		if !present[f] {
+			errs = append(errs, FieldError{"details." + f, "is required for " + achType})
+		}
+	}
+
+	allowed := map[string]bool{}
+	for _, group := range [][]string{spec.required, spec.optional, commonDetailFields} {
+		for _, f := range group {
+			allowed[f] = true
+		}
+	}
+	for _, f := range sortedKeys(present) {
+		if !allowed[f] {
+			errs = append(errs, FieldError{"details." + f, "is not allowed for " + achType})
+		}
+	}
+
+	errs = append(errs, validateDetailValues(&doc.Details)...)
+
+	return errs
+}
+
+// ValidateCustomAchievement memvalidasi prestasi bertipe tambahan. Details
+// hanya boleh berisi field umum, dan customFields harus sesuai JSON Schema
+// yang didaftarkan untuk tipe tersebut.
+func ValidateCustomAchievement(doc *models.MongoAchievement, def *models.AchievementTypeDefinition) []FieldError {
+	errs := []FieldError{}
+
+	if strings.TrimSpace(doc.Title) == "" {
+		errs = append(errs, FieldError{"title", "is required"})
+	}
+
+	allowed := map[string]bool{}
+	for _, f := range commonDetailFields {
+		allowed[f] = true
+	}
+	for _, f := range sortedKeys(presentDetailFields(&doc.Details)) {
+		if !allowed[f] {
+			errs = append(errs, FieldError{"details." + f, "is not allowed for " + def.Code})
+		}
+	}
+
+	errs = append(errs, validateDetailValues(&doc.Details)...)
+
+	schema, err := CompileCustomFieldsSchema(def.CustomFieldsSchema)
+	if err != nil {
+		return append(errs, FieldError{"achievementType", "has an invalid customFields schema"})
+	}
+	if schema != nil {
+		errs = append(errs, validateCustomFields(schema, doc.Details.CustomFields)...)
+	}
+
+	return errs
+}
+
+// CompileCustomFieldsSchema mengompilasi JSON Schema customFields. Schema
+// kosong berarti customFields tidak dibatasi dan mengembalikan nil.
+func CompileCustomFieldsSchema(raw json.RawMessage) (*jsonschema.Schema, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	return jsonschema.CompileString("customFields.json", string(trimmed))
+}
+
+func validateCustomFields(schema *jsonschema.Schema, fields map[string]interface{}) []FieldError {
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+
+	// Round-trip lewat JSON supaya tipe nilai sama dengan yang dikenal validator.
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return []FieldError{{"details.customFields", err.Error()}}
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return []FieldError{{"details.customFields", err.Error()}}
+	}
+
+	err = schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []FieldError{{"details.customFields", err.Error()}}
+	}
+
+	errs := []FieldError{}
+	var walk func(*jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			errs = append(errs, FieldError{
+				"details.customFields" + strings.ReplaceAll(e.InstanceLocation, "/", "."),
+				e.Message,
+			})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(ve)
+
+	return errs
+}
+
+func validateDetailValues(d *models.AchievementDetails) []FieldError {
+	errs := []FieldError{}
+
+	checkEnum := func(field string, v *string) {
+		if v == nil {
+			return
+		}
+		for _, allowed := range detailEnums[field] {
+			if strings.EqualFold(strings.TrimSpace(*v), allowed) {
+				return
+			}
+		}
+		errs = append(errs, FieldError{"details." + field, "must be one of: " + strings.Join(detailEnums[field], ", ")})
+	}
+	checkEnum("competitionLevel", d.CompetitionLevel)
+	checkEnum("medalType", d.MedalType)
+	checkEnum("publicationType", d.PublicationType)
+
+	if d.Rank != nil && *d.Rank < 1 {
+		errs = append(errs, FieldError{"details.rank", "must be at least 1"})
+	}
+	if d.Score != nil && *d.Score < 0 {
+		errs = append(errs, FieldError{"details.score", "must not be negative"})
+	}
+	if d.Authors != nil {
+		for _, a := range d.Authors {
+			if strings.TrimSpace(a) == "" {
+				errs = append(errs, FieldError{"details.authors", "must not contain empty names"})
+				break
+			}
+		}
+	}
+	if d.Period != nil {
+		if d.Period.Start.IsZero() || d.Period.End.IsZero() {
+			errs = append(errs, FieldError{"details.period", "start and end are required"})
+		} else if d.Period.Start.After(d.Period.End) {
+			errs = append(errs, FieldError{"details.period", "start must not be after end"})
+		}
+	}
+	if d.ValidUntil != nil && d.EventDate != nil && d.ValidUntil.Before(*d.EventDate) {
+		errs = append(errs, FieldError{"details.validUntil", "must not be before eventDate"})
+	}
+
+	return errs
+}
+
+// presentDetailFields mengembalikan nama (tag bson) field details yang terisi.
+func presentDetailFields(d *models.AchievementDetails) map[string]bool {
+	present := map[string]bool{}
+
+	v := reflect.ValueOf(d).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if fv.IsNil() || (fv.Kind() != reflect.Ptr && fv.Len() == 0) {
+				continue
+			}
+		default:
+			if fv.IsZero() {
+				continue
+			}
+		}
+
+		name := strings.Split(t.Field(i).Tag.Get("bson"), ",")[0]
+		if fv.Kind() == reflect.Ptr && fv.Elem().Kind() == reflect.String &&
+			strings.TrimSpace(fv.Elem().String()) == "" {
+			continue
+		}
+		present[name] = true
+	}
+
+	return present
+}
+
+func knownAchievementTypes() []string {
+	return sortedKeys(achievementTypeSpecs)
+}
+
+func isBuiltinAchievementType(code string) bool {
+	_, ok := achievementTypeSpecs[strings.ToLower(strings.TrimSpace(code))]
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func validationFailed(c *fiber.Ctx, errs []FieldError) error {
+	return c.Status(422).JSON(fiber.Map{
+		"error":  "validation failed",
+		"fields": errs,
+	})
+}
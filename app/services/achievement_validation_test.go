@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fieldsOf(errs []services.FieldError) []string {
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+func TestValidateAchievement(t *testing.T) {
+	t.Run("Valid Competition", func(t *testing.T) {
+		name, level, medal, rank := "Gemastik", "national", "gold", 1
+		doc := &models.MongoAchievement{
+			AchievementType: "competition",
+			Title:           "Juara 1 Gemastik",
+			Details: models.AchievementDetails{
+				CompetitionName:  &name,
+				CompetitionLevel: &level,
+				MedalType:        &medal,
+				Rank:             &rank,
+			},
+		}
+		assert.Empty(t, services.ValidateAchievement(doc))
+	})
+
+	t.Run("Competition Missing Fields And Bad Enum", func(t *testing.T) {
+		level, title := "galactic", "Paper"
+		doc := &models.MongoAchievement{
+			AchievementType: "competition",
+			Title:           "Lomba",
+			Details: models.AchievementDetails{
+				CompetitionLevel: &level,
+				PublicationTitle: &title,
+			},
+		}
+		fields := fieldsOf(services.ValidateAchievement(doc))
+		assert.ElementsMatch(t, []string{
+			"details.competitionName",
+			"details.publicationTitle",
+			"details.competitionLevel",
+		}, fields)
+	})
+
+	t.Run("Publication Requires Authors", func(t *testing.T) {
+		pubType, title := "journal", "Deep Learning"
+		doc := &models.MongoAchievement{
+			AchievementType: "publication",
+			Title:           "Publikasi",
+			Details: models.AchievementDetails{
+				PublicationType:  &pubType,
+				PublicationTitle: &title,
+			},
+		}
+		assert.Equal(t, []string{"details.authors"}, fieldsOf(services.ValidateAchievement(doc)))
+	})
+
+	t.Run("Organization Period Order", func(t *testing.T) {
+		org, pos := "BEM", "Ketua"
+		doc := &models.MongoAchievement{
+			AchievementType: "organization",
+			Title:           "Ketua BEM",
+			Details: models.AchievementDetails{
+				OrganizationName: &org,
+				Position:         &pos,
+			},
+		}
+		doc.Details.Period = &struct {
+			Start time.Time `bson:"start"`
+			End   time.Time `bson:"end"`
+		}{Start: time.Now(), End: time.Now().AddDate(-1, 0, 0)}
+
+		assert.Equal(t, []string{"details.period"}, fieldsOf(services.ValidateAchievement(doc)))
+	})
+
+	t.Run("Unknown Type", func(t *testing.T) {
+		doc := &models.MongoAchievement{AchievementType: "hackathon"}
+		assert.ElementsMatch(t, []string{"title", "achievementType"}, fieldsOf(services.ValidateAchievement(doc)))
+	})
+}
+
+func TestValidateCustomAchievement(t *testing.T) {
+	def := &models.AchievementTypeDefinition{
+		Code: "hackathon",
+		Name: "Hackathon",
+		CustomFieldsSchema: []byte(`{
+			"type": "object",
+			"required": ["teamName", "teamSize"],
+			"properties": {
+				"teamName": {"type": "string", "minLength": 1},
+				"teamSize": {"type": "integer", "minimum": 1, "maximum": 10}
+			},
+			"additionalProperties": false
+		}`),
+	}
+
+	t.Run("Valid Custom Fields", func(t *testing.T) {
+		organizer := "Google"
+		doc := &models.MongoAchievement{
+			AchievementType: "hackathon",
+			Title:           "Juara Hackathon",
+			Details: models.AchievementDetails{
+				Organizer:    &organizer,
+				CustomFields: map[string]interface{}{"teamName": "Kopi", "teamSize": float64(4)},
+			},
+		}
+		assert.Empty(t, services.ValidateCustomAchievement(doc, def))
+	})
+
+	t.Run("Schema Violations And Foreign Details", func(t *testing.T) {
+		level := "national"
+		doc := &models.MongoAchievement{
+			AchievementType: "hackathon",
+			Title:           "Hackathon",
+			Details: models.AchievementDetails{
+				CompetitionLevel: &level,
+				CustomFields:     map[string]interface{}{"teamSize": float64(20), "extra": true},
+			},
+		}
+		fields := fieldsOf(services.ValidateCustomAchievement(doc, def))
+		assert.Contains(t, fields, "details.competitionLevel")
+		assert.Contains(t, fields, "details.customFields")
+		assert.Contains(t, fields, "details.customFields.teamSize")
+	})
+
+	t.Run("Invalid Schema", func(t *testing.T) {
+		_, err := services.CompileCustomFieldsSchema([]byte(`{"type": 12}`))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+type ApprovalChainService struct {
+	ChainRepo repositories.IApprovalChainRepository
+}
+
+func NewApprovalChainService(chain repositories.IApprovalChainRepository) *ApprovalChainService {
+	return &ApprovalChainService{ChainRepo: chain}
+}
+
+// ListApprovalChains godoc
+// @Summary List approval chains
+// @Description Admin melihat rantai persetujuan per tipe prestasi dan tingkat lomba
+// @Tags Approval Chains
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /approval-chains [get]
+func (s *ApprovalChainService) ListApprovalChains() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		chains, err := s.ChainRepo.FindAll()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if chains == nil {
+			chains = []*models.ApprovalChain{}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":     chains,
+			"fallback": models.DefaultApprovalStages,
+		})
+	}
+}
+
+// UpsertApprovalChain godoc
+// @Summary Set approval chain
+// @Description Admin mengatur tahap persetujuan untuk satu tipe prestasi (competitionLevel kosong = semua tingkat).
+// @Description Role "advisor" berarti dosen wali mahasiswa; role lain dicocokkan dengan role user.
+// @Tags Approval Chains
+// @Accept json
+// @Produce json
+// @Param chain body models.ApprovalChain true "Approval chain"
+// @Success 200 {object} models.ApprovalChain
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /approval-chains [put]
+func (s *ApprovalChainService) UpsertApprovalChain() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var chain models.ApprovalChain
+		if err := c.BodyParser(&chain); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if msg := validateApprovalChain(&chain); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		if err := s.ChainRepo.Upsert(&chain); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(chain)
+	}
+}
+
+// DeleteApprovalChain godoc
+// @Summary Delete approval chain
+// @Description Admin menghapus rantai persetujuan sehingga tipe tersebut kembali memakai rantai default
+// @Tags Approval Chains
+// @Accept json
+// @Produce json
+// @Param id path string true "Approval chain ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /approval-chains/{id} [delete]
+func (s *ApprovalChainService) DeleteApprovalChain() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		if err := s.ChainRepo.Delete(c.Params("id")); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "approval chain not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+func validateApprovalChain(chain *models.ApprovalChain) string {
+	chain.AchievementType = strings.ToLower(strings.TrimSpace(chain.AchievementType))
+	chain.CompetitionLevel = strings.ToLower(strings.TrimSpace(chain.CompetitionLevel))
+
+	if chain.AchievementType == "" {
+		return "achievementType is required"
+	}
+	if chain.CompetitionLevel != "" {
+		if chain.AchievementType != "competition" {
+			return "competitionLevel is only allowed for competition"
+		}
+		level := chain.CompetitionLevel
+		if errs := validateDetailValues(&models.AchievementDetails{CompetitionLevel: &level}); len(errs) > 0 {
+			return "competitionLevel " + errs[0].Message
+		}
+	}
+	if len(chain.Stages) == 0 {
+		return "stages must not be empty"
+	}
+
+	seen := map[string]bool{}
+	for i := range chain.Stages {
+		stage := &chain.Stages[i]
+		stage.Name = strings.ToLower(strings.TrimSpace(stage.Name))
+		stage.Role = strings.TrimSpace(stage.Role)
+		if stage.Name == "" || stage.Role == "" {
+			return "each stage needs a name and a role"
+		}
+		if seen[stage.Name] {
+			return "stage names must be unique"
+		}
+		seen[stage.Name] = true
+	}
+	return ""
+}
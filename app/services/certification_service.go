@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// DefaultCertificationExpiryDays adalah rentang bawaan ListExpiringCertifications.
+const DefaultCertificationExpiryDays = 30
+
+type CertificationService struct {
+	MongoRepo        repositories.IAchievementMongoRepository
+	RefRepo          repositories.IAchievementReferenceRepo
+	LecturerRepo     repositories.ILecturerRepository
+	StudentRepo      repositories.IStudentRepository
+	NotificationRepo repositories.ICertificationNotificationRepository
+}
+
+func NewCertificationService(
+	mongo repositories.IAchievementMongoRepository,
+	ref repositories.IAchievementReferenceRepo,
+	lecturer repositories.ILecturerRepository,
+	student repositories.IStudentRepository,
+	notification repositories.ICertificationNotificationRepository,
+) *CertificationService {
+	return &CertificationService{
+		MongoRepo:        mongo,
+		RefRepo:          ref,
+		LecturerRepo:     lecturer,
+		StudentRepo:      student,
+		NotificationRepo: notification,
+	}
+}
+
+// CertificationValid bernilai true jika sertifikasi masih berlaku pada now.
+// Sertifikasi tanpa validUntil dianggap berlaku selamanya.
+func CertificationValid(validUntil *time.Time, now time.Time) bool {
+	return validUntil == nil || now.Before(*validUntil)
+}
+
+// RunExpiryChecker menjalankan CheckExpiry setiap interval sampai ctx dibatalkan.
+func (s *CertificationService) RunExpiryChecker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.CheckExpiry(ctx, time.Now())
+		if err != nil {
+			log.Println("certification expiry:", err)
+		} else if result.Expired > 0 || result.Notified > 0 {
+			log.Printf("certification expiry: %d expired of %d verified, %d notified",
+				result.Expired, result.Scanned, result.Notified)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckExpiry menandai sertifikasi terverifikasi yang validUntil-nya sudah
+// lewat sebagai expired, lalu memberi tahu mahasiswa dan dosen walinya.
+// Dokumen sertifikasi terverifikasi yang sudah diproses ditandai di Mongo
+// sehingga tidak dipindai ulang; yang belum terverifikasi tetap dipindai
+// sampai diverifikasi. Sertifikasi yang kedaluwarsa dalam
+// DefaultCertificationExpiryDays hari ke depan mendapat notifikasi expiring.
+func (s *CertificationService) CheckExpiry(ctx context.Context, now time.Time) (*models.CertificationExpiryResult, error) {
+	result := &models.CertificationExpiryResult{}
+
+	docs, err := s.MongoRepo.FindUnmarkedExpiredCertifications(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := s.verifiedCertifications(docs)
+	if err != nil {
+		return nil, err
+	}
+	result.Scanned = len(certs)
+
+	var expired, processed []string
+	for _, c := range certs {
+		processed = append(processed, c.MongoAchievementID)
+		if c.ExpiredAt == nil {
+			expired = append(expired, c.MongoAchievementID)
+		}
+	}
+
+	if len(expired) > 0 {
+		if err := s.RefRepo.MarkExpiredByMongoIDs(expired, now); err != nil {
+			return nil, err
+		}
+	}
+	result.Expired = len(expired)
+
+	notified, err := s.notify(certs, models.CertificationExpired)
+	if err != nil {
+		return nil, err
+	}
+	result.Notified += notified
+
+	if len(processed) > 0 {
+		if err := s.MongoRepo.MarkCertificationsExpired(ctx, processed, now); err != nil {
+			return nil, err
+		}
+	}
+
+	docs, err = s.MongoRepo.FindCertificationsValidUntil(ctx, &now, now.Add(days(DefaultCertificationExpiryDays)))
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err = s.verifiedCertifications(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	notified, err = s.notify(certs, models.CertificationExpiring)
+	if err != nil {
+		return nil, err
+	}
+	result.Notified += notified
+
+	return result, nil
+}
+
+// verifiedCertifications mengembalikan referensi terverifikasi dari docs
+// beserta validUntil dokumennya.
+func (s *CertificationService) verifiedCertifications(docs []*models.MongoAchievement) ([]*models.CertificationValidity, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	byID := make(map[string]*models.MongoAchievement, len(docs))
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		byID[doc.ID.Hex()] = doc
+		ids = append(ids, doc.ID.Hex())
+	}
+
+	certs, err := s.RefRepo.GetVerifiedCertifications(ids, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range certs {
+		if doc, ok := byID[c.MongoAchievementID]; ok {
+			c.ValidUntil = doc.Details.ValidUntil
+		}
+	}
+	return certs, nil
+}
+
+// notify membuat notifikasi kind untuk mahasiswa dan dosen wali setiap
+// sertifikasi. Notifikasi yang sudah pernah dibuat tidak dihitung.
+func (s *CertificationService) notify(certs []*models.CertificationValidity, kind string) (int, error) {
+	created := 0
+	for _, c := range certs {
+		recipients := map[string]string{models.RecipientStudent: c.StudentID}
+		if c.AdvisorID != nil {
+			recipients[models.RecipientAdvisor] = *c.AdvisorID
+		}
+
+		for role, id := range recipients {
+			ok, err := s.NotificationRepo.Create(&models.CertificationNotification{
+				MongoAchievementID: c.MongoAchievementID,
+				StudentID:          c.StudentID,
+				RecipientRole:      role,
+				RecipientID:        id,
+				Kind:               kind,
+				ValidUntil:         c.ValidUntil,
+			})
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+// ListCertificationNotifications godoc
+// @Summary List certification notifications
+// @Description Melihat notifikasi sertifikasi yang akan atau sudah kedaluwarsa.
+// @Description Mahasiswa melihat sertifikasinya sendiri; dosen wali melihat milik mahasiswa bimbingannya.
+// @Tags Certifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/certifications/notifications [get]
+func (s *CertificationService) ListCertificationNotifications() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var role, recipientID string
+		switch {
+		case isAdmin(user):
+			return c.Status(403).JSON(fiber.Map{"error": "certification notifications are for students and advisors"})
+		case isStudent(user):
+			student, err := s.StudentRepo.FindByUserID(user.UserID)
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "student profile not found"})
+			}
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			role, recipientID = models.RecipientStudent, student.ID
+		default:
+			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "lecturer profile not found"})
+			}
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			role, recipientID = models.RecipientAdvisor, lecturer.ID
+		}
+
+		notifications, err := s.NotificationRepo.GetByRecipient(role, recipientID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if notifications == nil {
+			notifications = []*models.CertificationNotification{}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":  notifications,
+			"total": len(notifications),
+		})
+	}
+}
+
+// ListExpiringCertifications godoc
+// @Summary List expiring certifications
+// @Description Melihat sertifikasi terverifikasi yang kedaluwarsa dalam N hari ke depan.
+// @Description Dosen wali hanya melihat mahasiswa bimbingannya; admin dapat memfilter dengan advisor_id.
+// @Description expired=true ikut menampilkan sertifikasi yang sudah kedaluwarsa.
+// @Tags Certifications
+// @Accept json
+// @Produce json
+// @Param days query int false "Days ahead (default 30)"
+// @Param program query string false "Program study"
+// @Param advisor_id query string false "Advisor lecturer ID (admin only)"
+// @Param expired query bool false "Include already expired certifications"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/certifications/expiring [get]
+func (s *CertificationService) ListExpiringCertifications() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+		ctx := context.Background()
+
+		within := c.QueryInt("days", DefaultCertificationExpiryDays)
+		if within < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "days must be at least 1"})
+		}
+
+		advisorID := c.Query("advisor_id")
+		if !isAdmin(user) {
+			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
+			if err != nil {
+				return c.Status(404).JSON(fiber.Map{"error": "lecturer profile not found"})
+			}
+			advisorID = lecturer.ID
+		}
+
+		now := time.Now()
+		var after *time.Time
+		if !c.QueryBool("expired", false) {
+			after = &now
+		}
+
+		docs, err := s.MongoRepo.FindCertificationsValidUntil(ctx, after, now.Add(days(within)))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		items := []*models.CertificationValidity{}
+		if len(docs) > 0 {
+			byID := make(map[string]*models.MongoAchievement, len(docs))
+			ids := make([]string, 0, len(docs))
+			for _, doc := range docs {
+				byID[doc.ID.Hex()] = doc
+				ids = append(ids, doc.ID.Hex())
+			}
+
+			certs, err := s.RefRepo.GetVerifiedCertifications(ids, c.Query("program"), advisorID)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			for _, cert := range certs {
+				if doc, ok := byID[cert.MongoAchievementID]; ok {
+					fillCertification(cert, doc, now)
+					items = append(items, cert)
+				}
+			}
+		}
+
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].ValidUntil.Before(*items[j].ValidUntil)
+		})
+
+		expired := 0
+		for _, item := range items {
+			if !item.Valid {
+				expired++
+			}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":    items,
+			"total":   len(items),
+			"expired": expired,
+			"days":    within,
+		})
+	}
+}
+
+// fillCertification melengkapi data sertifikasi dari dokumen Mongo.
+func fillCertification(cert *models.CertificationValidity, doc *models.MongoAchievement, now time.Time) {
+	cert.Title = doc.Title
+	cert.CertificationName = deref(doc.Details.CertificationName)
+	cert.IssuedBy = deref(doc.Details.IssuedBy)
+	cert.ValidUntil = doc.Details.ValidUntil
+	cert.Valid = CertificationValid(cert.ValidUntil, now)
+	if cert.ValidUntil != nil {
+		cert.DaysLeft = int(cert.ValidUntil.Sub(now).Hours() / 24)
+	}
+}
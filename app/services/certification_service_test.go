@@ -0,0 +1,170 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCertificationExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+
+	t.Run("Certification Valid", func(t *testing.T) {
+		past, future := now.Add(-time.Hour), now.Add(time.Hour)
+		assert.True(t, services.CertificationValid(nil, now))
+		assert.True(t, services.CertificationValid(&future, now))
+		assert.False(t, services.CertificationValid(&past, now))
+	})
+
+	t.Run("Check Marks Only Unmarked Verified Certifications", func(t *testing.T) {
+		mongoMock := new(mocks.AchievementMongoMock)
+		refMock := new(mocks.AchievementRefMock)
+		notifyMock := new(mocks.CertificationNotificationMock)
+		service := services.NewCertificationService(mongoMock, refMock, nil, nil, notifyMock)
+
+		oldID, newID := primitive.NewObjectID(), primitive.NewObjectID()
+		mongoMock.On("FindUnmarkedExpiredCertifications", mock.Anything, now).Return([]*models.MongoAchievement{
+			{ID: oldID, AchievementType: "certification"},
+			{ID: newID, AchievementType: "certification"},
+		}, nil).Once()
+		mongoMock.On("FindCertificationsValidUntil", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		marked := now.AddDate(0, 0, -3)
+		refMock.On("GetVerifiedCertifications", []string{oldID.Hex(), newID.Hex()}, "", "").Return([]*models.CertificationValidity{
+			{MongoAchievementID: oldID.Hex(), StudentID: "student-1", ExpiredAt: &marked},
+			{MongoAchievementID: newID.Hex(), StudentID: "student-2"},
+		}, nil).Once()
+		refMock.On("MarkExpiredByMongoIDs", []string{newID.Hex()}, now).Return(nil).Once()
+		mongoMock.On("MarkCertificationsExpired", mock.Anything, []string{oldID.Hex(), newID.Hex()}, now).Return(nil).Once()
+
+		// Notifikasi mahasiswa-1 sudah dibuat pada pemeriksaan sebelumnya.
+		notifyMock.On("Create", mock.MatchedBy(func(n *models.CertificationNotification) bool {
+			return n.StudentID == "student-1"
+		})).Return(false, nil).Once()
+		notifyMock.On("Create", mock.MatchedBy(func(n *models.CertificationNotification) bool {
+			return n.StudentID == "student-2" && n.Kind == models.CertificationExpired
+		})).Return(true, nil).Once()
+
+		result, err := service.CheckExpiry(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Scanned)
+		assert.Equal(t, 1, result.Expired)
+		assert.Equal(t, 1, result.Notified)
+		refMock.AssertExpectations(t)
+		mongoMock.AssertExpectations(t)
+		notifyMock.AssertExpectations(t)
+	})
+
+	t.Run("Check Notifies Student And Advisor Before Expiry", func(t *testing.T) {
+		mongoMock := new(mocks.AchievementMongoMock)
+		refMock := new(mocks.AchievementRefMock)
+		notifyMock := new(mocks.CertificationNotificationMock)
+		service := services.NewCertificationService(mongoMock, refMock, nil, nil, notifyMock)
+
+		soon := now.AddDate(0, 0, 7)
+		id := primitive.NewObjectID()
+		mongoMock.On("FindUnmarkedExpiredCertifications", mock.Anything, now).Return(nil, nil).Once()
+		mongoMock.On("FindCertificationsValidUntil", mock.Anything, &now, now.AddDate(0, 0, services.DefaultCertificationExpiryDays)).Return([]*models.MongoAchievement{
+			{ID: id, AchievementType: "certification", Details: models.AchievementDetails{ValidUntil: &soon}},
+		}, nil).Once()
+		refMock.On("GetVerifiedCertifications", []string{id.Hex()}, "", "").Return([]*models.CertificationValidity{
+			{MongoAchievementID: id.Hex(), StudentID: "student-1", AdvisorID: str("lecturer-1")},
+		}, nil).Once()
+
+		var recipients []string
+		notifyMock.On("Create", mock.MatchedBy(func(n *models.CertificationNotification) bool {
+			return n.Kind == models.CertificationExpiring && n.ValidUntil.Equal(soon)
+		})).Run(func(args mock.Arguments) {
+			n := args.Get(0).(*models.CertificationNotification)
+			recipients = append(recipients, n.RecipientRole+":"+n.RecipientID)
+		}).Return(true, nil).Twice()
+
+		result, err := service.CheckExpiry(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Expired)
+		assert.Equal(t, 2, result.Notified)
+		assert.ElementsMatch(t, []string{"student:student-1", "advisor:lecturer-1"}, recipients)
+		refMock.AssertNotCalled(t, "MarkExpiredByMongoIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Notifications - Student Sees Own", func(t *testing.T) {
+		studentMock := new(mocks.StudentRepoMock)
+		notifyMock := new(mocks.CertificationNotificationMock)
+		service := services.NewCertificationService(nil, nil, nil, studentMock, notifyMock)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user", &models.JWTClaims{UserID: "user-student", Role: "Mahasiswa"})
+			return c.Next()
+		})
+		app.Get("/certifications/notifications", service.ListCertificationNotifications())
+
+		studentMock.On("FindByUserID", "user-student").Return(&models.Student{ID: "student-1"}, nil)
+		notifyMock.On("GetByRecipient", models.RecipientStudent, "student-1").Return([]*models.CertificationNotification{
+			{MongoAchievementID: "cert-1", StudentID: "student-1", Kind: models.CertificationExpiring},
+		}, nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/certifications/notifications", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Total int `json:"total"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Equal(t, 1, result.Total)
+		notifyMock.AssertExpectations(t)
+	})
+
+	t.Run("List Expiring - Advisor Scope", func(t *testing.T) {
+		mongoMock := new(mocks.AchievementMongoMock)
+		refMock := new(mocks.AchievementRefMock)
+		lecturerMock := new(mocks.LecturerRepoMock)
+		service := services.NewCertificationService(mongoMock, refMock, lecturerMock, nil, nil)
+
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user", &models.JWTClaims{UserID: "user-advisor", Role: "Dosen Wali"})
+			return c.Next()
+		})
+		app.Get("/certifications/expiring", service.ListExpiringCertifications())
+
+		soon := time.Now().AddDate(0, 0, 10)
+		later := time.Now().AddDate(0, 0, 20)
+		soonID, laterID := primitive.NewObjectID(), primitive.NewObjectID()
+
+		lecturerMock.On("FindByUserID", "user-advisor").Return(&models.Lecturer{ID: "lecturer-1"}, nil)
+		mongoMock.On("FindCertificationsValidUntil", mock.Anything, mock.Anything, mock.Anything).Return([]*models.MongoAchievement{
+			{ID: laterID, Title: "AWS", Details: models.AchievementDetails{CertificationName: str("AWS SAA"), ValidUntil: &later}},
+			{ID: soonID, Title: "CCNA", Details: models.AchievementDetails{CertificationName: str("CCNA"), ValidUntil: &soon}},
+		}, nil).Once()
+		refMock.On("GetVerifiedCertifications", mock.Anything, "Informatika", "lecturer-1").Return([]*models.CertificationValidity{
+			{MongoAchievementID: laterID.Hex(), StudentID: "student-1"},
+			{MongoAchievementID: soonID.Hex(), StudentID: "student-2"},
+		}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/certifications/expiring?days=30&program=Informatika&advisor_id=lecturer-9", nil)
+		resp, _ := app.Test(req)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data  []models.CertificationValidity `json:"data"`
+			Total int                            `json:"total"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, "CCNA", result.Data[0].CertificationName)
+		assert.True(t, result.Data[0].Valid)
+		assert.Equal(t, "student-1", result.Data[1].StudentID)
+	})
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// CommentEditWindow adalah batas waktu penulis boleh mengubah komentarnya.
+const CommentEditWindow = 15 * time.Minute
+
+type CommentService struct {
+	CommentRepo repositories.IAchievementCommentRepository
+	RefRepo     repositories.IAchievementReferenceRepo
+	Access      *AchievementAccess
+}
+
+func NewCommentService(
+	comment repositories.IAchievementCommentRepository,
+	ref repositories.IAchievementReferenceRepo,
+	access *AchievementAccess,
+) *CommentService {
+	return &CommentService{
+		CommentRepo: comment,
+		RefRepo:     ref,
+		Access:      access,
+	}
+}
+
+// ListComments godoc
+// @Summary List achievement comments
+// @Description Melihat diskusi (thread) antara mahasiswa dan dosen wali pada prestasi
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/comments [get]
+func (s *CommentService) ListComments() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		if err := s.authorize(user, id); err != nil {
+			return s.denied(c, err)
+		}
+
+		comments, err := s.CommentRepo.GetByMongoID(id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		lastRead, err := s.CommentRepo.GetLastRead(id, user.UserID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		threads, unread := buildCommentThreads(comments, user.UserID, lastRead)
+
+		return c.JSON(fiber.Map{
+			"data":         threads,
+			"total":        len(comments),
+			"unread_count": unread,
+		})
+	}
+}
+
+// CreateComment godoc
+// @Summary Create achievement comment
+// @Description Menambahkan komentar atau balasan pada prestasi
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param comment body object true "Comment payload {\"body\": \"...\", \"parent_id\": \"...\"}"
+// @Success 201 {object} models.AchievementComment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/comments [post]
+func (s *CommentService) CreateComment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var payload struct {
+			Body     string  `json:"body"`
+			ParentID *string `json:"parent_id"`
+		}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		payload.Body = strings.TrimSpace(payload.Body)
+		if payload.Body == "" {
+			return c.Status(400).JSON(fiber.Map{"error": "body is required"})
+		}
+
+		if err := s.authorize(user, id); err != nil {
+			return s.denied(c, err)
+		}
+
+		if payload.ParentID != nil {
+			parent, err := s.CommentRepo.GetByID(*payload.ParentID)
+			if err != nil || parent.MongoAchievementID != id {
+				return c.Status(400).JSON(fiber.Map{"error": "parent comment not found"})
+			}
+		}
+
+		comment := &models.AchievementComment{
+			MongoAchievementID: id,
+			ParentID:           payload.ParentID,
+			AuthorID:           user.UserID,
+			AuthorRole:         user.Role,
+			Body:               payload.Body,
+		}
+
+		if err := s.CommentRepo.Create(comment); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// Komentar sendiri otomatis dianggap sudah dibaca.
+		_ = s.CommentRepo.MarkRead(id, user.UserID, comment.CreatedAt)
+
+		return c.Status(201).JSON(comment)
+	}
+}
+
+// UpdateComment godoc
+// @Summary Edit achievement comment
+// @Description Penulis mengubah komentarnya selama masih dalam batas waktu edit
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param commentId path string true "Comment ID"
+// @Param comment body object true "Comment payload {\"body\": \"...\"}"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/comments/{commentId} [put]
+func (s *CommentService) UpdateComment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		commentID := c.Params("commentId")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var payload struct {
+			Body string `json:"body"`
+		}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		payload.Body = strings.TrimSpace(payload.Body)
+		if payload.Body == "" {
+			return c.Status(400).JSON(fiber.Map{"error": "body is required"})
+		}
+
+		comment, err := s.CommentRepo.GetByID(commentID)
+		if err != nil || comment.MongoAchievementID != id {
+			return c.Status(404).JSON(fiber.Map{"error": "comment not found"})
+		}
+
+		if comment.AuthorID != user.UserID {
+			return c.Status(403).JSON(fiber.Map{"error": "only the author can edit this comment"})
+		}
+
+		if time.Since(comment.CreatedAt) > CommentEditWindow {
+			return c.Status(409).JSON(fiber.Map{"error": "edit window has passed"})
+		}
+
+		if err := s.CommentRepo.UpdateBody(commentID, payload.Body); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+// MarkCommentsRead godoc
+// @Summary Mark achievement comments as read
+// @Description Menandai semua komentar pada prestasi sudah dibaca oleh user
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/{id}/comments/read [post]
+func (s *CommentService) MarkCommentsRead() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		if err := s.authorize(user, id); err != nil {
+			return s.denied(c, err)
+		}
+
+		if err := s.CommentRepo.MarkRead(id, user.UserID, time.Now()); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "marked as read"})
+	}
+}
+
+func (s *CommentService) authorize(user *models.JWTClaims, mongoID string) error {
+	ref, err := s.RefRepo.GetByMongoID(mongoID)
+	if err != nil {
+		return errAchievementNotFound
+	}
+	return s.Access.CanView(user, ref.StudentID)
+}
+
+func (s *CommentService) denied(c *fiber.Ctx, err error) error {
+	if err == errAchievementNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": "achievement not found"})
+	}
+	return accessDenied(c, err)
+}
+
+// buildCommentThreads menyusun komentar datar menjadi pohon balasan dan
+// menghitung komentar orang lain yang dibuat setelah penanda baca user.
+func buildCommentThreads(
+	comments []*models.AchievementComment,
+	userID string,
+	lastRead *time.Time,
+) ([]*models.CommentThread, int) {
+	threads := []*models.CommentThread{}
+	byID := map[string]*models.CommentThread{}
+	unread := 0
+
+	for _, cm := range comments {
+		node := &models.CommentThread{
+			AchievementComment: *cm,
+			Replies:            []*models.CommentThread{},
+		}
+		if cm.AuthorID != userID && (lastRead == nil || cm.CreatedAt.After(*lastRead)) {
+			node.Unread = true
+			unread++
+		}
+		byID[cm.ID] = node
+
+		if cm.ParentID != nil {
+			if parent, ok := byID[*cm.ParentID]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		threads = append(threads, node)
+	}
+
+	return threads, unread
+}
@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCommentService(t *testing.T) {
+	commentMock := new(mocks.AchievementCommentMock)
+	refMock := new(mocks.AchievementRefMock)
+	studentMock := new(mocks.StudentRepoMock)
+	lecturerMock := new(mocks.LecturerRepoMock)
+	service := services.NewCommentService(
+		commentMock,
+		refMock,
+		services.NewAchievementAccess(studentMock, lecturerMock, nil),
+	)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &models.JWTClaims{UserID: "user-owner", Role: "Mahasiswa"})
+		return c.Next()
+	})
+	app.Get("/achievement/:id/comments", service.ListComments())
+	app.Post("/achievement/:id/comments", service.CreateComment())
+	app.Put("/achievement/:id/comments/:commentId", service.UpdateComment())
+
+	id := "ach-1"
+	refMock.On("GetByMongoID", id).Return(&models.AchievementReference{MongoAchievementID: id, StudentID: "student-1"}, nil)
+	studentMock.On("FindByUserID", "user-owner").Return(&models.Student{ID: "student-1"}, nil)
+
+	t.Run("List - Threads And Unread", func(t *testing.T) {
+		lastRead := time.Now().Add(-time.Hour)
+		parentID := "c1"
+		comments := []*models.AchievementComment{
+			{ID: "c1", MongoAchievementID: id, AuthorID: "user-advisor", CreatedAt: lastRead.Add(-time.Minute)},
+			{ID: "c2", MongoAchievementID: id, ParentID: &parentID, AuthorID: "user-owner", CreatedAt: lastRead.Add(time.Minute)},
+			{ID: "c3", MongoAchievementID: id, ParentID: &parentID, AuthorID: "user-advisor", CreatedAt: lastRead.Add(2 * time.Minute)},
+		}
+		commentMock.On("GetByMongoID", id).Return(comments, nil).Once()
+		commentMock.On("GetLastRead", id, "user-owner").Return(&lastRead, nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/achievement/"+id+"/comments", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Len(t, result["data"], 1)
+		assert.Equal(t, float64(1), result["unread_count"])
+
+		root := result["data"].([]interface{})[0].(map[string]interface{})
+		assert.Len(t, root["replies"], 2)
+	})
+
+	t.Run("Create - Success", func(t *testing.T) {
+		commentMock.On("Create", mock.Anything).Return(nil).Once()
+		commentMock.On("MarkRead", id, "user-owner", mock.Anything).Return(nil).Once()
+
+		body, _ := json.Marshal(map[string]string{"body": "Sertifikat sudah saya lampirkan"})
+		req := httptest.NewRequest("POST", "/achievement/"+id+"/comments", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		assert.Equal(t, 201, resp.StatusCode)
+	})
+
+	t.Run("Update - Edit Window Passed", func(t *testing.T) {
+		old := &models.AchievementComment{
+			ID:                 "c9",
+			MongoAchievementID: id,
+			AuthorID:           "user-owner",
+			CreatedAt:          time.Now().Add(-services.CommentEditWindow - time.Minute),
+		}
+		commentMock.On("GetByID", "c9").Return(old, nil).Once()
+
+		body, _ := json.Marshal(map[string]string{"body": "ralat"})
+		req := httptest.NewRequest("PUT", "/achievement/"+id+"/comments/c9", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("Update - Not Author", func(t *testing.T) {
+		other := &models.AchievementComment{
+			ID:                 "c10",
+			MongoAchievementID: id,
+			AuthorID:           "user-advisor",
+			CreatedAt:          time.Now(),
+		}
+		commentMock.On("GetByID", "c10").Return(other, nil).Once()
+
+		body, _ := json.Marshal(map[string]string{"body": "ralat"})
+		req := httptest.NewRequest("PUT", "/achievement/"+id+"/comments/c10", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
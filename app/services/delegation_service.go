@@ -0,0 +1,200 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+type DelegationService struct {
+	DelegationRepo repositories.IVerificationDelegationRepository
+	LecturerRepo   repositories.ILecturerRepository
+}
+
+func NewDelegationService(
+	delegation repositories.IVerificationDelegationRepository,
+	lecturer repositories.ILecturerRepository,
+) *DelegationService {
+	return &DelegationService{
+		DelegationRepo: delegation,
+		LecturerRepo:   lecturer,
+	}
+}
+
+// ListDelegations godoc
+// @Summary List verification delegations
+// @Description Dosen melihat delegasi yang ia berikan atau terima; admin melihat semua (opsional ?lecturer_id=)
+// @Tags Delegations
+// @Accept json
+// @Produce json
+// @Param lecturer_id query string false "Lecturer ID (admin only)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /delegations [get]
+func (s *DelegationService) ListDelegations() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		lecturerID := c.Query("lecturer_id")
+		if !isAdmin(user) {
+			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
+			if err != nil {
+				return c.Status(404).JSON(fiber.Map{"error": "lecturer profile not found"})
+			}
+			lecturerID = lecturer.ID
+		}
+
+		delegations, err := s.DelegationRepo.FindByLecturer(lecturerID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		now := time.Now()
+		data := make([]fiber.Map, 0, len(delegations))
+		for _, d := range delegations {
+			data = append(data, fiber.Map{
+				"delegation": d,
+				"active":     d.IsActive(now),
+			})
+		}
+
+		return c.JSON(fiber.Map{"data": data})
+	}
+}
+
+// CreateDelegation godoc
+// @Summary Delegate verification rights
+// @Description Dosen wali mendelegasikan hak verifikasi atas mahasiswa bimbingannya ke dosen lain untuk rentang waktu tertentu.
+// @Description Admin dapat membuat delegasi atas nama dosen dengan advisor_id.
+// @Tags Delegations
+// @Accept json
+// @Produce json
+// @Param delegation body object true "{\"advisor_id\": \"...\", \"delegate_id\": \"...\", \"starts_at\": \"...\", \"ends_at\": \"...\", \"reason\": \"...\"}"
+// @Success 201 {object} models.VerificationDelegation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /delegations [post]
+func (s *DelegationService) CreateDelegation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		var payload struct {
+			AdvisorID  string    `json:"advisor_id"`
+			DelegateID string    `json:"delegate_id"`
+			StartsAt   time.Time `json:"starts_at"`
+			EndsAt     time.Time `json:"ends_at"`
+			Reason     string    `json:"reason"`
+		}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if !isAdmin(user) {
+			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
+			if err != nil {
+				return c.Status(404).JSON(fiber.Map{"error": "lecturer profile not found"})
+			}
+			payload.AdvisorID = lecturer.ID
+		}
+
+		if payload.AdvisorID == "" || payload.DelegateID == "" {
+			return c.Status(400).JSON(fiber.Map{"error": "advisor_id and delegate_id are required"})
+		}
+		if payload.AdvisorID == payload.DelegateID {
+			return c.Status(400).JSON(fiber.Map{"error": "cannot delegate to yourself"})
+		}
+		if payload.StartsAt.IsZero() || payload.EndsAt.IsZero() {
+			return c.Status(400).JSON(fiber.Map{"error": "starts_at and ends_at are required"})
+		}
+		if !payload.EndsAt.After(payload.StartsAt) {
+			return c.Status(400).JSON(fiber.Map{"error": "ends_at must be after starts_at"})
+		}
+		if !payload.EndsAt.After(time.Now()) {
+			return c.Status(400).JSON(fiber.Map{"error": "ends_at must be in the future"})
+		}
+
+		if _, err := s.LecturerRepo.FindByID(payload.AdvisorID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "advisor not found"})
+		}
+		if _, err := s.LecturerRepo.FindByID(payload.DelegateID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "delegate lecturer not found"})
+		}
+
+		overlap, err := s.DelegationRepo.HasOverlap(payload.AdvisorID, payload.StartsAt, payload.EndsAt)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if overlap {
+			return c.Status(409).JSON(fiber.Map{"error": "an active delegation already covers this period"})
+		}
+
+		delegation := &models.VerificationDelegation{
+			AdvisorID:  payload.AdvisorID,
+			DelegateID: payload.DelegateID,
+			StartsAt:   payload.StartsAt,
+			EndsAt:     payload.EndsAt,
+			Reason:     strings.TrimSpace(payload.Reason),
+			CreatedBy:  user.UserID,
+		}
+		if err := s.DelegationRepo.Create(delegation); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.Status(201).JSON(delegation)
+	}
+}
+
+// RevokeDelegation godoc
+// @Summary Revoke verification delegation
+// @Description Dosen wali pemberi delegasi (atau admin) mencabut delegasi
+// @Tags Delegations
+// @Accept json
+// @Produce json
+// @Param id path string true "Delegation ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /delegations/{id} [delete]
+func (s *DelegationService) RevokeDelegation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		user := c.Locals("user").(*models.JWTClaims)
+
+		delegation, err := s.DelegationRepo.FindByID(id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "delegation not found"})
+		}
+
+		if !isAdmin(user) {
+			lecturer, err := s.LecturerRepo.FindByUserID(user.UserID)
+			if err != nil || lecturer.ID != delegation.AdvisorID {
+				return c.Status(403).JSON(fiber.Map{"error": "forbidden"})
+			}
+		}
+
+		if delegation.RevokedAt != nil {
+			return c.Status(409).JSON(fiber.Map{"error": "delegation already revoked"})
+		}
+
+		if err := s.DelegationRepo.Revoke(id, time.Now()); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "revoked"})
+	}
+}
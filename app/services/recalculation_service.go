@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+const defaultRecalculationBatchSize = 100
+
+type RecalculationService struct {
+	MongoRepo  repositories.IAchievementMongoRepository
+	RefRepo    repositories.IAchievementReferenceRepo
+	RubricRepo repositories.IRubricRepository
+	TypeRepo   repositories.IAchievementTypeRepository
+	PolicyRepo repositories.ITeamPointPolicyRepository
+}
+
+func NewRecalculationService(
+	mongo repositories.IAchievementMongoRepository,
+	ref repositories.IAchievementReferenceRepo,
+	rubric repositories.IRubricRepository,
+	types repositories.IAchievementTypeRepository,
+	policy repositories.ITeamPointPolicyRepository,
+) *RecalculationService {
+	return &RecalculationService{
+		MongoRepo:  mongo,
+		RefRepo:    ref,
+		RubricRepo: rubric,
+		TypeRepo:   types,
+		PolicyRepo: policy,
+	}
+}
+
+// RecalculatePoints godoc
+// @Summary Recalculate achievement points
+// @Description Admin menghitung ulang poin prestasi terverifikasi dengan rubrik terbaru.
+// @Description Default dryRun=true hanya mengembalikan diff tanpa menyimpan.
+// @Tags Rubric
+// @Accept json
+// @Produce json
+// @Param body body models.PointsRecalculationRequest true "Recalculation filter"
+// @Success 200 {object} models.PointsRecalculationReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /rubrics/recalculate [post]
+func (s *RecalculationService) RecalculatePoints() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var req models.PointsRecalculationRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		if req.From != nil && req.To != nil && req.From.After(*req.To) {
+			return c.Status(400).JSON(fiber.Map{"error": "from must be before to"})
+		}
+
+		report, err := s.Recalculate(context.Background(), req)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(report)
+	}
+}
+
+// Recalculate menghitung diff poin lama vs rubrik terbaru lalu, jika bukan
+// dry run, menyimpannya per batch. Poin yang diterima setiap anggota
+// (achievement_references.points) ikut dihitung ulang sesuai kebijakan poin
+// tim. Prestasi dengan override dosen dilewati kecuali IncludeOverridden
+// diset.
+func (s *RecalculationService) Recalculate(
+	ctx context.Context,
+	req models.PointsRecalculationRequest,
+) (*models.PointsRecalculationReport, error) {
+
+	dryRun := req.DryRun == nil || *req.DryRun
+	batchSize := req.BatchSize
+	if batchSize < 1 {
+		batchSize = defaultRecalculationBatchSize
+	}
+
+	report := &models.PointsRecalculationReport{
+		DryRun:   dryRun,
+		Changes:  []models.PointsChange{},
+		Students: []*models.StudentPointsChange{},
+	}
+
+	refs, err := s.RefRepo.GetVerifiedByFilter(req.From, req.To, req.ProgramStudy)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.findDocs(ctx, refs, req.AchievementType)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.RubricRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := s.TypeRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	rules = withTypeDefaults(rules, defs...)
+
+	policies, err := s.teamPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	byStudent := map[string]*models.StudentPointsChange{}
+	pending := map[string]int{}
+	awards := map[[2]string]int{}
+
+	for _, ref := range refs {
+		doc, ok := docs[ref.MongoAchievementID]
+		if !ok {
+			if req.AchievementType == "" {
+				report.Missing++
+			}
+			continue
+		}
+		report.Scanned++
+
+		st, ok := byStudent[ref.StudentID]
+		if !ok {
+			st = &models.StudentPointsChange{StudentID: ref.StudentID}
+			byStudent[ref.StudentID] = st
+		}
+
+		oldAwarded := doc.Points
+		if ref.Points != nil {
+			oldAwarded = *ref.Points
+		}
+
+		newPoints := ScoreAchievement(rules, doc).Points
+		newAwarded := AwardPoints(policies[doc.AchievementType], newPoints, ref.TeamSize)
+		if ref.PointsJustification != nil && !req.IncludeOverridden {
+			newPoints = doc.Points
+			newAwarded = oldAwarded
+			report.SkippedOverridden++
+		}
+
+		st.OldTotal += oldAwarded
+		st.NewTotal += newAwarded
+		if newAwarded != oldAwarded {
+			awards[[2]string{ref.MongoAchievementID, ref.StudentID}] = newAwarded
+		}
+
+		if newPoints == doc.Points {
+			report.Unchanged++
+			continue
+		}
+
+		report.Changed++
+		st.Changed++
+		report.Changes = append(report.Changes, models.PointsChange{
+			AchievementID: ref.MongoAchievementID,
+			StudentID:     ref.StudentID,
+			OldPoints:     doc.Points,
+			NewPoints:     newPoints,
+		})
+		// Poin dokumen hanya mengikuti referensi pemilik entri, sama
+		// seperti saat verifikasi.
+		if ref.TeamRole != models.TeamRoleMember {
+			pending[ref.MongoAchievementID] = newPoints
+		}
+	}
+
+	for _, st := range byStudent {
+		st.Delta = st.NewTotal - st.OldTotal
+		if st.Delta != 0 {
+			report.StudentsChanged++
+		}
+		if st.Changed > 0 {
+			report.Students = append(report.Students, st)
+		}
+	}
+	sort.Slice(report.Students, func(i, j int) bool {
+		return report.Students[i].StudentID < report.Students[j].StudentID
+	})
+
+	if dryRun {
+		return report, nil
+	}
+
+	batch := map[string]int{}
+	var scored []models.PointsChange
+	flush := func() error {
+		if len(scored) == 0 {
+			return nil
+		}
+		if len(batch) > 0 {
+			if err := s.MongoRepo.UpdatePointsBatch(ctx, batch); err != nil {
+				return err
+			}
+		}
+		// Poin usulan disimpan per anggota sehingga justifikasi override
+		// anggota lain pada prestasi yang sama tidak ikut terhapus.
+		for _, change := range scored {
+			if err := s.RefRepo.UpdateScoringByMongoID(change.AchievementID, change.StudentID, change.NewPoints, nil); err != nil {
+				return err
+			}
+		}
+		report.Batches++
+		batch = map[string]int{}
+		scored = nil
+		return nil
+	}
+
+	for _, change := range report.Changes {
+		if points, ok := pending[change.AchievementID]; ok {
+			batch[change.AchievementID] = points
+		}
+		scored = append(scored, change)
+		if len(scored) >= batchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	for key, points := range awards {
+		if err := s.RefRepo.UpdateAwardedPoints(key[0], key[1], points); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// teamPolicies memetakan tipe prestasi ke kebijakan poin timnya. Tipe yang
+// tidak terdaftar bernilai kosong dan diperlakukan AwardPoints sebagai
+// duplicate.
+func (s *RecalculationService) teamPolicies() (map[string]string, error) {
+	policies := map[string]string{}
+	if s.PolicyRepo == nil {
+		return policies, nil
+	}
+
+	found, err := s.PolicyRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range found {
+		policies[p.AchievementType] = p.Policy
+	}
+	return policies, nil
+}
+
+func (s *RecalculationService) findDocs(
+	ctx context.Context,
+	refs []*models.AchievementReference,
+	achievementType string,
+) (map[string]*models.MongoAchievement, error) {
+
+	docs := map[string]*models.MongoAchievement{}
+	if len(refs) == 0 {
+		return docs, nil
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.MongoAchievementID)
+	}
+
+	found, err := s.MongoRepo.FindByIDsAndType(ctx, ids, achievementType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range found {
+		docs[doc.ID.Hex()] = doc
+	}
+	return docs, nil
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRecalculatePoints(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	rubricMock := new(mocks.RubricRepoMock)
+	typeMock := new(mocks.AchievementTypeRepoMock)
+	service := services.NewRecalculationService(mongoMock, refMock, rubricMock, typeMock, nil)
+
+	oid1, oid2, oid3 := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	justification := "juara umum"
+	refs := []*models.AchievementReference{
+		{StudentID: "s1", MongoAchievementID: oid1.Hex()},
+		{StudentID: "s1", MongoAchievementID: oid2.Hex()},
+		{StudentID: "s2", MongoAchievementID: oid3.Hex(), PointsJustification: &justification},
+	}
+	docs := []*models.MongoAchievement{
+		{ID: oid1, AchievementType: "competition", Points: 50},
+		{ID: oid2, AchievementType: "competition", Points: 80},
+		{ID: oid3, AchievementType: "competition", Points: 200},
+	}
+
+	refMock.On("GetVerifiedByFilter", mock.Anything, mock.Anything, "").Return(refs, nil)
+	mongoMock.On("FindByIDsAndType", mock.Anything, mock.Anything, mock.Anything).Return(docs, nil)
+	rubricMock.On("FindAll").Return([]*models.RubricRule{
+		{AchievementType: "competition", Field: "base", Points: 80},
+	}, nil)
+	typeMock.On("FindAll").Return([]*models.AchievementTypeDefinition{}, nil)
+
+	t.Run("Dry Run", func(t *testing.T) {
+		report, err := service.Recalculate(context.Background(), models.PointsRecalculationRequest{})
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 3, report.Scanned)
+		assert.Equal(t, 1, report.Changed)
+		assert.Equal(t, 1, report.SkippedOverridden)
+		assert.Equal(t, 1, report.StudentsChanged)
+		assert.Equal(t, 30, report.Students[0].Delta)
+		mongoMock.AssertNotCalled(t, "UpdatePointsBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Apply In Batches", func(t *testing.T) {
+		dryRun := false
+		mongoMock.On("UpdatePointsBatch", mock.Anything, map[string]int{oid1.Hex(): 80}).Return(nil).Once()
+		mongoMock.On("UpdatePointsBatch", mock.Anything, map[string]int{oid3.Hex(): 80}).Return(nil).Once()
+		refMock.On("UpdateScoringByMongoID", mock.Anything, mock.Anything, 80, (*string)(nil)).Return(nil)
+		refMock.On("UpdateAwardedPoints", oid1.Hex(), "s1", 80).Return(nil).Once()
+		refMock.On("UpdateAwardedPoints", oid3.Hex(), "s2", 80).Return(nil).Once()
+
+		report, err := service.Recalculate(context.Background(), models.PointsRecalculationRequest{
+			DryRun:            &dryRun,
+			BatchSize:         1,
+			IncludeOverridden: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Changed)
+		assert.Equal(t, 2, report.Batches)
+		mongoMock.AssertExpectations(t)
+	})
+}
+
+func TestRecalculateTeamAwardedPoints(t *testing.T) {
+	mongoMock := new(mocks.AchievementMongoMock)
+	refMock := new(mocks.AchievementRefMock)
+	rubricMock := new(mocks.RubricRepoMock)
+	typeMock := new(mocks.AchievementTypeRepoMock)
+	policyMock := new(mocks.TeamPointPolicyRepoMock)
+	service := services.NewRecalculationService(mongoMock, refMock, rubricMock, typeMock, policyMock)
+
+	oid := primitive.NewObjectID()
+	awarded := 50
+	refMock.On("GetVerifiedByFilter", mock.Anything, mock.Anything, "").Return([]*models.AchievementReference{
+		{StudentID: "s1", MongoAchievementID: oid.Hex(), TeamSize: 2, Points: &awarded, TeamRole: models.TeamRoleOwner},
+		{StudentID: "s2", MongoAchievementID: oid.Hex(), TeamSize: 2, Points: &awarded, TeamRole: models.TeamRoleMember},
+	}, nil)
+	mongoMock.On("FindByIDsAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*models.MongoAchievement{
+		{ID: oid, AchievementType: "competition", Points: 100},
+	}, nil)
+	rubricMock.On("FindAll").Return([]*models.RubricRule{
+		{AchievementType: "competition", Field: "base", Points: 80},
+	}, nil)
+	typeMock.On("FindAll").Return([]*models.AchievementTypeDefinition{}, nil)
+	policyMock.On("FindAll").Return([]*models.TeamPointPolicy{
+		{AchievementType: "competition", Policy: models.TeamPointPolicySplit},
+	}, nil)
+
+	dryRun := false
+	mongoMock.On("UpdatePointsBatch", mock.Anything, map[string]int{oid.Hex(): 80}).Return(nil).Once()
+	refMock.On("UpdateScoringByMongoID", oid.Hex(), "s1", 80, (*string)(nil)).Return(nil).Once()
+	refMock.On("UpdateScoringByMongoID", oid.Hex(), "s2", 80, (*string)(nil)).Return(nil).Once()
+	refMock.On("UpdateAwardedPoints", oid.Hex(), "s1", 40).Return(nil).Once()
+	refMock.On("UpdateAwardedPoints", oid.Hex(), "s2", 40).Return(nil).Once()
+
+	report, err := service.Recalculate(context.Background(), models.PointsRecalculationRequest{DryRun: &dryRun})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.StudentsChanged)
+	for _, st := range report.Students {
+		assert.Equal(t, 50, st.OldTotal)
+		assert.Equal(t, 40, st.NewTotal)
+	}
+	refMock.AssertExpectations(t)
+	mongoMock.AssertExpectations(t)
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// ReconciliationService mencocokkan achievement_references dengan dokumen
+// Mongo prestasi. Postgres menjadi acuan: dokumen tanpa referensi dibuatkan
+// referensi draft bila pemiliknya dikenal, referensi tanpa dokumen dipindah
+// ke tempat sampah, dan status hapus dokumen disamakan dengan referensinya.
+type ReconciliationService struct {
+	MongoRepo   repositories.IAchievementMongoRepository
+	RefRepo     repositories.IAchievementReferenceRepo
+	StudentRepo repositories.IStudentRepository
+	HistoryRepo repositories.IAchievementHistoryRepository
+}
+
+func NewReconciliationService(
+	mongo repositories.IAchievementMongoRepository,
+	ref repositories.IAchievementReferenceRepo,
+	student repositories.IStudentRepository,
+	history repositories.IAchievementHistoryRepository,
+) *ReconciliationService {
+	return &ReconciliationService{
+		MongoRepo:   mongo,
+		RefRepo:     ref,
+		StudentRepo: student,
+		HistoryRepo: history,
+	}
+}
+
+// ReconcileSystemActor dicatat sebagai pelaku riwayat status perbaikan yang
+// dijalankan dari command line.
+var ReconcileSystemActor = &models.JWTClaims{UserID: uuid.Nil.String(), Role: "system"}
+
+// reconcileNote menjelaskan baris riwayat status yang dibuat perbaikan.
+const reconcileNote = "reconciliation repair"
+
+// ReconcileAchievements godoc
+// @Summary Reconcile achievement stores
+// @Description Admin mencocokkan achievement_references dengan dokumen MongoDB dan melaporkan
+// @Description referensi tanpa dokumen, dokumen tanpa referensi, dan status hapus yang berbeda.
+// @Description repair=true memperbaiki yang bisa diperbaiki: membuat referensi draft, soft delete
+// @Description dokumen yatim atau referensi tanpa dokumen, atau menyamakan status hapus dokumen
+// @Description dengan referensinya.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Param body body models.ReconciliationRequest false "Reconciliation options"
+// @Success 200 {object} models.ReconciliationReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/reconcile [post]
+func (s *ReconciliationService) ReconcileAchievements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var req models.ReconciliationRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		user := c.Locals("user").(*models.JWTClaims)
+
+		report, err := s.Reconcile(context.Background(), user, req.Repair)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(report)
+	}
+}
+
+// Reconcile memindai kedua penyimpanan dan, jika repair, memperbaiki setiap
+// ketidakcocokan yang punya Action atas nama actor. Kegagalan satu perbaikan
+// dicatat pada issue-nya tanpa menghentikan yang lain. Dokumen tanpa referensi yang
+// lebih muda dari OutboxCompensationDelay dilewati karena create-nya bisa
+// masih berjalan; jika create gagal, relay outbox yang membuangnya.
+func (s *ReconciliationService) Reconcile(
+	ctx context.Context,
+	actor *models.JWTClaims,
+	repair bool,
+) (*models.ReconciliationReport, error) {
+	now := time.Now()
+
+	refs, err := s.RefRepo.GetAllWithDeleted()
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.MongoRepo.FindAllWithDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconciliationReport{
+		Repair:            repair,
+		ReferencesScanned: len(refs),
+		DocumentsScanned:  len(docs),
+		Issues:            []*models.ReconciliationIssue{},
+	}
+
+	byDoc := map[string][]*models.AchievementReference{}
+	for _, ref := range refs {
+		byDoc[ref.MongoAchievementID] = append(byDoc[ref.MongoAchievementID], ref)
+	}
+
+	seen := map[string]*models.MongoAchievement{}
+	for _, doc := range docs {
+		id := doc.ID.Hex()
+		seen[id] = doc
+
+		team, ok := byDoc[id]
+		if !ok {
+			if now.Sub(doc.CreatedAt) < OutboxCompensationDelay {
+				report.RecentSkipped++
+				continue
+			}
+			report.Issues = append(report.Issues, s.orphanIssue(doc))
+			continue
+		}
+
+		owner := teamOwner(team)
+		refDeleted := owner.Status == models.AchievementStatusDeleted
+		if refDeleted == (doc.DeletedAt != nil) {
+			continue
+		}
+
+		issue := &models.ReconciliationIssue{
+			Kind:               models.ReconcileDeletedMismatch,
+			MongoAchievementID: id,
+			StudentID:          owner.StudentID,
+			Title:              doc.Title,
+			ReferenceStatus:    owner.Status,
+			DocumentDeleted:    doc.DeletedAt != nil,
+			Action:             models.ReconcileActionRestoreDocument,
+		}
+		if refDeleted {
+			issue.Action = models.ReconcileActionSoftDeleteDocument
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	for id, team := range byDoc {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		owner := teamOwner(team)
+		issue := &models.ReconciliationIssue{
+			Kind:               models.ReconcileMissingDocument,
+			MongoAchievementID: id,
+			StudentID:          owner.StudentID,
+			ReferenceStatus:    owner.Status,
+		}
+		// Referensi di tempat sampah dibersihkan oleh purge.
+		if owner.Status != models.AchievementStatusDeleted {
+			issue.Action = models.ReconcileActionSoftDeleteReference
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Kind != report.Issues[j].Kind {
+			return report.Issues[i].Kind < report.Issues[j].Kind
+		}
+		return report.Issues[i].MongoAchievementID < report.Issues[j].MongoAchievementID
+	})
+
+	for _, issue := range report.Issues {
+		switch issue.Kind {
+		case models.ReconcileMissingDocument:
+			report.MissingDocuments++
+		case models.ReconcileOrphanDocument:
+			report.OrphanDocuments++
+		case models.ReconcileDeletedMismatch:
+			report.DeletedMismatches++
+		}
+
+		if !repair || issue.Action == "" {
+			continue
+		}
+		if err := s.repair(ctx, actor, issue, seen[issue.MongoAchievementID], byDoc[issue.MongoAchievementID]); err != nil {
+			issue.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		issue.Repaired = true
+		report.Repaired++
+	}
+
+	return report, nil
+}
+
+// orphanIssue menentukan perbaikan dokumen tanpa referensi. Dokumen yang
+// pemiliknya masih terdaftar dibuatkan referensi draft; selain itu dokumen
+// di-soft delete. Dokumen yatim yang sudah terhapus tidak perlu diperbaiki.
+func (s *ReconciliationService) orphanIssue(doc *models.MongoAchievement) *models.ReconciliationIssue {
+	issue := &models.ReconciliationIssue{
+		Kind:               models.ReconcileOrphanDocument,
+		MongoAchievementID: doc.ID.Hex(),
+		StudentID:          doc.StudentID,
+		Title:              doc.Title,
+		DocumentDeleted:    doc.DeletedAt != nil,
+	}
+	if issue.DocumentDeleted {
+		return issue
+	}
+
+	issue.Action = models.ReconcileActionSoftDeleteDocument
+	if doc.StudentID != "" {
+		if _, err := s.StudentRepo.FindByID(doc.StudentID); err == nil {
+			issue.Action = models.ReconcileActionCreateReference
+		}
+	}
+	return issue
+}
+
+func (s *ReconciliationService) repair(
+	ctx context.Context,
+	actor *models.JWTClaims,
+	issue *models.ReconciliationIssue,
+	doc *models.MongoAchievement,
+	team []*models.AchievementReference,
+) error {
+	switch issue.Action {
+	case models.ReconcileActionCreateReference:
+		return s.createReferences(actor, doc)
+	case models.ReconcileActionSoftDeleteReference:
+		if err := s.RefRepo.SoftDeleteByMongoID(issue.MongoAchievementID); err != nil {
+			return err
+		}
+		for _, ref := range team {
+			if ref.Status == models.AchievementStatusDeleted {
+				continue
+			}
+			if err := s.recordHistory(actor, ref, ref.Status, models.AchievementStatusDeleted); err != nil {
+				return err
+			}
+		}
+		return nil
+	case models.ReconcileActionSoftDeleteDocument:
+		return s.MongoRepo.SoftDelete(ctx, issue.MongoAchievementID)
+	case models.ReconcileActionRestoreDocument:
+		return s.MongoRepo.Restore(ctx, issue.MongoAchievementID)
+	}
+	return nil
+}
+
+// createReferences membuat referensi draft pemilik dan anggota tim yang
+// masih terdaftar dalam satu transaksi, lalu mencatat riwayat statusnya.
+func (s *ReconciliationService) createReferences(actor *models.JWTClaims, doc *models.MongoAchievement) error {
+	var members []models.TeamMember
+	for _, m := range doc.Members {
+		_, err := s.StudentRepo.FindByID(m.StudentID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		members = append(members, m)
+	}
+
+	now := time.Now()
+	refs := []*models.AchievementReference{{
+		StudentID: doc.StudentID,
+		Status:    models.AchievementStatusDraft,
+		TeamRole:  models.TeamRoleOwner,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}}
+	refs = append(refs, memberRefs(members, doc.StudentID, now)...)
+	for _, ref := range refs {
+		ref.MongoAchievementID = doc.ID.Hex()
+	}
+
+	if err := s.RefRepo.CreateWithOutbox(refs, ""); err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		if err := s.recordHistory(actor, ref, "", models.AchievementStatusDraft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ReconciliationService) recordHistory(actor *models.JWTClaims, ref *models.AchievementReference, from, to string) error {
+	note := reconcileNote
+	entry := &models.AchievementStatusHistory{
+		AchievementRefID:   ref.ID,
+		MongoAchievementID: ref.MongoAchievementID,
+		ToStatus:           to,
+		ActorID:            actor.UserID,
+		ActorRole:          actor.Role,
+		Note:               &note,
+	}
+	if from != "" {
+		entry.FromStatus = &from
+	}
+	return s.HistoryRepo.Create(entry)
+}
+
+// teamOwner mengembalikan referensi pemilik entri, atau referensi pertama
+// jika pemiliknya tidak ada.
+func teamOwner(team []*models.AchievementReference) *models.AchievementReference {
+	for _, ref := range team {
+		if ref.TeamRole == models.TeamRoleOwner {
+			return ref
+		}
+	}
+	return team[0]
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReconcileAchievements(t *testing.T) {
+	deletedAt := time.Now()
+	okID := primitive.NewObjectID()
+	ownedID := primitive.NewObjectID()
+	strayID := primitive.NewObjectID()
+	trashedID := primitive.NewObjectID()
+	revivedID := primitive.NewObjectID()
+	inFlightID := primitive.NewObjectID()
+	missingID := primitive.NewObjectID().Hex()
+	purgedID := primitive.NewObjectID().Hex()
+	admin := &models.JWTClaims{UserID: "user-admin", Role: "Admin"}
+
+	setup := func() (*services.ReconciliationService, *mocks.AchievementMongoMock, *mocks.AchievementRefMock, *mocks.AchievementHistoryMock) {
+		mongoMock := new(mocks.AchievementMongoMock)
+		refMock := new(mocks.AchievementRefMock)
+		studentMock := new(mocks.StudentRepoMock)
+		historyMock := new(mocks.AchievementHistoryMock)
+
+		refMock.On("GetAllWithDeleted").Return([]*models.AchievementReference{
+			{MongoAchievementID: okID.Hex(), StudentID: "student-1", Status: "verified", TeamRole: "owner"},
+			{MongoAchievementID: okID.Hex(), StudentID: "student-2", Status: "verified", TeamRole: "member"},
+			{MongoAchievementID: trashedID.Hex(), StudentID: "student-1", Status: "deleted", TeamRole: "owner"},
+			{MongoAchievementID: revivedID.Hex(), StudentID: "student-1", Status: "draft", TeamRole: "owner"},
+			{ID: "ref-missing-1", MongoAchievementID: missingID, StudentID: "student-1", Status: "submitted", TeamRole: "owner"},
+			{ID: "ref-missing-2", MongoAchievementID: missingID, StudentID: "student-2", Status: "submitted", TeamRole: "member"},
+			{MongoAchievementID: purgedID, StudentID: "student-1", Status: "deleted", TeamRole: "owner"},
+		}, nil)
+		mongoMock.On("FindAllWithDeleted", mock.Anything).Return([]*models.MongoAchievement{
+			{ID: okID, StudentID: "student-1"},
+			{ID: ownedID, StudentID: "student-1", Title: "Juara 2 KRI", Members: []models.TeamMember{
+				{StudentID: "student-1"}, {StudentID: "student-2"}, {StudentID: "student-9"},
+			}},
+			{ID: strayID, StudentID: "student-9"},
+			{ID: trashedID, StudentID: "student-1"},
+			{ID: revivedID, StudentID: "student-1", DeletedAt: &deletedAt},
+			// Baru disisipkan; referensinya mungkin belum tersimpan.
+			{ID: inFlightID, StudentID: "student-1", CreatedAt: time.Now()},
+		}, nil)
+		studentMock.On("FindByID", "student-1").Return(&models.Student{ID: "student-1"}, nil)
+		studentMock.On("FindByID", "student-2").Return(&models.Student{ID: "student-2"}, nil)
+		studentMock.On("FindByID", "student-9").Return(nil, sql.ErrNoRows)
+
+		service := services.NewReconciliationService(mongoMock, refMock, studentMock, historyMock)
+		return service, mongoMock, refMock, historyMock
+	}
+
+	t.Run("Report Only", func(t *testing.T) {
+		service, mongoMock, refMock, _ := setup()
+
+		report, err := service.Reconcile(context.Background(), admin, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.MissingDocuments)
+		assert.Equal(t, 2, report.OrphanDocuments)
+		assert.Equal(t, 2, report.DeletedMismatches)
+		assert.Equal(t, 1, report.RecentSkipped)
+		assert.Equal(t, 0, report.Repaired)
+
+		actions := map[string]string{}
+		for _, issue := range report.Issues {
+			actions[issue.MongoAchievementID] = issue.Action
+		}
+		assert.Equal(t, map[string]string{
+			missingID:       models.ReconcileActionSoftDeleteReference,
+			purgedID:        "",
+			ownedID.Hex():   models.ReconcileActionCreateReference,
+			strayID.Hex():   models.ReconcileActionSoftDeleteDocument,
+			trashedID.Hex(): models.ReconcileActionSoftDeleteDocument,
+			revivedID.Hex(): models.ReconcileActionRestoreDocument,
+		}, actions)
+
+		refMock.AssertNotCalled(t, "CreateWithOutbox", mock.Anything, mock.Anything)
+		refMock.AssertNotCalled(t, "SoftDeleteByMongoID", mock.Anything)
+		mongoMock.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repair", func(t *testing.T) {
+		service, mongoMock, refMock, historyMock := setup()
+
+		// Pemilik dan anggota yang masih terdaftar dibuatkan referensi
+		// sekaligus; student-9 sudah tidak ada.
+		refMock.On("CreateWithOutbox", mock.MatchedBy(func(refs []*models.AchievementReference) bool {
+			return len(refs) == 2 &&
+				refs[0].MongoAchievementID == ownedID.Hex() && refs[0].StudentID == "student-1" &&
+				refs[0].TeamRole == models.TeamRoleOwner && refs[0].Status == "draft" &&
+				refs[1].MongoAchievementID == ownedID.Hex() && refs[1].StudentID == "student-2" &&
+				refs[1].TeamRole == models.TeamRoleMember
+		}), "").Return(nil).Once()
+		historyMock.On("Create", mock.MatchedBy(func(h *models.AchievementStatusHistory) bool {
+			return h.MongoAchievementID == ownedID.Hex() && h.FromStatus == nil &&
+				h.ToStatus == "draft" && h.ActorID == "user-admin"
+		})).Return(nil).Twice()
+
+		refMock.On("SoftDeleteByMongoID", missingID).Return(nil).Once()
+		historyMock.On("Create", mock.MatchedBy(func(h *models.AchievementStatusHistory) bool {
+			return h.MongoAchievementID == missingID && h.FromStatus != nil && *h.FromStatus == "submitted" &&
+				h.ToStatus == "deleted" && h.ActorRole == "Admin"
+		})).Return(nil).Twice()
+
+		mongoMock.On("SoftDelete", mock.Anything, strayID.Hex()).Return(nil).Once()
+		mongoMock.On("SoftDelete", mock.Anything, trashedID.Hex()).Return(errors.New("mongo: timeout")).Once()
+		mongoMock.On("Restore", mock.Anything, revivedID.Hex()).Return(nil).Once()
+
+		report, err := service.Reconcile(context.Background(), admin, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, report.Repaired)
+		assert.Equal(t, 1, report.Failed)
+		refMock.AssertNotCalled(t, "SoftDeleteByMongoID", purgedID)
+		refMock.AssertExpectations(t)
+		mongoMock.AssertExpectations(t)
+		historyMock.AssertExpectations(t)
+	})
+}
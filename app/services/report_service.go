@@ -2,23 +2,48 @@ package services
 
 import (
 	"context"
+	"strings"
+	"time"
 	"uas/app/repositories"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ReportService struct {
 	ReportRepo repositories.IReportRepository
 	MongoRepo  repositories.IAchievementMongoReportRepository
+	TagRepo    repositories.ITagRepository
 }
 
-func NewReportService(r repositories.IReportRepository, m repositories.IAchievementMongoReportRepository) *ReportService {
+func NewReportService(r repositories.IReportRepository, m repositories.IAchievementMongoReportRepository, t repositories.ITagRepository) *ReportService {
 	return &ReportService{
 		ReportRepo: r,
 		MongoRepo:  m,
+		TagRepo:    t,
 	}
 }
 
+// filterByTag menyaring ids ke prestasi yang memiliki tag dari query ?tag=.
+// Tanpa tag, ids dikembalikan apa adanya.
+func (s *ReportService) filterByTag(c *fiber.Ctx, ids []string) ([]string, error) {
+	tag := strings.TrimSpace(c.Query("tag"))
+	if tag == "" || len(ids) == 0 {
+		return ids, nil
+	}
+
+	vocab, err := loadTagVocabulary(s.TagRepo)
+	if err != nil {
+		return nil, err
+	}
+	return s.MongoRepo.FilterIDsByTag(context.Background(), ids, vocab.Spellings(tag))
+}
+
+// GetAchievementStatistics menghitung statistik per prestasi, bukan per
+// anggota: prestasi tim dihitung sekali dengan poin dokumennya, terlepas
+// dari kebijakan split/duplicate yang hanya memengaruhi poin tiap anggota
+// pada laporan mahasiswa.
 func (s *ReportService) GetAchievementStatistics(c *fiber.Ctx) error {
 	ctx := context.Background()
 
@@ -27,6 +52,11 @@ func (s *ReportService) GetAchievementStatistics(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	ids, err = s.filterByTag(c, ids)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	totalPoints, _ := s.MongoRepo.SumPointsByIDs(ctx, ids)
 	byType, _ := s.MongoRepo.CountByType(ctx, ids)
 
@@ -51,14 +81,25 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	ids, err = s.filterByTag(c, ids)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	achievements, err := s.MongoRepo.FindByIDs(ctx, ids)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	awarded, err := s.ReportRepo.GetAwardedPointsByStudent(studentID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
 
 	var totalPoints int
 	pointsByType := make(map[string][]int)
+	certifications := []fiber.Map{}
+	now := time.Now()
 
 	for _, a := range achievements {
 		typeName, _ := a["achievementType"].(string)
@@ -66,6 +107,10 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 			typeName = "Lainnya"
 		}
 
+		if typeName == "certification" {
+			certifications = append(certifications, certificationEntry(a, now))
+		}
+
 		var pointsVal int
 		if val, ok := a["points"]; ok {
 			switch v := val.(type) {
@@ -80,6 +125,13 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 			}
 		}
 
+		// Poin anggota prestasi tim mengikuti kebijakan split/duplicate.
+		if oid, ok := a["_id"].(primitive.ObjectID); ok {
+			if p, ok := awarded[oid.Hex()]; ok {
+				pointsVal = p
+			}
+		}
+
 		totalPoints += pointsVal
 		pointsByType[typeName] = append(pointsByType[typeName], pointsVal)
 	}
@@ -103,5 +155,28 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 		"total_points":           totalPoints,
 		"average_points_by_type": avgByType,
 		"achievements":           achievements,
+		"certifications":         certifications,
 	})
+}
+
+// certificationEntry meringkas masa berlaku satu sertifikasi untuk laporan.
+func certificationEntry(a bson.M, now time.Time) fiber.Map {
+	entry := fiber.Map{"id": a["_id"], "title": a["title"]}
+
+	var validUntil *time.Time
+	details, ok := a["details"].(bson.M)
+	if d, isD := a["details"].(primitive.D); isD {
+		details, ok = d.Map(), true
+	}
+	if ok {
+		entry["certificationName"] = details["certificationName"]
+		if dt, ok := details["validUntil"].(primitive.DateTime); ok {
+			t := dt.Time()
+			validUntil = &t
+		}
+	}
+
+	entry["validUntil"] = validUntil
+	entry["valid"] = CertificationValid(validUntil, now)
+	return entry
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 	"uas/app/mocks"
+	"uas/app/models"
 	"uas/app/services"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,7 +17,7 @@ import (
 func TestReportService(t *testing.T) {
 	reportMock := new(mocks.ReportRepoMock)
 	mongoMock := new(mocks.MongoReportMock)
-	service := services.NewReportService(reportMock, mongoMock)
+	service := services.NewReportService(reportMock, mongoMock, nil)
 
 	app := fiber.New()
 	app.Get("/reports/statistics", service.GetAchievementStatistics)
@@ -56,6 +57,7 @@ func TestReportService(t *testing.T) {
 		}
 		
 		mongoMock.On("FindByIDs", mock.Anything, mockIDs).Return(mockData, nil).Once()
+		reportMock.On("GetAwardedPointsByStudent", studentID).Return(map[string]int{}, nil).Once()
 		mongoMock.On("SumPointsByIDs", mock.Anything, mockIDs).Return(150, nil).Once()
 
 		req := httptest.NewRequest("GET", "/reports/students/"+studentID, nil)
@@ -69,4 +71,32 @@ func TestReportService(t *testing.T) {
 		avgByType := result["average_points_by_type"].(map[string]interface{})
 		assert.Equal(t, 75.0, avgByType["Akademik"])
 	})
-}
\ No newline at end of file
+}
+
+func TestReportTagFilterMatchesAliases(t *testing.T) {
+	reportMock := new(mocks.ReportRepoMock)
+	mongoMock := new(mocks.MongoReportMock)
+	tagMock := new(mocks.TagRepoMock)
+	service := services.NewReportService(reportMock, mongoMock, tagMock)
+
+	app := fiber.New()
+	app.Get("/reports/statistics", service.GetAchievementStatistics)
+
+	tagMock.On("FindAll").Return([]*models.Tag{
+		{ID: "tag-1", Name: "Artificial Intelligence", Aliases: []string{"AI"}},
+	}, nil)
+	reportMock.On("GetVerifiedAchievementMongoIDs").Return([]string{"old", "new", "other"}, nil)
+	// Dokumen lama yang masih bertag "AI" ikut cocok dengan nama kanonik.
+	mongoMock.On("FilterIDsByTag", mock.Anything, []string{"old", "new", "other"}, []string{"Artificial Intelligence", "ai"}).
+		Return([]string{"old", "new"}, nil).Once()
+	mongoMock.On("SumPointsByIDs", mock.Anything, []string{"old", "new"}).Return(120, nil)
+	mongoMock.On("CountByType", mock.Anything, []string{"old", "new"}).Return(map[string]int{"competition": 2}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/statistics?tag=artificial+intelligence", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, float64(2), result["total_verified_achievements"])
+	mongoMock.AssertExpectations(t)
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+type RubricService struct {
+	RubricRepo repositories.IRubricRepository
+}
+
+func NewRubricService(rubric repositories.IRubricRepository) *RubricService {
+	return &RubricService{RubricRepo: rubric}
+}
+
+// ScoreAchievement menjumlahkan poin semua aturan rubrik yang cocok dengan
+// tipe dan detail prestasi. Aturan "base" selalu cocok untuk tipenya.
+func ScoreAchievement(rules []*models.RubricRule, doc *models.MongoAchievement) models.RubricScore {
+	score := models.RubricScore{Matches: []*models.RubricRule{}}
+
+	for _, rule := range rules {
+		if !strings.EqualFold(rule.AchievementType, doc.AchievementType) {
+			continue
+		}
+
+		value, ok := rubricFieldValue(rule.Field, &doc.Details)
+		if !ok || !strings.EqualFold(strings.TrimSpace(rule.Value), value) {
+			continue
+		}
+
+		score.Points += rule.Points
+		score.Matches = append(score.Matches, rule)
+	}
+
+	return score
+}
+
+// withTypeDefaults menambahkan DefaultPoints tipe tambahan sebagai aturan
+// "base" implisit, kecuali rubrik sudah punya aturan base untuk tipe itu.
+func withTypeDefaults(rules []*models.RubricRule, defs ...*models.AchievementTypeDefinition) []*models.RubricRule {
+	hasBase := map[string]bool{}
+	for _, rule := range rules {
+		if rule.Field == models.RubricFieldBase {
+			hasBase[strings.ToLower(rule.AchievementType)] = true
+		}
+	}
+
+	out := append([]*models.RubricRule{}, rules...)
+	for _, def := range defs {
+		if hasBase[strings.ToLower(def.Code)] || def.DefaultPoints == 0 {
+			continue
+		}
+		out = append(out, &models.RubricRule{
+			AchievementType: def.Code,
+			Field:           models.RubricFieldBase,
+			Points:          def.DefaultPoints,
+			Description:     "default points for " + def.Name,
+		})
+	}
+	return out
+}
+
+func rubricFieldValue(field string, d *models.AchievementDetails) (string, bool) {
+	deref := func(v *string) (string, bool) {
+		if v == nil {
+			return "", false
+		}
+		return strings.TrimSpace(*v), true
+	}
+
+	switch field {
+	case models.RubricFieldBase:
+		return "", true
+	case "competitionLevel":
+		return deref(d.CompetitionLevel)
+	case "rank":
+		if d.Rank == nil {
+			return "", false
+		}
+		return strconv.Itoa(*d.Rank), true
+	case "medalType":
+		return deref(d.MedalType)
+	case "publicationType":
+		return deref(d.PublicationType)
+	case "position":
+		return deref(d.Position)
+	}
+	return "", false
+}
+
+func isRubricField(field string) bool {
+	for _, f := range models.RubricFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRubricRules godoc
+// @Summary List rubric rules
+// @Description Admin melihat aturan rubrik poin prestasi
+// @Tags Rubric
+// @Accept json
+// @Produce json
+// @Param type query string false "Achievement type"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /rubrics [get]
+func (s *RubricService) ListRubricRules() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var (
+			rules []*models.RubricRule
+			err   error
+		)
+
+		if t := c.Query("type"); t != "" {
+			rules, err = s.RubricRepo.FindByType(t)
+		} else {
+			rules, err = s.RubricRepo.FindAll()
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if rules == nil {
+			rules = []*models.RubricRule{}
+		}
+
+		return c.JSON(fiber.Map{"data": rules})
+	}
+}
+
+// CreateRubricRule godoc
+// @Summary Create rubric rule
+// @Description Admin menambahkan aturan rubrik poin
+// @Tags Rubric
+// @Accept json
+// @Produce json
+// @Param rule body models.RubricRule true "Rubric rule"
+// @Success 201 {object} models.RubricRule
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /rubrics [post]
+func (s *RubricService) CreateRubricRule() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var rule models.RubricRule
+		if err := c.BodyParser(&rule); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if msg := validateRubricRule(&rule); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		if err := s.RubricRepo.Create(&rule); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.Status(201).JSON(rule)
+	}
+}
+
+// UpdateRubricRule godoc
+// @Summary Update rubric rule
+// @Description Admin mengubah aturan rubrik poin
+// @Tags Rubric
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body models.RubricRule true "Rubric rule"
+// @Success 200 {object} models.RubricRule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /rubrics/{id} [put]
+func (s *RubricService) UpdateRubricRule() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var rule models.RubricRule
+		if err := c.BodyParser(&rule); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		rule.ID = c.Params("id")
+
+		if msg := validateRubricRule(&rule); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+
+		if err := s.RubricRepo.Update(&rule); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "rule not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(rule)
+	}
+}
+
+// DeleteRubricRule godoc
+// @Summary Delete rubric rule
+// @Description Admin menghapus aturan rubrik poin
+// @Tags Rubric
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /rubrics/{id} [delete]
+func (s *RubricService) DeleteRubricRule() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		if err := s.RubricRepo.Delete(c.Params("id")); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "rule not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+func validateRubricRule(rule *models.RubricRule) string {
+	rule.AchievementType = strings.TrimSpace(rule.AchievementType)
+	rule.Value = strings.TrimSpace(rule.Value)
+
+	if rule.AchievementType == "" {
+		return "achievementType is required"
+	}
+	if !isRubricField(rule.Field) {
+		return "field must be one of: " + strings.Join(models.RubricFields, ", ")
+	}
+	if rule.Field == models.RubricFieldBase {
+		rule.Value = ""
+	} else if rule.Value == "" {
+		return "value is required"
+	}
+	if rule.Field == "rank" {
+		if _, err := strconv.Atoi(rule.Value); err != nil {
+			return "rank value must be a number"
+		}
+	}
+	return ""
+}
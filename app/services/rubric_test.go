@@ -0,0 +1,38 @@
+package services_test
+
+import (
+	"testing"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreAchievement(t *testing.T) {
+	rules := []*models.RubricRule{
+		{AchievementType: "competition", Field: "base", Points: 10},
+		{AchievementType: "competition", Field: "competitionLevel", Value: "international", Points: 50},
+		{AchievementType: "competition", Field: "competitionLevel", Value: "national", Points: 30},
+		{AchievementType: "competition", Field: "rank", Value: "1", Points: 20},
+		{AchievementType: "competition", Field: "medalType", Value: "gold", Points: 15},
+		{AchievementType: "publication", Field: "base", Points: 40},
+	}
+
+	level, medal, rank := "International", "Gold", 1
+	doc := &models.MongoAchievement{
+		AchievementType: "Competition",
+		Details: models.AchievementDetails{
+			CompetitionLevel: &level,
+			MedalType:        &medal,
+			Rank:             &rank,
+		},
+	}
+
+	score := services.ScoreAchievement(rules, doc)
+	assert.Equal(t, 95, score.Points)
+	assert.Len(t, score.Matches, 4)
+
+	empty := services.ScoreAchievement(rules, &models.MongoAchievement{AchievementType: "organization"})
+	assert.Equal(t, 0, empty.Points)
+	assert.Empty(t, empty.Matches)
+}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"uas/app/models"
 	"uas/app/repositories"
 	"uas/databases"
 )
@@ -60,6 +63,7 @@ func GetStudentByID(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Student ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
@@ -68,38 +72,36 @@ func GetStudentAchievements(c *fiber.Ctx) error {
 	ctx := context.Background()
 
 	studentRepo := repositories.NewStudentRepository(databases.PSQL)
+	lecturerRepo := repositories.NewLecturerRepository(databases.PSQL)
 	refRepo := repositories.NewAchievementReferenceRepo(databases.PSQL)
 	mongoRepo := repositories.NewAchievementMongoRepository(databases.MongoDB)
 
 	studentID := c.Params("id")
+	claims := c.Locals("user").(*models.JWTClaims)
 
 	student, err := studentRepo.FindByID(studentID)
-	if err != nil {
+	if err == sql.ErrNoRows {
 		return fiber.NewError(fiber.StatusNotFound, "student not found")
 	}
-
-	refs, err := refRepo.GetByStudentID(studentID)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	var results []fiber.Map
-
-	for _, ref := range refs {
-		mongoAch, err := mongoRepo.FindByID(ctx, ref.MongoAchievementID)
-		if err != nil {
-			continue
+	access := NewAchievementAccess(
+		studentRepo,
+		lecturerRepo,
+		repositories.NewVerificationDelegationRepository(databases.PSQL),
+	)
+	if err := access.CanView(claims, studentID); err != nil {
+		if errors.Is(err, ErrAchievementForbidden) {
+			return fiber.ErrForbidden
 		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
 
-		results = append(results, fiber.Map{
-			"id":             ref.ID,
-			"status":         ref.Status,
-			"submitted_at":   ref.SubmittedAt,
-			"verified_at":    ref.VerifiedAt,
-			"verified_by":    ref.VerifiedBy,
-			"rejection_note": ref.RejectionNote,
-			"achievement":   mongoAch,
-		})
+	results, err := LoadStudentAchievements(ctx, refRepo, mongoRepo, studentID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
 	return c.JSON(fiber.Map{
@@ -151,3 +153,43 @@ func UpdateStudentAdvisor(c *fiber.Ctx) error {
 		"message": "advisor updated successfully",
 	})
 }
+
+// LoadStudentAchievements membaca referensi mahasiswa lalu semua dokumen
+// Mongo-nya dalam satu query, sehingga jumlah round trip tetap berapa pun
+// banyaknya prestasi.
+func LoadStudentAchievements(
+	ctx context.Context,
+	refRepo repositories.IAchievementReferenceRepo,
+	mongoRepo repositories.IAchievementMongoRepository,
+	studentID string,
+) ([]fiber.Map, error) {
+
+	refs, err := refRepo.GetByStudentIDs([]string{studentID})
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := findDocsByRefs(ctx, mongoRepo, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []fiber.Map{}
+	for _, ref := range refs {
+		mongoAch, ok := docs[ref.MongoAchievementID]
+		if !ok {
+			continue
+		}
+
+		results = append(results, fiber.Map{
+			"id":             ref.ID,
+			"status":         ref.Status,
+			"submitted_at":   ref.SubmittedAt,
+			"verified_at":    ref.VerifiedAt,
+			"verified_by":    ref.VerifiedBy,
+			"rejection_note": ref.RejectionNote,
+			"achievement":    mongoAch,
+		})
+	}
+	return results, nil
+}
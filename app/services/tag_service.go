@@ -0,0 +1,300 @@
+package services
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// Batas hasil autocomplete tag.
+const (
+	DefaultTagSuggestions = 10
+	MaxTagSuggestions     = 50
+)
+
+type TagService struct {
+	TagRepo repositories.ITagRepository
+}
+
+func NewTagService(tags repositories.ITagRepository) *TagService {
+	return &TagService{TagRepo: tags}
+}
+
+// TagVocabulary memetakan nama dan alias tag (tanpa membedakan huruf besar
+// kecil dan spasi berlebih) ke nama kanoniknya.
+type TagVocabulary map[string]string
+
+func NewTagVocabulary(tags []*models.Tag) TagVocabulary {
+	v := TagVocabulary{}
+	for _, tag := range tags {
+		v[tagKey(tag.Name)] = tag.Name
+		for _, alias := range tag.Aliases {
+			v[tagKey(alias)] = tag.Name
+		}
+	}
+	return v
+}
+
+// Canonical mengembalikan nama kanonik tag. Tag di luar kosakata hanya
+// dirapikan spasinya.
+func (v TagVocabulary) Canonical(tag string) string {
+	tag = strings.Join(strings.Fields(tag), " ")
+	if name, ok := v[tagKey(tag)]; ok {
+		return name
+	}
+	return tag
+}
+
+// Spellings mengembalikan nama kanonik tag beserta semua aliasnya, urut
+// dengan nama kanonik lebih dulu. Dipakai untuk filter tag agar dokumen
+// yang disimpan sebelum alias terdaftar tetap cocok.
+func (v TagVocabulary) Spellings(tag string) []string {
+	name := v.Canonical(tag)
+	if name == "" {
+		return nil
+	}
+
+	spellings := []string{name}
+	var aliases []string
+	for key, canonical := range v {
+		if canonical == name && key != tagKey(name) {
+			aliases = append(aliases, key)
+		}
+	}
+	sort.Strings(aliases)
+	return append(spellings, aliases...)
+}
+
+// Normalize mengganti setiap tag dengan nama kanoniknya, membuang tag
+// kosong, dan menghapus duplikat dengan urutan tetap.
+func (v TagVocabulary) Normalize(tags []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		name := v.Canonical(tag)
+		if name == "" || seen[tagKey(name)] {
+			continue
+		}
+		seen[tagKey(name)] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+func tagKey(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// hasTag memeriksa apakah tags memuat tag tanpa membedakan huruf besar kecil.
+func hasTag(tags []string, tag string) bool {
+	key := tagKey(tag)
+	for _, t := range tags {
+		if tagKey(t) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTagVocabulary membaca kosakata tag. Repository nil berarti kosakata
+// kosong sehingga tag hanya dirapikan.
+func loadTagVocabulary(repo repositories.ITagRepository) (TagVocabulary, error) {
+	if repo == nil {
+		return TagVocabulary{}, nil
+	}
+	tags, err := repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return NewTagVocabulary(tags), nil
+}
+
+// ListTags godoc
+// @Summary List or autocomplete tags
+// @Description Tanpa q: semua tag kosakata. Dengan q: saran tag yang nama atau aliasnya diawali q.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param q query string false "Prefix"
+// @Param limit query int false "Max suggestions (default 10, max 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /tags [get]
+func (s *TagService) ListTags() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		q := strings.TrimSpace(c.Query("q"))
+
+		var (
+			tags []*models.Tag
+			err  error
+		)
+		if q == "" {
+			tags, err = s.TagRepo.FindAll()
+		} else {
+			limit := c.QueryInt("limit", DefaultTagSuggestions)
+			if limit < 1 || limit > MaxTagSuggestions {
+				limit = DefaultTagSuggestions
+			}
+			tags, err = s.TagRepo.Search(q, limit)
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if tags == nil {
+			tags = []*models.Tag{}
+		}
+
+		return c.JSON(fiber.Map{"data": tags})
+	}
+}
+
+// CreateTag godoc
+// @Summary Create tag
+// @Description Admin menambahkan tag ke kosakata beserta aliasnya
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param tag body models.Tag true "Tag"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /tags [post]
+func (s *TagService) CreateTag() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var tag models.Tag
+		if err := c.BodyParser(&tag); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		tag.ID = ""
+
+		if code, msg := s.validateTag(&tag); msg != "" {
+			return c.Status(code).JSON(fiber.Map{"error": msg})
+		}
+
+		if err := s.TagRepo.Create(&tag); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.Status(201).JSON(tag)
+	}
+}
+
+// UpdateTag godoc
+// @Summary Update tag
+// @Description Admin mengubah nama atau alias tag. Prestasi lama tidak diubah; tag lamanya
+// @Description dinormalisasi saat prestasi diperbarui berikutnya.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param tag body models.Tag true "Tag"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /tags/{id} [put]
+func (s *TagService) UpdateTag() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		id := c.Params("id")
+		if _, err := s.TagRepo.FindByID(id); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "tag not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var tag models.Tag
+		if err := c.BodyParser(&tag); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		tag.ID = id
+
+		if code, msg := s.validateTag(&tag); msg != "" {
+			return c.Status(code).JSON(fiber.Map{"error": msg})
+		}
+
+		if err := s.TagRepo.Update(&tag); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(tag)
+	}
+}
+
+// DeleteTag godoc
+// @Summary Delete tag
+// @Description Admin menghapus tag dari kosakata. Tag pada prestasi tidak dihapus.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /tags/{id} [delete]
+func (s *TagService) DeleteTag() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		if err := s.TagRepo.Delete(c.Params("id")); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "tag not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// validateTag merapikan nama dan alias, lalu memastikan tidak ada nama atau
+// alias yang sudah dipakai tag lain agar setiap ejaan hanya punya satu
+// nama kanonik.
+func (s *TagService) validateTag(tag *models.Tag) (int, string) {
+	tag.Name = strings.Join(strings.Fields(tag.Name), " ")
+	if tag.Name == "" {
+		return 400, "name is required"
+	}
+
+	aliases := []string{}
+	seen := map[string]bool{tagKey(tag.Name): true}
+	for _, alias := range tag.Aliases {
+		alias = strings.Join(strings.Fields(alias), " ")
+		if alias == "" || seen[tagKey(alias)] {
+			continue
+		}
+		seen[tagKey(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	tag.Aliases = aliases
+
+	existing, err := s.TagRepo.FindAll()
+	if err != nil {
+		return 500, err.Error()
+	}
+	for _, other := range existing {
+		if other.ID == tag.ID {
+			continue
+		}
+		for _, spelling := range append([]string{other.Name}, other.Aliases...) {
+			if seen[tagKey(spelling)] {
+				return 409, "\"" + spelling + "\" is already used by tag " + other.Name
+			}
+		}
+	}
+	return 0, ""
+}
@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTagVocabulary(t *testing.T) {
+	vocab := services.NewTagVocabulary([]*models.Tag{
+		{Name: "Artificial Intelligence", Aliases: []string{"AI", "kecerdasan buatan"}},
+		{Name: "Web"},
+	})
+
+	assert.Equal(t, "Artificial Intelligence", vocab.Canonical("ai"))
+	assert.Equal(t, "Artificial Intelligence", vocab.Canonical("  Kecerdasan   Buatan "))
+	assert.Equal(t, "Web", vocab.Canonical("WEB"))
+	assert.Equal(t, "Robotika Laut", vocab.Canonical(" Robotika  Laut"))
+
+	assert.Equal(t,
+		[]string{"Artificial Intelligence", "Web", "IoT"},
+		vocab.Normalize([]string{"AI", "ai", "web", "", "Artificial Intelligence", "IoT", "iot"}),
+	)
+
+	assert.Equal(t, []string{"Artificial Intelligence", "ai", "kecerdasan buatan"}, vocab.Spellings("AI"))
+	assert.Equal(t, []string{"Web"}, vocab.Spellings("web"))
+	assert.Equal(t, []string{"Robotika Laut"}, vocab.Spellings("Robotika  Laut"))
+}
+
+func TestTagService(t *testing.T) {
+	tagMock := new(mocks.TagRepoMock)
+	service := services.NewTagService(tagMock)
+
+	app := fiber.New()
+	app.Get("/tags", service.ListTags())
+	app.Post("/tags", service.CreateTag())
+
+	tagMock.On("FindAll").Return([]*models.Tag{
+		{ID: "tag-1", Name: "Artificial Intelligence", Aliases: []string{"AI"}},
+	}, nil)
+
+	post := func(payload interface{}) int {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", "/tags", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+
+	t.Run("Create - Alias Already Used", func(t *testing.T) {
+		code := post(map[string]interface{}{"name": "Machine Learning", "aliases": []string{"ml", "ai"}})
+		assert.Equal(t, 409, code)
+	})
+
+	t.Run("Create - Success", func(t *testing.T) {
+		tagMock.On("Create", mock.MatchedBy(func(tag *models.Tag) bool {
+			return tag.Name == "Machine Learning" && len(tag.Aliases) == 1 && tag.Aliases[0] == "ML"
+		})).Return(nil).Once()
+
+		code := post(map[string]interface{}{"name": " Machine  Learning ", "aliases": []string{"ML", "ml", "machine learning"}})
+		assert.Equal(t, 201, code)
+	})
+
+	t.Run("Autocomplete", func(t *testing.T) {
+		tagMock.On("Search", "a", 10).Return([]*models.Tag{
+			{ID: "tag-1", Name: "Artificial Intelligence", Aliases: []string{"AI"}},
+		}, nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest("GET", "/tags?q=a", nil))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result struct {
+			Data []models.Tag `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		assert.Len(t, result.Data, 1)
+		tagMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+type TeamPointPolicyService struct {
+	PolicyRepo repositories.ITeamPointPolicyRepository
+}
+
+func NewTeamPointPolicyService(policy repositories.ITeamPointPolicyRepository) *TeamPointPolicyService {
+	return &TeamPointPolicyService{PolicyRepo: policy}
+}
+
+// ListTeamPointPolicies godoc
+// @Summary List team point policies
+// @Description Admin melihat kebijakan pembagian poin prestasi tim per tipe prestasi
+// @Tags Team Point Policies
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /team-point-policies [get]
+func (s *TeamPointPolicyService) ListTeamPointPolicies() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		policies, err := s.PolicyRepo.FindAll()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if policies == nil {
+			policies = []*models.TeamPointPolicy{}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":     policies,
+			"fallback": models.TeamPointPolicyDuplicate,
+		})
+	}
+}
+
+// UpsertTeamPointPolicy godoc
+// @Summary Set team point policy
+// @Description Admin mengatur pembagian poin prestasi tim untuk satu tipe prestasi.
+// @Description split = poin dibagi rata antar anggota, duplicate = setiap anggota menerima poin penuh.
+// @Tags Team Point Policies
+// @Accept json
+// @Produce json
+// @Param policy body models.TeamPointPolicy true "Team point policy"
+// @Success 200 {object} models.TeamPointPolicy
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /team-point-policies [put]
+func (s *TeamPointPolicyService) UpsertTeamPointPolicy() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var policy models.TeamPointPolicy
+		if err := c.BodyParser(&policy); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		policy.AchievementType = strings.ToLower(strings.TrimSpace(policy.AchievementType))
+		policy.Policy = strings.ToLower(strings.TrimSpace(policy.Policy))
+
+		if policy.AchievementType == "" {
+			return c.Status(400).JSON(fiber.Map{"error": "achievementType is required"})
+		}
+		if policy.Policy != models.TeamPointPolicySplit && policy.Policy != models.TeamPointPolicyDuplicate {
+			return c.Status(400).JSON(fiber.Map{"error": "policy must be split or duplicate"})
+		}
+
+		if err := s.PolicyRepo.Upsert(&policy); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(policy)
+	}
+}
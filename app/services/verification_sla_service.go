@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/models"
+	"uas/app/repositories"
+)
+
+// SLA bawaan jika program studi belum diatur dan tidak ada SLA default.
+const (
+	DefaultSLADueDays      = 7
+	DefaultSLAEscalateDays = 14
+)
+
+type VerificationSLAService struct {
+	RefRepo repositories.IAchievementReferenceRepo
+	SLARepo repositories.IVerificationSLARepository
+}
+
+func NewVerificationSLAService(
+	ref repositories.IAchievementReferenceRepo,
+	sla repositories.IVerificationSLARepository,
+) *VerificationSLAService {
+	return &VerificationSLAService{
+		RefRepo: ref,
+		SLARepo: sla,
+	}
+}
+
+// RunChecker menjalankan Check setiap interval sampai ctx dibatalkan.
+func (s *VerificationSLAService) RunChecker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Check(time.Now())
+		if err != nil {
+			log.Println("SLA checker:", err)
+		} else if result.Overdue > 0 || result.Escalated > 0 {
+			log.Printf("SLA checker: %d overdue, %d escalated of %d submitted", result.Overdue, result.Escalated, result.Scanned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check menandai prestasi submitted yang melewati batas SLA program
+// studinya sebagai overdue, lalu mengeskalasi ke antrean admin setelah
+// batas kedua terlewati. Prestasi yang dieskalasi muncul di
+// ListAchievements?awaiting=true milik admin walaupun masih di tahap dosen
+// wali. Prestasi yang sudah ditandai tidak dihitung ulang.
+func (s *VerificationSLAService) Check(now time.Time) (*models.SLACheckResult, error) {
+	slas, err := s.slaByProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.RefRepo.GetPendingVerifications("", false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SLACheckResult{Scanned: len(pending)}
+	var overdue, escalated []string
+
+	for _, p := range pending {
+		if p.SubmittedAt == nil {
+			continue
+		}
+		sla := slaFor(slas, p.ProgramStudy)
+		age := now.Sub(*p.SubmittedAt)
+
+		if p.EscalatedAt == nil && age >= days(sla.EscalateDays) {
+			escalated = append(escalated, p.MongoAchievementID)
+			continue
+		}
+		if p.OverdueAt == nil && age >= days(sla.DueDays) {
+			overdue = append(overdue, p.MongoAchievementID)
+		}
+	}
+
+	if len(overdue) > 0 {
+		if err := s.RefRepo.MarkOverdueByMongoIDs(overdue, now); err != nil {
+			return nil, err
+		}
+	}
+	if len(escalated) > 0 {
+		if err := s.RefRepo.MarkEscalatedByMongoIDs(escalated, now); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Overdue = len(overdue)
+	result.Escalated = len(escalated)
+	return result, nil
+}
+
+// ListOverdueAchievements godoc
+// @Summary List overdue achievements
+// @Description Admin melihat prestasi submitted yang melewati SLA verifikasi.
+// @Description escalated=true hanya menampilkan antrean eskalasi admin.
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Param program query string false "Program study"
+// @Param escalated query bool false "Only escalated items"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /achievements/overdue [get]
+func (s *VerificationSLAService) ListOverdueAchievements() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		escalatedOnly := c.QueryBool("escalated", false)
+
+		items, err := s.RefRepo.GetPendingVerifications(c.Query("program"), true, escalatedOnly)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if items == nil {
+			items = []*models.PendingVerification{}
+		}
+
+		now := time.Now()
+		escalated := 0
+		for _, item := range items {
+			if item.SubmittedAt != nil {
+				item.DaysPending = int(now.Sub(*item.SubmittedAt) / (24 * time.Hour))
+			}
+			if item.EscalatedAt != nil {
+				escalated++
+			}
+		}
+
+		return c.JSON(fiber.Map{
+			"data":      items,
+			"total":     len(items),
+			"escalated": escalated,
+		})
+	}
+}
+
+// ListVerificationSLA godoc
+// @Summary List verification SLA
+// @Description Admin melihat SLA verifikasi per program studi
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /verification-sla [get]
+func (s *VerificationSLAService) ListVerificationSLA() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		slas, err := s.SLARepo.FindAll()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if slas == nil {
+			slas = []*models.VerificationSLA{}
+		}
+
+		return c.JSON(fiber.Map{
+			"data": slas,
+			"fallback": models.VerificationSLA{
+				DueDays:      DefaultSLADueDays,
+				EscalateDays: DefaultSLAEscalateDays,
+			},
+		})
+	}
+}
+
+// UpsertVerificationSLA godoc
+// @Summary Set verification SLA
+// @Description Admin mengatur SLA verifikasi untuk satu program studi (programStudy kosong = default)
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Param sla body models.VerificationSLA true "SLA"
+// @Success 200 {object} models.VerificationSLA
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /verification-sla [put]
+func (s *VerificationSLAService) UpsertVerificationSLA() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		var sla models.VerificationSLA
+		if err := c.BodyParser(&sla); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		sla.ProgramStudy = strings.TrimSpace(sla.ProgramStudy)
+		if sla.DueDays < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "dueDays must be at least 1"})
+		}
+		if sla.EscalateDays <= sla.DueDays {
+			return c.Status(400).JSON(fiber.Map{"error": "escalateDays must be greater than dueDays"})
+		}
+
+		if err := s.SLARepo.Upsert(&sla); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(sla)
+	}
+}
+
+// DeleteVerificationSLA godoc
+// @Summary Delete verification SLA
+// @Description Admin menghapus SLA program studi sehingga kembali memakai SLA default
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Param program query string false "Program study (kosong = default)"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /verification-sla [delete]
+func (s *VerificationSLAService) DeleteVerificationSLA() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		if err := s.SLARepo.Delete(strings.TrimSpace(c.Query("program"))); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "sla not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+func (s *VerificationSLAService) slaByProgram() (map[string]*models.VerificationSLA, error) {
+	slas, err := s.SLARepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byProgram := map[string]*models.VerificationSLA{}
+	for _, sla := range slas {
+		byProgram[sla.ProgramStudy] = sla
+	}
+	return byProgram, nil
+}
+
+// slaFor memilih SLA program studi, lalu SLA default (program kosong),
+// lalu konstanta bawaan.
+func slaFor(slas map[string]*models.VerificationSLA, programStudy string) *models.VerificationSLA {
+	if sla, ok := slas[programStudy]; ok {
+		return sla
+	}
+	if sla, ok := slas[""]; ok {
+		return sla
+	}
+	return &models.VerificationSLA{DueDays: DefaultSLADueDays, EscalateDays: DefaultSLAEscalateDays}
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
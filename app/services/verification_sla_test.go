@@ -0,0 +1,45 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+	"uas/app/mocks"
+	"uas/app/models"
+	"uas/app/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationSLACheck(t *testing.T) {
+	refMock := new(mocks.AchievementRefMock)
+	slaMock := new(mocks.VerificationSLARepoMock)
+	service := services.NewVerificationSLAService(refMock, slaMock)
+
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	ago := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+
+	slaMock.On("FindAll").Return([]*models.VerificationSLA{
+		{ProgramStudy: "", DueDays: 10, EscalateDays: 20},
+		{ProgramStudy: "Informatika", DueDays: 3, EscalateDays: 5},
+	}, nil)
+	refMock.On("GetPendingVerifications", "", false, false).Return([]*models.PendingVerification{
+		{MongoAchievementID: "fresh", ProgramStudy: "Informatika", SubmittedAt: ago(1)},
+		{MongoAchievementID: "late", ProgramStudy: "Informatika", SubmittedAt: ago(4)},
+		{MongoAchievementID: "stale", ProgramStudy: "Informatika", SubmittedAt: ago(6), OverdueAt: ago(2)},
+		{MongoAchievementID: "default-late", ProgramStudy: "Sistem Informasi", SubmittedAt: ago(11)},
+		{MongoAchievementID: "flagged", ProgramStudy: "Sistem Informasi", SubmittedAt: ago(12), OverdueAt: ago(1)},
+		{MongoAchievementID: "done", ProgramStudy: "Informatika", SubmittedAt: ago(9), OverdueAt: ago(6), EscalatedAt: ago(4)},
+	}, nil)
+	refMock.On("MarkOverdueByMongoIDs", []string{"late", "default-late"}, now).Return(nil).Once()
+	refMock.On("MarkEscalatedByMongoIDs", []string{"stale"}, now).Return(nil).Once()
+
+	result, err := service.Check(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, result.Scanned)
+	assert.Equal(t, 2, result.Overdue)
+	assert.Equal(t, 1, result.Escalated)
+	refMock.AssertExpectations(t)
+}
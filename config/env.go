@@ -2,6 +2,9 @@ package config
 
 import (
 	"log"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,3 +15,48 @@ func LoadEnv() {
 		log.Println("Warning: .env file not found, using system environment variables")
 	}
 }
+
+// SLACheckInterval membaca SLA_CHECK_INTERVAL (format time.Duration,
+// misalnya "30m"). Default satu jam.
+func SLACheckInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("SLA_CHECK_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// TrashPurgeInterval membaca TRASH_PURGE_INTERVAL (format time.Duration).
+// Default 24 jam.
+func TrashPurgeInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("TRASH_PURGE_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+// TrashRetention membaca TRASH_RETENTION_DAYS, lama prestasi disimpan di
+// tempat sampah sebelum dihapus permanen. Nol berarti default service.
+func TrashRetention() time.Duration {
+	if n, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && n > 0 {
+		return time.Duration(n) * 24 * time.Hour
+	}
+	return 0
+}
+
+// CertExpiryCheckInterval membaca CERT_EXPIRY_CHECK_INTERVAL (format
+// time.Duration). Default 24 jam.
+func CertExpiryCheckInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("CERT_EXPIRY_CHECK_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+// OutboxRelayInterval membaca OUTBOX_RELAY_INTERVAL (format time.Duration).
+// Default 1 menit.
+func OutboxRelayInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("OUTBOX_RELAY_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return time.Minute
+}
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS achievement_status_history (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_ref_id   UUID NOT NULL REFERENCES achievement_references(id),
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    from_status          VARCHAR(20),
+    to_status            VARCHAR(20) NOT NULL,
+    actor_id             UUID NOT NULL,
+    actor_role           VARCHAR(50) NOT NULL,
+    note                 TEXT,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_status_history_mongo
+    ON achievement_status_history (mongo_achievement_id, created_at);
+
+-- Riwayat bersifat append-only: baris tidak boleh diubah atau dihapus.
+CREATE OR REPLACE FUNCTION achievement_status_history_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'achievement_status_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_achievement_status_history_append_only ON achievement_status_history;
+CREATE TRIGGER trg_achievement_status_history_append_only
+    BEFORE UPDATE OR DELETE ON achievement_status_history
+    FOR EACH ROW EXECUTE FUNCTION achievement_status_history_append_only();
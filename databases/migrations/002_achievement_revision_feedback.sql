@@ -0,0 +1,11 @@
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS revision_count    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS revision_feedback JSONB   NOT NULL DEFAULT '[]';
+
+-- Jika status disimpan sebagai enum, tambahkan nilai baru.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'achievement_status') THEN
+        ALTER TYPE achievement_status ADD VALUE IF NOT EXISTS 'needs_revision';
+    END IF;
+END $$;
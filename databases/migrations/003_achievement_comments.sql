@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS achievement_comments (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    parent_id            UUID REFERENCES achievement_comments(id),
+    author_id            UUID NOT NULL,
+    author_role          VARCHAR(50) NOT NULL,
+    body                 TEXT NOT NULL,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    edited_at            TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_comments_mongo
+    ON achievement_comments (mongo_achievement_id, created_at);
+
+-- Penanda baca per user per prestasi.
+CREATE TABLE IF NOT EXISTS achievement_comment_reads (
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    user_id              UUID NOT NULL,
+    last_read_at         TIMESTAMP NOT NULL,
+    PRIMARY KEY (mongo_achievement_id, user_id)
+);
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement:comment', 'achievement', 'comment', 'Berdiskusi pada prestasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name IN ('Admin', 'Mahasiswa', 'Dosen Wali')
+  AND p.name = 'achievement:comment'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS point_rubric_rules (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_type VARCHAR(50) NOT NULL,
+    field            VARCHAR(50) NOT NULL,
+    value            VARCHAR(100) NOT NULL DEFAULT '',
+    points           INTEGER NOT NULL,
+    description      TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (achievement_type, field, value)
+);
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS suggested_points     INTEGER,
+    ADD COLUMN IF NOT EXISTS points_justification TEXT;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('rubric:manage', 'rubric', 'manage', 'Mengelola rubrik poin prestasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin'
+  AND p.name = 'rubric:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS achievement_types (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code                 VARCHAR(50) NOT NULL UNIQUE,
+    name                 VARCHAR(100) NOT NULL,
+    description          TEXT NOT NULL DEFAULT '',
+    custom_fields_schema JSONB NOT NULL DEFAULT '{}'::jsonb,
+    default_points       INTEGER NOT NULL DEFAULT 0,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement_type:manage', 'achievement_type', 'manage', 'Mengelola tipe prestasi tambahan')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin'
+  AND p.name = 'achievement_type:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS verification_sla (
+    program_study VARCHAR(100) PRIMARY KEY,
+    due_days      INTEGER NOT NULL CHECK (due_days > 0),
+    escalate_days INTEGER NOT NULL CHECK (escalate_days > due_days),
+    updated_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS overdue_at   TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_submitted
+    ON achievement_references (submitted_at)
+    WHERE status = 'submitted';
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement:sla', 'achievement', 'sla', 'Mengelola SLA verifikasi dan melihat prestasi yang terlambat diverifikasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin'
+  AND p.name = 'achievement:sla'
+ON CONFLICT DO NOTHING;
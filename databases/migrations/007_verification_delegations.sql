@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS verification_delegations (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    advisor_id  UUID NOT NULL REFERENCES lecturers(id),
+    delegate_id UUID NOT NULL REFERENCES lecturers(id),
+    starts_at   TIMESTAMP NOT NULL,
+    ends_at     TIMESTAMP NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    created_by  UUID NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at  TIMESTAMP,
+    CHECK (advisor_id <> delegate_id),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_delegations_delegate
+    ON verification_delegations (delegate_id, starts_at, ends_at)
+    WHERE revoked_at IS NULL;
+
+-- Aksi yang dilakukan lewat delegasi mencatat dosen wali asli.
+ALTER TABLE achievement_status_history
+    ADD COLUMN IF NOT EXISTS on_behalf_of  UUID,
+    ADD COLUMN IF NOT EXISTS delegation_id UUID;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('delegation:manage', 'delegation', 'manage', 'Mendelegasikan hak verifikasi ke dosen lain')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name IN ('Admin', 'Dosen Wali')
+  AND p.name = 'delegation:manage'
+ON CONFLICT DO NOTHING;
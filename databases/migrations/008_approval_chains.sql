@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS approval_chains (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_type  VARCHAR(50) NOT NULL,
+    competition_level VARCHAR(50) NOT NULL DEFAULT '',
+    stages            JSONB NOT NULL,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (achievement_type, competition_level)
+);
+
+CREATE TABLE IF NOT EXISTS achievement_approvals (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_ref_id   UUID NOT NULL REFERENCES achievement_references(id),
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    stage_index          INTEGER NOT NULL,
+    stage_name           VARCHAR(50) NOT NULL,
+    approver_id          UUID NOT NULL,
+    approver_role        VARCHAR(50) NOT NULL,
+    on_behalf_of         UUID,
+    approved_at          TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_approvals_mongo
+    ON achievement_approvals (mongo_achievement_id, approved_at);
+
+-- Indeks tahap yang sedang menunggu persetujuan selama status submitted.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS approval_stage INTEGER NOT NULL DEFAULT 0;
+
+-- Prestasi lomba tingkat nasional dan internasional butuh persetujuan fakultas.
+INSERT INTO approval_chains (achievement_type, competition_level, stages)
+VALUES
+    ('competition', 'national',      '[{"name": "advisor", "role": "advisor"}, {"name": "faculty", "role": "Admin"}]'),
+    ('competition', 'international', '[{"name": "advisor", "role": "advisor"}, {"name": "faculty", "role": "Admin"}]')
+ON CONFLICT (achievement_type, competition_level) DO NOTHING;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('approval_chain:manage', 'approval_chain', 'manage', 'Mengelola rantai persetujuan prestasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin'
+  AND p.name = 'approval_chain:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+-- Status sebelum dihapus disimpan agar restore bisa mengembalikannya.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS deleted_at           TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS status_before_delete VARCHAR(20);
+
+UPDATE achievement_references
+SET deleted_at = updated_at
+WHERE status = 'deleted'
+  AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_deleted
+    ON achievement_references (deleted_at)
+    WHERE status = 'deleted';
@@ -0,0 +1,26 @@
+-- Prestasi tim: satu dokumen Mongo, satu baris referensi per anggota.
+-- Anggota pembuat entri bertanda owner; poin yang diterima anggota
+-- disimpan per baris karena bisa dibagi.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS team_role VARCHAR(10) NOT NULL DEFAULT 'owner',
+    ADD COLUMN IF NOT EXISTS points    INTEGER;
+
+ALTER TABLE achievement_references
+    DROP CONSTRAINT IF EXISTS achievement_references_mongo_achievement_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_achievement_references_mongo_student
+    ON achievement_references (mongo_achievement_id, student_id);
+
+-- Kebijakan poin prestasi tim per tipe: split = dibagi rata antar anggota,
+-- duplicate = setiap anggota menerima poin penuh.
+CREATE TABLE IF NOT EXISTS team_point_policies (
+    achievement_type VARCHAR(50) PRIMARY KEY,
+    policy           VARCHAR(10) NOT NULL CHECK (policy IN ('split', 'duplicate')),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO team_point_policies (achievement_type, policy)
+VALUES
+    ('competition', 'duplicate'),
+    ('publication', 'split')
+ON CONFLICT (achievement_type) DO NOTHING;
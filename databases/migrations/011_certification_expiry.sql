@@ -0,0 +1,15 @@
+-- Sertifikasi terverifikasi yang melewati validUntil ditandai expired_at;
+-- statusnya tetap verified agar riwayat dan poin tidak berubah.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS expired_at TIMESTAMP;
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement:certification', 'achievement', 'certification', 'Melihat sertifikasi mahasiswa yang akan atau sudah kedaluwarsa')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name IN ('Admin', 'Dosen Wali')
+  AND p.name = 'achievement:certification'
+ON CONFLICT DO NOTHING;
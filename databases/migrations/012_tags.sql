@@ -0,0 +1,24 @@
+-- Kosakata tag prestasi yang dikelola admin. Tag pada prestasi disimpan
+-- dengan nama kanonik; alias (mis. "AI" untuk "Artificial Intelligence")
+-- dipetakan ke nama tersebut saat prestasi dibuat atau diubah.
+CREATE TABLE IF NOT EXISTS tags (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       VARCHAR(100) NOT NULL,
+    aliases    TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_lower
+    ON tags (LOWER(name));
+
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('tag:manage', 'tag', 'manage', 'Mengelola kosakata tag prestasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin'
+  AND p.name = 'tag:manage'
+ON CONFLICT DO NOTHING;
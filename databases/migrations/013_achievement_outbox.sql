@@ -0,0 +1,23 @@
+-- Outbox untuk perubahan dokumen Mongo yang harus mengikuti perubahan di
+-- achievement_references. Baris outbox ditulis dalam transaksi yang sama
+-- dengan perubahan referensi, lalu diterapkan ke Mongo secara langsung atau
+-- oleh relay jika gagal.
+--
+-- discard_document dicatat sebelum dokumen baru disisipkan; jika referensi
+-- tidak pernah tersimpan, relay menghapus dokumen tersebut setelah
+-- available_at.
+CREATE TABLE IF NOT EXISTS achievement_outbox (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    operation            VARCHAR(30) NOT NULL
+        CHECK (operation IN ('discard_document', 'soft_delete_document', 'restore_document')),
+    attempts             INTEGER NOT NULL DEFAULT 0,
+    last_error           TEXT,
+    available_at         TIMESTAMP NOT NULL DEFAULT NOW(),
+    processed_at         TIMESTAMP,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_outbox_pending
+    ON achievement_outbox (available_at)
+    WHERE processed_at IS NULL;
@@ -0,0 +1,10 @@
+INSERT INTO permissions (name, resource, action, description)
+VALUES ('achievement:reconcile', 'achievement', 'reconcile', 'Mencocokkan dan memperbaiki data prestasi antara Postgres dan MongoDB')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin'
+  AND p.name = 'achievement:reconcile'
+ON CONFLICT DO NOTHING;
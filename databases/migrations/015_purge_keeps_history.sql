@@ -0,0 +1,6 @@
+-- Riwayat status append-only tetap disimpan setelah prestasi dihapus
+-- permanen dari tempat sampah, sehingga tidak boleh bergantung pada baris
+-- achievement_references yang ikut terhapus. Baris riwayat tetap bisa
+-- ditelusuri lewat mongo_achievement_id.
+ALTER TABLE achievement_status_history
+    DROP CONSTRAINT IF EXISTS achievement_status_history_achievement_ref_id_fkey;
@@ -0,0 +1,17 @@
+-- Notifikasi sertifikasi untuk mahasiswa dan dosen walinya. Satu baris per
+-- prestasi, penerima, dan jenis (expiring/expired) sehingga pemeriksa
+-- berkala tidak mengirim ulang notifikasi yang sama.
+CREATE TABLE IF NOT EXISTS certification_notifications (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mongo_achievement_id VARCHAR(24) NOT NULL,
+    student_id           UUID NOT NULL,
+    recipient_role       VARCHAR(20) NOT NULL,
+    recipient_id         UUID NOT NULL,
+    kind                 VARCHAR(20) NOT NULL,
+    valid_until          TIMESTAMP,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (mongo_achievement_id, recipient_role, recipient_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_certification_notifications_recipient
+    ON certification_notifications (recipient_role, recipient_id, created_at);
@@ -0,0 +1,7 @@
+-- Tipe prestasi dan tingkat lomba disalin ke referensi saat dikirim agar
+-- tahap persetujuan saat ini bisa dihitung dari approval_chains di SQL.
+-- Dokumen tidak bisa diubah selama submitted sehingga salinannya tetap
+-- sama. Referensi submitted lama diisi oleh BackfillApprovalKeys saat start.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS achievement_type  VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS competition_level VARCHAR(50) NOT NULL DEFAULT '';
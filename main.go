@@ -16,13 +16,17 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/arsmn/fiber-swagger/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 
 	_ "uas/docs"
+	"uas/app/repositories"
+	"uas/app/services"
 	"uas/config"
 	"uas/databases"
 	"uas/routes"
@@ -34,6 +38,28 @@ func main() {
 	databases.ConnectPostgres()
 	databases.ConnectMongoDB()
 
+	// go run . reconcile [-repair]
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(runReconcile(os.Args[2:]))
+	}
+
+	if err := repositories.EnsureAchievementSearchIndex(context.Background(), databases.MongoDB); err != nil {
+		log.Println("achievement search index:", err)
+	}
+	if err := repositories.EnsureAchievementRevisionIndex(context.Background(), databases.MongoDB); err != nil {
+		log.Println("achievement revision index:", err)
+	}
+
+	if n, err := services.BackfillApprovalKeys(
+		context.Background(),
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		repositories.NewAchievementReferenceRepo(databases.PSQL),
+	); err != nil {
+		log.Println("approval key backfill:", err)
+	} else if n > 0 {
+		log.Println("approval key backfill: filled", n, "achievements")
+	}
+
 	app := fiber.New()
 	
 	app.Get("/swagger/*", swagger.HandlerDefault) // default: http://localhost:3000/swagger/index.html
@@ -45,6 +71,37 @@ func main() {
 
 	routes.RegisterRoutes(app)
 
+	slaChecker := services.NewVerificationSLAService(
+		repositories.NewAchievementReferenceRepo(databases.PSQL),
+		repositories.NewVerificationSLARepository(databases.PSQL),
+	)
+	go slaChecker.RunChecker(context.Background(), config.SLACheckInterval())
+
+	trashPurger := services.NewTrashPurgeService(
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		repositories.NewAchievementReferenceRepo(databases.PSQL),
+		repositories.NewAchievementRevisionRepository(databases.MongoDB),
+		repositories.NewAchievementOutboxRepository(databases.PSQL),
+		config.TrashRetention(),
+	)
+	go trashPurger.RunPurge(context.Background(), config.TrashPurgeInterval())
+
+	certChecker := services.NewCertificationService(
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		repositories.NewAchievementReferenceRepo(databases.PSQL),
+		repositories.NewLecturerRepository(databases.PSQL),
+		repositories.NewStudentRepository(databases.PSQL),
+		repositories.NewCertificationNotificationRepository(databases.PSQL),
+	)
+	go certChecker.RunExpiryChecker(context.Background(), config.CertExpiryCheckInterval())
+
+	outboxRelay := services.NewAchievementOutboxService(
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		repositories.NewAchievementReferenceRepo(databases.PSQL),
+		repositories.NewAchievementOutboxRepository(databases.PSQL),
+	)
+	go outboxRelay.RunRelay(context.Background(), config.OutboxRelayInterval())
+
 	log.Println("Server running at http://localhost:3000")
 	log.Fatal(app.Listen(":3000"))
 }
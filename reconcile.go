@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"uas/app/repositories"
+	"uas/app/services"
+	"uas/databases"
+)
+
+// runReconcile menjalankan pencocokan achievement_references dengan dokumen
+// Mongo dari command line dan mencetak laporannya sebagai JSON. Exit code 1
+// berarti masih ada ketidakcocokan yang belum diperbaiki.
+func runReconcile(args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := fs.Bool("repair", false, "perbaiki ketidakcocokan yang bisa diperbaiki otomatis")
+	fs.Parse(args)
+
+	service := services.NewReconciliationService(
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		repositories.NewAchievementReferenceRepo(databases.PSQL),
+		repositories.NewStudentRepository(databases.PSQL),
+		repositories.NewAchievementHistoryRepository(databases.PSQL),
+	)
+
+	report, err := service.Reconcile(context.Background(), services.ReconcileSystemActor, *repair)
+	if err != nil {
+		log.Println("reconcile:", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Println("reconcile:", err)
+		return 1
+	}
+
+	if len(report.Issues) > report.Repaired {
+		return 1
+	}
+	return 0
+}
@@ -10,6 +10,10 @@ import (
 func registerAchievementRoutes(
 	api fiber.Router,
 	achService *services.AchievementService,
+	commentService *services.CommentService,
+	slaService *services.VerificationSLAService,
+	certService *services.CertificationService,
+	reconcileService *services.ReconciliationService,
 ) {
 
 	ach := api.Group(
@@ -23,6 +27,36 @@ func registerAchievementRoutes(
 		achService.ListAchievements(),
 	)
 
+	ach.Get(
+		"/search",
+		middleware.RequirePermission("achievement:list"),
+		achService.SearchAchievements(),
+	)
+
+	ach.Get(
+		"/trash",
+		middleware.RequirePermission("achievement:delete"),
+		achService.ListTrash(),
+	)
+
+	ach.Get(
+		"/overdue",
+		middleware.RequirePermission("achievement:sla"),
+		slaService.ListOverdueAchievements(),
+	)
+
+	ach.Get(
+		"/certifications/expiring",
+		middleware.RequirePermission("achievement:certification"),
+		certService.ListExpiringCertifications(),
+	)
+
+	ach.Get(
+		"/certifications/notifications",
+		middleware.RequirePermission("achievement:view"),
+		certService.ListCertificationNotifications(),
+	)
+
 	ach.Get(
 		"/:id",
 		middleware.RequirePermission("achievement:view"),
@@ -35,6 +69,24 @@ func registerAchievementRoutes(
 		achService.CreateAchievement(),
 	)
 
+	ach.Post(
+		"/bulk/verify",
+		middleware.RequirePermission("achievement:verify"),
+		achService.BulkVerifyAchievements(),
+	)
+
+	ach.Post(
+		"/bulk/reject",
+		middleware.RequirePermission("achievement:reject"),
+		achService.BulkRejectAchievements(),
+	)
+
+	ach.Post(
+		"/reconcile",
+		middleware.RequirePermission("achievement:reconcile"),
+		reconcileService.ReconcileAchievements(),
+	)
+
 	ach.Put(
 		"/:id",
 		middleware.RequirePermission("achievement:update"),
@@ -47,6 +99,12 @@ func registerAchievementRoutes(
 		achService.DeleteAchievement(),
 	)
 
+	ach.Post(
+		"/:id/restore",
+		middleware.RequirePermission("achievement:delete"),
+		achService.RestoreAchievement(),
+	)
+
 	ach.Post(
 		"/:id/submit",
 		middleware.RequirePermission("achievement:submit"),
@@ -65,17 +123,59 @@ func registerAchievementRoutes(
 		achService.RejectAchievement(),
 	)
 
+	ach.Post(
+		"/:id/request-revision",
+		middleware.RequirePermission("achievement:reject"),
+		achService.RequestRevision(),
+	)
+
 	ach.Get(
 		"/:id/history",
 		middleware.RequirePermission("achievement:view"),
 		achService.GetAchievementHistory(),
 	)
 
+	ach.Get(
+		"/:id/revisions",
+		middleware.RequirePermission("achievement:view"),
+		achService.GetAchievementRevisions(),
+	)
+
+	ach.Get(
+		"/:id/revisions/diff",
+		middleware.RequirePermission("achievement:view"),
+		achService.DiffAchievementRevisions(),
+	)
+
 	ach.Post(
 	"/:id/attachments",
 	middleware.RequirePermission("achievement:upload_attachment"),
 	achService.UploadAttachment(),
 )
 
+	ach.Get(
+		"/:id/comments",
+		middleware.RequirePermission("achievement:view"),
+		commentService.ListComments(),
+	)
+
+	ach.Post(
+		"/:id/comments",
+		middleware.RequirePermission("achievement:comment"),
+		commentService.CreateComment(),
+	)
+
+	ach.Post(
+		"/:id/comments/read",
+		middleware.RequirePermission("achievement:view"),
+		commentService.MarkCommentsRead(),
+	)
+
+	ach.Put(
+		"/:id/comments/:commentId",
+		middleware.RequirePermission("achievement:comment"),
+		commentService.UpdateComment(),
+	)
+
 }
 
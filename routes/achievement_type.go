@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerAchievementTypeRoutes(api fiber.Router, s *services.AchievementTypeService) {
+	types := api.Group(
+		"/achievement-types",
+		middleware.JWTProtected(),
+	)
+
+	types.Get("/", s.ListAchievementTypes())
+	types.Post("/", middleware.RequirePermission("achievement_type:manage"), s.CreateAchievementType())
+	types.Put("/:code", middleware.RequirePermission("achievement_type:manage"), s.UpdateAchievementType())
+	types.Delete("/:code", middleware.RequirePermission("achievement_type:manage"), s.DeleteAchievementType())
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerApprovalChainRoutes(api fiber.Router, s *services.ApprovalChainService) {
+	chains := api.Group(
+		"/approval-chains",
+		middleware.JWTProtected(),
+		middleware.RequirePermission("approval_chain:manage"),
+	)
+
+	chains.Get("/", s.ListApprovalChains())
+	chains.Put("/", s.UpsertApprovalChain())
+	chains.Delete("/:id", s.DeleteApprovalChain())
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerDelegationRoutes(api fiber.Router, s *services.DelegationService) {
+	delegations := api.Group(
+		"/delegations",
+		middleware.JWTProtected(),
+		middleware.RequirePermission("delegation:manage"),
+	)
+
+	delegations.Get("/", s.ListDelegations())
+	delegations.Post("/", s.CreateDelegation())
+	delegations.Delete("/:id", s.RevokeDelegation())
+}
@@ -13,17 +13,79 @@ func RegisterRoutes(app *fiber.App) {
 
 	registerAuthRoutes(api)
 
+	refRepo := repositories.NewAchievementReferenceRepo(databases.PSQL)
+	studentRepo := repositories.NewStudentRepository(databases.PSQL)
+	lecturerRepo := repositories.NewLecturerRepository(databases.PSQL)
+	rubricRepo := repositories.NewRubricRepository(databases.PSQL)
+	typeRepo := repositories.NewAchievementTypeRepository(databases.PSQL)
+	delegationRepo := repositories.NewVerificationDelegationRepository(databases.PSQL)
+	chainRepo := repositories.NewApprovalChainRepository(databases.PSQL)
+	policyRepo := repositories.NewTeamPointPolicyRepository(databases.PSQL)
+	tagRepo := repositories.NewTagRepository(databases.PSQL)
+
 	achievementService := services.NewAchievementService(
 		repositories.NewAchievementMongoRepository(databases.MongoDB),
-		repositories.NewAchievementReferenceRepo(databases.PSQL),
-		repositories.NewStudentRepository(databases.PSQL),
-		repositories.NewLecturerRepository(databases.PSQL),
+		refRepo,
+		studentRepo,
+		lecturerRepo,
+		repositories.NewAchievementHistoryRepository(databases.PSQL),
+		rubricRepo,
+		typeRepo,
+		delegationRepo,
+		chainRepo,
+		repositories.NewAchievementApprovalRepository(databases.PSQL),
+		repositories.NewAchievementRevisionRepository(databases.MongoDB),
+		policyRepo,
+		tagRepo,
+		repositories.NewAchievementOutboxRepository(databases.PSQL),
+	)
+	commentService := services.NewCommentService(
+		repositories.NewAchievementCommentRepository(databases.PSQL),
+		refRepo,
+		services.NewAchievementAccess(studentRepo, lecturerRepo, delegationRepo),
+	)
+	slaService := services.NewVerificationSLAService(
+		refRepo,
+		repositories.NewVerificationSLARepository(databases.PSQL),
+	)
+	certService := services.NewCertificationService(
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		refRepo,
+		lecturerRepo,
+		studentRepo,
+		repositories.NewCertificationNotificationRepository(databases.PSQL),
 	)
-	registerAchievementRoutes(api, achievementService)
+	reconcileService := services.NewReconciliationService(
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+		refRepo,
+		studentRepo,
+		repositories.NewAchievementHistoryRepository(databases.PSQL),
+	)
+	registerAchievementRoutes(api, achievementService, commentService, slaService, certService, reconcileService)
+	registerVerificationSLARoutes(api, slaService)
+	registerDelegationRoutes(api, services.NewDelegationService(delegationRepo, lecturerRepo))
+	registerApprovalChainRoutes(api, services.NewApprovalChainService(chainRepo))
+	registerTeamPointPolicyRoutes(api, services.NewTeamPointPolicyService(policyRepo))
+	registerRubricRoutes(
+		api,
+		services.NewRubricService(rubricRepo),
+		services.NewRecalculationService(
+			repositories.NewAchievementMongoRepository(databases.MongoDB),
+			refRepo,
+			rubricRepo,
+			typeRepo,
+			policyRepo,
+		),
+	)
+	registerAchievementTypeRoutes(api, services.NewAchievementTypeService(
+		typeRepo,
+		repositories.NewAchievementMongoRepository(databases.MongoDB),
+	))
+	registerTagRoutes(api, services.NewTagService(tagRepo))
 
 	reportRepo := repositories.NewReportRepository(databases.PSQL)
 	mongoReportRepo := repositories.NewAchievementMongoReportRepository(databases.MongoDB)
-	reportService := services.NewReportService(reportRepo, mongoReportRepo)
+	reportService := services.NewReportService(reportRepo, mongoReportRepo, tagRepo)
 
 	registerReportRoutes(api, reportService)
 
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerRubricRoutes(
+	api fiber.Router,
+	s *services.RubricService,
+	recalc *services.RecalculationService,
+) {
+	rubrics := api.Group(
+		"/rubrics",
+		middleware.JWTProtected(),
+		middleware.RequirePermission("rubric:manage"),
+	)
+
+	rubrics.Post("/recalculate", recalc.RecalculatePoints())
+	rubrics.Get("/", s.ListRubricRules())
+	rubrics.Post("/", s.CreateRubricRule())
+	rubrics.Put("/:id", s.UpdateRubricRule())
+	rubrics.Delete("/:id", s.DeleteRubricRule())
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerTagRoutes(api fiber.Router, s *services.TagService) {
+	tags := api.Group(
+		"/tags",
+		middleware.JWTProtected(),
+	)
+
+	tags.Get("/", s.ListTags())
+	tags.Post("/", middleware.RequirePermission("tag:manage"), s.CreateTag())
+	tags.Put("/:id", middleware.RequirePermission("tag:manage"), s.UpdateTag())
+	tags.Delete("/:id", middleware.RequirePermission("tag:manage"), s.DeleteTag())
+}
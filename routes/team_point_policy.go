@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerTeamPointPolicyRoutes(api fiber.Router, s *services.TeamPointPolicyService) {
+	policies := api.Group(
+		"/team-point-policies",
+		middleware.JWTProtected(),
+		middleware.RequirePermission("achievement_type:manage"),
+	)
+
+	policies.Get("/", s.ListTeamPointPolicies())
+	policies.Put("/", s.UpsertTeamPointPolicy())
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"uas/app/services"
+	"uas/middleware"
+)
+
+func registerVerificationSLARoutes(api fiber.Router, s *services.VerificationSLAService) {
+	sla := api.Group(
+		"/verification-sla",
+		middleware.JWTProtected(),
+		middleware.RequirePermission("achievement:sla"),
+	)
+
+	sla.Get("/", s.ListVerificationSLA())
+	sla.Put("/", s.UpsertVerificationSLA())
+	sla.Delete("/", s.DeleteVerificationSLA())
+}